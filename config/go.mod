@@ -0,0 +1,5 @@
+module github.com/purposeinplay/go-commons/config
+
+go 1.21
+
+require github.com/matryer/is v1.4.1
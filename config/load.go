@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadInto errors.
+var (
+	ErrInvalidTarget    = errors.New("target must be a non nil pointer to a struct")
+	ErrRequiredKey      = errors.New("required key not found")
+	ErrUnsupportedField = errors.New("unsupported field type")
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadInto sets the fields of the struct pointed by v to the values
+// returned by the loader.
+//
+// The key of a field is given by its env tag, followed by an optional
+// ",required" flag, while its default value is given by the default tag:
+//
+//	type Config struct {
+//		Port     int           `env:"PORT" default:"8080"`
+//		DSN      string        `env:"DATABASE_URL,required"`
+//		Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+//		Brokers  []string      `env:"BROKERS"`
+//	}
+//
+// Nested structs without an env tag are loaded recursively.
+func LoadInto(v any, loader Loader) error {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	return loadStruct(rv.Elem(), loader)
+}
+
+func loadStruct(rv reflect.Value, loader Loader) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+
+		if !hasTag {
+			if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+				if err := loadStruct(rv.Field(i), loader); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		key, flags, _ := strings.Cut(tag, ",")
+
+		value, ok := loader.Load(key)
+		if !ok {
+			value, ok = field.Tag.Lookup("default")
+		}
+
+		if !ok {
+			if flags == "required" {
+				return fmt.Errorf("%w: %s", ErrRequiredKey, key)
+			}
+
+			continue
+		}
+
+		if err := setField(rv.Field(i), value); err != nil {
+			return fmt.Errorf("set field %s from %s: %w", field.Name, key, err)
+		}
+	}
+
+	return nil
+}
+
+// nolint: gocyclo // a case for every supported kind.
+func setField(fv reflect.Value, value string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("parse duration: %w", err)
+		}
+
+		fv.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parse bool: %w", err)
+		}
+
+		fv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse int: %w", err)
+		}
+
+		fv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse uint: %w", err)
+		}
+
+		fv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("parse float: %w", err)
+		}
+
+		fv.SetFloat(f)
+
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s", ErrUnsupportedField, fv.Type())
+		}
+
+		var items []string
+
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		fv.Set(reflect.ValueOf(items))
+
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedField, fv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/config"
+)
+
+func TestLoadInto(t *testing.T) {
+	t.Parallel()
+
+	type database struct {
+		DSN string `env:"DATABASE_URL,required"`
+	}
+
+	type cfg struct {
+		Port     int           `env:"PORT" default:"8080"`
+		Debug    bool          `env:"DEBUG"`
+		Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+		Brokers  []string      `env:"BROKERS"`
+		Database database
+	}
+
+	t.Run("MultiLoader", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		envFile := filepath.Join(t.TempDir(), ".env")
+
+		err := os.WriteFile(envFile, []byte(
+			"# comment\nexport APP_DEBUG=true\nAPP_PORT=9000\nAPP_DATABASE_URL=\"postgres://file\"\n",
+		), 0o600)
+		i.NoErr(err)
+
+		loader := config.NewMultiLoader(
+			config.MapLoader(map[string]string{
+				"DATABASE_URL": "postgres://map",
+				"BROKERS":      "a:9092, b:9092",
+			}),
+			config.PrefixedLoader("APP_", config.FileLoader(envFile)),
+		)
+
+		var c cfg
+
+		i.NoErr(config.LoadInto(&c, loader))
+		i.Equal(9000, c.Port)
+		i.True(c.Debug)
+		i.Equal(5*time.Second, c.Timeout)
+		i.Equal([]string{"a:9092", "b:9092"}, c.Brokers)
+		i.Equal("postgres://map", c.Database.DSN)
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		var c cfg
+
+		err := config.LoadInto(&c, config.MapLoader(nil))
+		i.True(errors.Is(err, config.ErrRequiredKey))
+	})
+
+	t.Run("InvalidTarget", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		err := config.LoadInto(cfg{}, config.EnvLoader())
+		i.True(errors.Is(err, config.ErrInvalidTarget))
+	})
+}
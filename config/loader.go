@@ -0,0 +1,137 @@
+// Package config loads service configuration from environment
+// variables, .env files and other key value sources.
+package config
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Loader is the interface that wraps the basic Load method.
+type Loader interface {
+	// Load returns the value of the key and whether it was found.
+	Load(key string) (string, bool)
+}
+
+// LoaderFunc is an adapter to allow the use of
+// ordinary functions as a Loader.
+type LoaderFunc func(key string) (string, bool)
+
+// Load calls f(key).
+func (f LoaderFunc) Load(key string) (string, bool) {
+	return f(key)
+}
+
+// EnvLoader returns a Loader that reads the environment variables.
+func EnvLoader() Loader {
+	return LoaderFunc(os.LookupEnv)
+}
+
+// MapLoader returns a Loader that reads the given map.
+// It is meant for tests.
+func MapLoader(m map[string]string) Loader {
+	return LoaderFunc(func(key string) (string, bool) {
+		v, ok := m[key]
+
+		return v, ok
+	})
+}
+
+// PrefixedLoader returns a Loader that looks up the inner Loader
+// for the key prefixed with prefix, allowing "APP_PORT" to be
+// loaded as "PORT".
+func PrefixedLoader(prefix string, inner Loader) Loader {
+	return LoaderFunc(func(key string) (string, bool) {
+		return inner.Load(prefix + key)
+	})
+}
+
+// FileLoader returns a Loader that reads the KEY=VALUE lines
+// of the .env file at path.
+//
+// The file is read on the first Load. A missing or unreadable
+// file is treated as an empty one.
+func FileLoader(path string) Loader {
+	var (
+		once   sync.Once
+		values map[string]string
+	)
+
+	return LoaderFunc(func(key string) (string, bool) {
+		once.Do(func() {
+			values = readEnvFile(path)
+		})
+
+		v, ok := values[key]
+
+		return v, ok
+	})
+}
+
+func readEnvFile(path string) map[string]string {
+	values := make(map[string]string)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+
+		if len(value) >= 2 &&
+			(value[0] == '"' && value[len(value)-1] == '"' ||
+				value[0] == '\'' && value[len(value)-1] == '\'') {
+			value = value[1 : len(value)-1]
+		}
+
+		values[strings.TrimSpace(key)] = value
+	}
+
+	return values
+}
+
+var _ Loader = (*MultiLoader)(nil)
+
+// MultiLoader looks up several sources in order.
+type MultiLoader struct {
+	sources []Loader
+}
+
+// NewMultiLoader creates a MultiLoader, the sources passed first
+// take precedence over the ones passed last.
+func NewMultiLoader(sources ...Loader) *MultiLoader {
+	return &MultiLoader{
+		sources: sources,
+	}
+}
+
+// Load returns the value of the key from the first source that has it.
+func (l *MultiLoader) Load(key string) (string, bool) {
+	for _, source := range l.sources {
+		if v, ok := source.Load(key); ok {
+			return v, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,453 @@
+// Package auth provides authentication primitives for grpc servers.
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// JWKS errors.
+var (
+	ErrKeyNotFound          = errors.New("key not found")
+	ErrUnsupportedKeyType   = errors.New("unsupported key type")
+	ErrUnexpectedStatusCode = errors.New("unexpected status code")
+)
+
+const (
+	authorizationHeader = "authorization"
+	bearerScheme        = "bearer"
+)
+
+// A JWKSOption configures the JWKS interceptor.
+type JWKSOption interface {
+	apply(*jwksOptions)
+}
+
+type funcJWKSOption struct {
+	f func(*jwksOptions)
+}
+
+func (fo *funcJWKSOption) apply(o *jwksOptions) {
+	fo.f(o)
+}
+
+func newFuncJWKSOption(f func(*jwksOptions)) *funcJWKSOption {
+	return &funcJWKSOption{
+		f: f,
+	}
+}
+
+type jwksOptions struct {
+	refreshInterval    time.Duration
+	minRefetchInterval time.Duration
+	keyRotation        bool
+	httpClient         *http.Client
+}
+
+// WithRefreshInterval configures how often the JWKS is re-fetched.
+//
+// The JWKS is re-fetched lazily, by the first request validated
+// once the cached set is older than d.
+func WithRefreshInterval(d time.Duration) JWKSOption {
+	return newFuncJWKSOption(func(o *jwksOptions) {
+		o.refreshInterval = d
+	})
+}
+
+// WithKeyRotation re-fetches the JWKS whenever a token is signed
+// with a key id that is not present in the cached set, at most once
+// per min refetch interval, see WithMinRefetchInterval.
+func WithKeyRotation() JWKSOption {
+	return newFuncJWKSOption(func(o *jwksOptions) {
+		o.keyRotation = true
+	})
+}
+
+// WithMinRefetchInterval configures the minimum delay between two
+// fetches of the JWKS, so the tokens signed with unknown key ids
+// can't flood the JWKS endpoint. Zero disables the limit.
+// Defaults to 1m.
+func WithMinRefetchInterval(d time.Duration) JWKSOption {
+	return newFuncJWKSOption(func(o *jwksOptions) {
+		o.minRefetchInterval = d
+	})
+}
+
+// WithHTTPClient configures the http client used to fetch the JWKS.
+func WithHTTPClient(c *http.Client) JWKSOption {
+	return newFuncJWKSOption(func(o *jwksOptions) {
+		o.httpClient = c
+	})
+}
+
+func defaultJWKSOptions() jwksOptions {
+	const (
+		refreshInterval    = time.Hour
+		minRefetchInterval = time.Minute
+		httpTimeout        = 10 * time.Second
+	)
+
+	return jwksOptions{
+		refreshInterval:    refreshInterval,
+		minRefetchInterval: minRefetchInterval,
+		keyRotation:        false,
+		httpClient:         &http.Client{Timeout: httpTimeout},
+	}
+}
+
+type ctxClaimsKey struct{}
+
+// ClaimsFromContext returns the claims of the token validated
+// by the JWKS interceptor.
+func ClaimsFromContext(ctx context.Context) (jwt.MapClaims, bool) {
+	claims, ok := ctx.Value(ctxClaimsKey{}).(jwt.MapClaims)
+	return claims, ok
+}
+
+// NewJWKSInterceptor returns an interceptor that validates the bearer
+// token found in the incoming metadata against the keys published at
+// jwksURL.
+//
+// The key set is fetched when the interceptor is created and re-fetched,
+// by the first request validated, once it becomes older than the refresh
+// interval. No fetch runs in the background.
+//
+// Only the tokens signed with an algorithm of the fetched keys are accepted.
+func NewJWKSInterceptor(
+	jwksURL string,
+	opts ...JWKSOption,
+) grpc.UnaryServerInterceptor {
+	o := defaultJWKSOptions()
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	keySet := &jwks{
+		url:     jwksURL,
+		options: o,
+	}
+
+	// A failed fetch leaves the set empty, the keys are fetched
+	// again on the first request.
+	_ = keySet.refresh(context.Background(), false)
+
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		token, err := bearerTokenFromCtx(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		claims := jwt.MapClaims{}
+
+		if _, err := jwt.ParseWithClaims(
+			token,
+			claims,
+			func(t *jwt.Token) (any, error) {
+				kid, _ := t.Header["kid"].(string)
+
+				return keySet.key(ctx, kid)
+			},
+			jwt.WithValidMethods(keySet.validMethods()),
+		); err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token.")
+		}
+
+		return handler(context.WithValue(ctx, ctxClaimsKey{}, claims), req)
+	}
+}
+
+// bearerTokenFromCtx extracts the token from the
+// "authorization: Bearer <token>" incoming metadata.
+func bearerTokenFromCtx(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errors.New("metadata not found")
+	}
+
+	values := md.Get(authorizationHeader)
+	if len(values) == 0 {
+		return "", errors.New("authorization token not present")
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, bearerScheme) || token == "" {
+		return "", errors.New("bad authorization scheme")
+	}
+
+	return token, nil
+}
+
+type jwks struct {
+	url     string
+	options jwksOptions
+
+	// deduplicates the concurrent fetches.
+	group singleflight.Group
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	methods   []string
+	fetchedAt time.Time
+	// the time of the last fetch, successful or not.
+	attemptedAt time.Time
+}
+
+func (s *jwks) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.RLock()
+	k, ok := s.keys[kid]
+	stale := time.Since(s.fetchedAt) > s.options.refreshInterval
+	s.mu.RUnlock()
+
+	// the unknown key ids trigger a fetch, rate limited, only
+	// when the keys may have been rotated.
+	if !stale && (ok || !s.options.keyRotation) {
+		return keyOrNotFound(k, ok)
+	}
+
+	if err := s.refreshRateLimited(ctx); err != nil {
+		return nil, fmt.Errorf("refresh jwks: %w", err)
+	}
+
+	s.mu.RLock()
+	k, ok = s.keys[kid]
+	s.mu.RUnlock()
+
+	return keyOrNotFound(k, ok)
+}
+
+func keyOrNotFound(k crypto.PublicKey, ok bool) (crypto.PublicKey, error) {
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	return k, nil
+}
+
+// validMethods returns the signing methods of the fetched keys.
+func (s *jwks) validMethods() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.methods
+}
+
+// refreshRateLimited fetches the keys unless they were fetched,
+// or attempted to, less than the min refetch interval ago.
+func (s *jwks) refreshRateLimited(ctx context.Context) error {
+	return s.refresh(ctx, true)
+}
+
+// refresh fetches the keys, sharing the fetch with
+// the concurrent callers.
+func (s *jwks) refresh(ctx context.Context, rateLimited bool) error {
+	// the fetch is shared, it isn't canceled with the
+	// context of the request that started it.
+	_, err, _ := s.group.Do(strconv.FormatBool(rateLimited), func() (any, error) {
+		return nil, s.fetch(context.WithoutCancel(ctx), rateLimited)
+	})
+
+	return err
+}
+
+func (s *jwks) fetch(ctx context.Context, rateLimited bool) error {
+	s.mu.RLock()
+	limited := rateLimited && time.Since(s.attemptedAt) < s.options.minRefetchInterval
+	s.mu.RUnlock()
+
+	if limited {
+		return nil
+	}
+
+	keys, err := s.fetchKeys(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attemptedAt = time.Now()
+
+	if err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.methods = signingMethods(keys)
+	s.fetchedAt = s.attemptedAt
+
+	return nil
+}
+
+func (s *jwks) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new request: %w", err)
+	}
+
+	resp, err := s.options.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get jwks: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, k := range set.Keys {
+		publicKey, err := k.publicKey()
+		if err != nil {
+			// skip the keys that can't be used to verify signatures.
+			continue
+		}
+
+		keys[k.Kid] = publicKey
+	}
+
+	return keys, nil
+}
+
+// signingMethods returns the algorithms the keys can verify
+// the signatures of.
+func signingMethods(keys map[string]crypto.PublicKey) []string {
+	var rsaKeys bool
+
+	curves := make(map[string]struct{})
+
+	for _, k := range keys {
+		switch k := k.(type) {
+		case *rsa.PublicKey:
+			rsaKeys = true
+
+		case *ecdsa.PublicKey:
+			curves[k.Curve.Params().Name] = struct{}{}
+		}
+	}
+
+	// a nil slice would disable the validation of the method.
+	methods := make([]string, 0)
+
+	if rsaKeys {
+		methods = append(methods,
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodRS384.Alg(),
+			jwt.SigningMethodRS512.Alg(),
+			jwt.SigningMethodPS256.Alg(),
+			jwt.SigningMethodPS384.Alg(),
+			jwt.SigningMethodPS512.Alg(),
+		)
+	}
+
+	for curve, method := range map[string]*jwt.SigningMethodECDSA{
+		elliptic.P256().Params().Name: jwt.SigningMethodES256,
+		elliptic.P384().Params().Name: jwt.SigningMethodES384,
+		elliptic.P521().Params().Name: jwt.SigningMethodES512,
+	} {
+		if _, ok := curves[curve]; ok {
+			methods = append(methods, method.Alg())
+		}
+	}
+
+	return methods
+}
+
+// jsonWebKey represents a public key as described in RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode modulus: %w", err)
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode exponent: %w", err)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: curve %q", ErrUnsupportedKeyType, k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x: %w", err)
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y: %w", err)
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedKeyType, k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,202 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWKSInterceptor(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	i.NoErr(err)
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	i.NoErr(err)
+
+	var rotated atomic.Bool
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			keys := []map[string]string{rsaJWK("old", &oldKey.PublicKey)}
+
+			if rotated.Load() {
+				keys = append(keys, rsaJWK("new", &newKey.PublicKey))
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+		},
+	))
+	t.Cleanup(jwksServer.Close)
+
+	interceptor := auth.NewJWKSInterceptor(
+		jwksServer.URL,
+		auth.WithKeyRotation(),
+		auth.WithMinRefetchInterval(0),
+	)
+
+	handler := func(ctx context.Context, _ any) (any, error) {
+		claims, ok := auth.ClaimsFromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Internal, "no claims")
+		}
+
+		return claims["sub"], nil
+	}
+
+	call := func(token string) (any, error) {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("authorization", "Bearer "+token),
+		)
+
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		i := is.New(t)
+
+		resp, err := call(signToken(t, oldKey, "old", time.Hour))
+		i.NoErr(err)
+		i.Equal("user", resp)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		i := is.New(t)
+
+		_, err := call(signToken(t, oldKey, "old", -time.Hour))
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("InvalidMethod", func(t *testing.T) {
+		i := is.New(t)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "user",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+
+		token.Header["kid"] = "old"
+
+		signed, err := token.SignedString([]byte("secret"))
+		i.NoErr(err)
+
+		_, err = call(signed)
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		i := is.New(t)
+
+		_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Rotation", func(t *testing.T) {
+		i := is.New(t)
+
+		rotated.Store(true)
+
+		resp, err := call(signToken(t, newKey, "new", time.Hour))
+		i.NoErr(err)
+		i.Equal("user", resp)
+	})
+}
+
+func TestJWKSInterceptorRefetchRateLimit(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	i.NoErr(err)
+
+	var fetches atomic.Int32
+
+	jwksServer := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, _ *http.Request) {
+			fetches.Add(1)
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"keys": []map[string]string{rsaJWK("kid", &key.PublicKey)},
+			})
+		},
+	))
+	t.Cleanup(jwksServer.Close)
+
+	interceptor := auth.NewJWKSInterceptor(jwksServer.URL, auth.WithKeyRotation())
+
+	var wg sync.WaitGroup
+
+	for n := 0; n < 10; n++ {
+		wg.Add(1)
+
+		go func(kid string) {
+			defer wg.Done()
+
+			ctx := metadata.NewIncomingContext(
+				context.Background(),
+				metadata.Pairs("authorization", "Bearer "+signToken(t, key, kid, time.Hour)),
+			)
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+				return nil, nil
+			})
+			i.Equal(codes.Unauthenticated, status.Code(err))
+		}(strconv.Itoa(n))
+	}
+
+	wg.Wait()
+
+	// the unknown key ids don't trigger a fetch within
+	// the min refetch interval of the initial one.
+	i.Equal(int32(1), fetches.Load())
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, ttl time.Duration) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub": "user",
+		"exp": time.Now().Add(ttl).Unix(),
+	})
+
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %s", err)
+	}
+
+	return signed
+}
+
+func rsaJWK(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA",
+		"kid": kid,
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
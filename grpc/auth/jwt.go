@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"reflect"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A JWTOption configures the JWT interceptors.
+type JWTOption interface {
+	apply(*jwtOptions)
+}
+
+type funcJWTOption struct {
+	f func(*jwtOptions)
+}
+
+func (fo *funcJWTOption) apply(o *jwtOptions) {
+	fo.f(o)
+}
+
+func newFuncJWTOption(f func(*jwtOptions)) *funcJWTOption {
+	return &funcJWTOption{
+		f: f,
+	}
+}
+
+type jwtOptions struct {
+	skipMethods   []string
+	parserOptions []jwt.ParserOption
+}
+
+// SkipMethods lets the calls of the given full methods, e.g.
+// "/grpc.health.v1.Health/Check", through without a token.
+func SkipMethods(methods ...string) JWTOption {
+	return newFuncJWTOption(func(o *jwtOptions) {
+		o.skipMethods = append(o.skipMethods, methods...)
+	})
+}
+
+// ValidMethods rejects the tokens not signed with one of the given
+// signing methods, e.g. "RS256". Without it, the tokens signed with
+// any method the keys returned by keyFunc fit are accepted.
+func ValidMethods(methods ...string) JWTOption {
+	return newFuncJWTOption(func(o *jwtOptions) {
+		o.parserOptions = append(o.parserOptions, jwt.WithValidMethods(methods))
+	})
+}
+
+// ParserOptions configures the parser of the tokens,
+// e.g. with jwt.WithAudience or jwt.WithLeeway.
+func ParserOptions(opts ...jwt.ParserOption) JWTOption {
+	return newFuncJWTOption(func(o *jwtOptions) {
+		o.parserOptions = append(o.parserOptions, opts...)
+	})
+}
+
+type ctxJWTClaimsKey struct{}
+
+// JWTClaimsFromContext returns the claims of the token validated by the
+// JWT interceptors. They have the type of the claims passed to
+// NewJWTAuthInterceptor.
+func JWTClaimsFromContext(ctx context.Context) (jwt.Claims, bool) {
+	claims, ok := ctx.Value(ctxJWTClaimsKey{}).(jwt.Claims)
+	return claims, ok
+}
+
+// NewJWTAuthInterceptor returns an interceptor that validates the bearer
+// token found in the incoming metadata with the keys returned by keyFunc.
+//
+// The token is parsed into a new value of the type of claims, e.g.
+// jwt.MapClaims or a pointer to a custom claims struct, retrieved by the
+// handlers using JWTClaimsFromContext.
+func NewJWTAuthInterceptor(
+	keyFunc jwt.Keyfunc,
+	claims jwt.Claims,
+	opts ...JWTOption,
+) grpc.UnaryServerInterceptor {
+	authenticate := newJWTAuthenticator(keyFunc, claims, opts)
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewJWTAuthStreamInterceptor is the stream counterpart
+// of NewJWTAuthInterceptor.
+func NewJWTAuthStreamInterceptor(
+	keyFunc jwt.Keyfunc,
+	claims jwt.Claims,
+	opts ...JWTOption,
+) grpc.StreamServerInterceptor {
+	authenticate := newJWTAuthenticator(keyFunc, claims, opts)
+
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		wrapped := grpcmiddleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+
+		return handler(srv, wrapped)
+	}
+}
+
+// newJWTAuthenticator returns a function that authenticates the calls of
+// the method, returning the context holding the claims of the token.
+func newJWTAuthenticator(
+	keyFunc jwt.Keyfunc,
+	claims jwt.Claims,
+	opts []JWTOption,
+) func(ctx context.Context, method string) (context.Context, error) {
+	var o jwtOptions
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(ctx context.Context, method string) (context.Context, error) {
+		if slices.Contains(o.skipMethods, method) {
+			return ctx, nil
+		}
+
+		token, err := bearerTokenFromCtx(ctx)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		parsedClaims := newClaims(claims)
+
+		if _, err := jwt.ParseWithClaims(token, parsedClaims, keyFunc, o.parserOptions...); err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token.")
+		}
+
+		return context.WithValue(ctx, ctxJWTClaimsKey{}, parsedClaims), nil
+	}
+}
+
+// newClaims returns an empty value of the type of claims,
+// so concurrent requests don't share the parsed claims.
+func newClaims(claims jwt.Claims) jwt.Claims {
+	if _, ok := claims.(jwt.MapClaims); ok {
+		return jwt.MapClaims{}
+	}
+
+	t := reflect.TypeOf(claims)
+
+	if t.Kind() == reflect.Ptr {
+		// nolint: forcetypeassert // *T implements jwt.Claims like the claims.
+		return reflect.New(t.Elem()).Interface().(jwt.Claims)
+	}
+
+	// claims of non pointer types can't be filled, parse into a map.
+	return jwt.MapClaims{}
+}
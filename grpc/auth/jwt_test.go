@@ -0,0 +1,158 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestJWTAuthInterceptor(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+
+	keyFunc := func(*jwt.Token) (any, error) {
+		return secret, nil
+	}
+
+	interceptor := auth.NewJWTAuthInterceptor(
+		keyFunc,
+		&jwt.RegisteredClaims{},
+		auth.SkipMethods("/grpc.health.v1.Health/Check"),
+	)
+
+	handler := func(ctx context.Context, _ any) (any, error) {
+		claims, ok := auth.JWTClaimsFromContext(ctx)
+		if !ok {
+			return "anonymous", nil
+		}
+
+		return claims.GetSubject()
+	}
+
+	call := func(method, token string) (any, error) {
+		ctx := context.Background()
+
+		if token != "" {
+			ctx = metadata.NewIncomingContext(
+				ctx,
+				metadata.Pairs("authorization", "Bearer "+token),
+			)
+		}
+
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	}
+
+	signHS256 := func(t *testing.T, key []byte, ttl time.Duration) string {
+		t.Helper()
+
+		i := is.New(t)
+
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   "user",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		}).SignedString(key)
+		i.NoErr(err)
+
+		return token
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		resp, err := call("/users.Users/Get", signHS256(t, secret, time.Hour))
+		i.NoErr(err)
+		i.Equal("user", resp)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := call("/users.Users/Get", signHS256(t, secret, -time.Hour))
+		i.Equal(codes.Unauthenticated, status.Code(err))
+		i.Equal("invalid token.", status.Convert(err).Message())
+	})
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := call("/users.Users/Get", signHS256(t, []byte("other"), time.Hour))
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("MissingToken", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := call("/users.Users/Get", "")
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("InvalidSigningMethod", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		interceptor := auth.NewJWTAuthInterceptor(
+			keyFunc,
+			&jwt.RegisteredClaims{},
+			auth.ValidMethods(jwt.SigningMethodHS512.Name),
+		)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("authorization", "Bearer "+signHS256(t, secret, time.Hour)),
+		)
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}, handler)
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("ParserOptions", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		interceptor := auth.NewJWTAuthInterceptor(
+			keyFunc,
+			&jwt.RegisteredClaims{},
+			auth.ValidMethods(jwt.SigningMethodHS256.Name),
+			auth.ParserOptions(jwt.WithLeeway(2*time.Hour)),
+		)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("authorization", "Bearer "+signHS256(t, secret, -time.Hour)),
+		)
+
+		// the expired token is within the leeway.
+		resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}, handler)
+		i.NoErr(err)
+		i.Equal("user", resp)
+	})
+
+	t.Run("SkippedMethod", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		resp, err := call("/grpc.health.v1.Health/Check", "")
+		i.NoErr(err)
+		i.Equal("anonymous", resp)
+	})
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+// NewIPFilterTapHandler returns a tap handler that rejects the streams
+// of peers whose IP is not part of the allowed networks.
+func NewIPFilterTapHandler(allowed []net.IPNet) tap.ServerInHandle {
+	return func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.PermissionDenied, "unknown peer")
+		}
+
+		ip := peerIP(p.Addr)
+
+		for _, ipNet := range allowed {
+			if ip != nil && ipNet.Contains(ip) {
+				return ctx, nil
+			}
+		}
+
+		return nil, status.Error(codes.PermissionDenied, "ip not allowed")
+	}
+}
+
+// NewClientCertTapHandler returns a tap handler that rejects the streams
+// of peers that did not present a TLS client certificate.
+func NewClientCertTapHandler() tap.ServerInHandle {
+	return func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "unknown peer")
+		}
+
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "client certificate required")
+		}
+
+		return ctx, nil
+	}
+}
+
+func peerIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+
+	case *net.UDPAddr:
+		return a.IP
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	return net.ParseIP(host)
+}
@@ -0,0 +1,60 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+func TestIPFilterTapHandler(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	_, allowed, err := net.ParseCIDR("10.0.0.0/8")
+	i.NoErr(err)
+
+	handler := auth.NewIPFilterTapHandler([]net.IPNet{*allowed})
+
+	peerCtx := func(ip net.IP) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: ip, Port: 1234},
+		})
+	}
+
+	_, err = handler(peerCtx(net.IPv4(10, 1, 2, 3)), &tap.Info{})
+	i.NoErr(err)
+
+	_, err = handler(peerCtx(net.IPv4(192, 168, 0, 1)), &tap.Info{})
+	i.Equal(codes.PermissionDenied, status.Code(err))
+}
+
+func TestClientCertTapHandler(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	handler := auth.NewClientCertTapHandler()
+
+	_, err := handler(peer.NewContext(context.Background(), &peer.Peer{}), &tap.Info{})
+	i.Equal(codes.Unauthenticated, status.Code(err))
+
+	_, err = handler(peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{}},
+			},
+		},
+	}), &tap.Info{})
+	i.NoErr(err)
+}
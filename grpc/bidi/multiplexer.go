@@ -0,0 +1,286 @@
+// Package bidi provides request/response semantics over
+// bidirectional grpc streams.
+package bidi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// DefaultCorrelationField is the name of the field carrying the
+// correlation id of the requests and responses, unless
+// WithCorrelationField is used.
+const DefaultCorrelationField = "correlation_id"
+
+var (
+	// ErrInvalidCorrelationField is returned when a message has no
+	// string field named after the correlation field.
+	ErrInvalidCorrelationField = errors.New("invalid correlation field")
+
+	// ErrMultiplexerClosed is returned by Send once the
+	// multiplexer is closed.
+	ErrMultiplexerClosed = errors.New("multiplexer closed")
+)
+
+// A MultiplexerOption configures the Multiplexer.
+type MultiplexerOption interface {
+	apply(*multiplexerOptions)
+}
+
+type funcMultiplexerOption struct {
+	f func(*multiplexerOptions)
+}
+
+func (fo *funcMultiplexerOption) apply(o *multiplexerOptions) {
+	fo.f(o)
+}
+
+func newFuncMultiplexerOption(f func(*multiplexerOptions)) *funcMultiplexerOption {
+	return &funcMultiplexerOption{
+		f: f,
+	}
+}
+
+type multiplexerOptions struct {
+	correlationField protoreflect.Name
+	callOptions      []grpc.CallOption
+}
+
+// WithCorrelationField sets the name of the string field carrying the
+// correlation id in both the requests and the responses.
+func WithCorrelationField(name string) MultiplexerOption {
+	return newFuncMultiplexerOption(func(o *multiplexerOptions) {
+		o.correlationField = protoreflect.Name(name)
+	})
+}
+
+// WithCallOptions sets the call options the stream is opened with.
+func WithCallOptions(opts ...grpc.CallOption) MultiplexerOption {
+	return newFuncMultiplexerOption(func(o *multiplexerOptions) {
+		o.callOptions = append(o.callOptions, opts...)
+	})
+}
+
+// Multiplexer sends requests over a single bidirectional stream,
+// matching the responses to their requests by correlation id.
+//
+// The server is expected to copy the correlation id of a request
+// into its response. The responses may be sent in any order.
+type Multiplexer struct {
+	stream      grpc.ClientStream
+	cancel      context.CancelFunc
+	newResponse func() proto.Message
+	opts        multiplexerOptions
+
+	// serializes the writes on the stream.
+	sendMu sync.Mutex
+	closed bool
+
+	mu      sync.Mutex
+	pending map[string]chan proto.Message
+	err     error
+
+	done chan struct{}
+}
+
+// NewMultiplexer opens a bidirectional stream, described by desc, to
+// method using conn. The received messages are decoded in the messages
+// returned by newResponse.
+//
+// The stream stays open until the multiplexer is closed or ctx is done.
+func NewMultiplexer(
+	ctx context.Context,
+	conn grpc.ClientConnInterface,
+	desc *grpc.StreamDesc,
+	method string,
+	newResponse func() proto.Message,
+	opts ...MultiplexerOption,
+) (*Multiplexer, error) {
+	o := multiplexerOptions{
+		correlationField: DefaultCorrelationField,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream, err := conn.NewStream(ctx, desc, method, o.callOptions...)
+	if err != nil {
+		cancel()
+
+		return nil, fmt.Errorf("new stream: %w", err)
+	}
+
+	m := &Multiplexer{
+		stream:      stream,
+		cancel:      cancel,
+		newResponse: newResponse,
+		opts:        o,
+		pending:     make(map[string]chan proto.Message),
+		done:        make(chan struct{}),
+	}
+
+	go m.receive()
+
+	return m, nil
+}
+
+// Send sends a copy of req, tagged with a new correlation id, and
+// waits for the response carrying the same id or for ctx to be done.
+func (m *Multiplexer) Send(ctx context.Context, req proto.Message) (proto.Message, error) {
+	req = proto.Clone(req)
+
+	field, err := m.correlationField(req)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+
+	req.ProtoReflect().Set(field, protoreflect.ValueOfString(id))
+
+	respCh := make(chan proto.Message, 1)
+
+	m.mu.Lock()
+
+	if m.err != nil {
+		m.mu.Unlock()
+
+		return nil, m.err
+	}
+
+	m.pending[id] = respCh
+
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		delete(m.pending, id)
+	}()
+
+	if err := m.send(req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respCh:
+		return resp, nil
+
+	case <-m.done:
+		return nil, m.closeErr()
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (m *Multiplexer) send(req proto.Message) error {
+	m.sendMu.Lock()
+	defer m.sendMu.Unlock()
+
+	if m.closed {
+		return ErrMultiplexerClosed
+	}
+
+	if err := m.stream.SendMsg(req); err != nil {
+		return fmt.Errorf("send message: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the stream, failing the pending requests.
+// It can be called multiple times.
+func (m *Multiplexer) Close() error {
+	m.sendMu.Lock()
+
+	var err error
+
+	if !m.closed {
+		m.closed = true
+
+		err = m.stream.CloseSend()
+	}
+
+	m.sendMu.Unlock()
+
+	m.cancel()
+
+	<-m.done
+
+	if err != nil {
+		return fmt.Errorf("close send: %w", err)
+	}
+
+	return nil
+}
+
+// receive delivers the responses to their pending requests until
+// the stream fails.
+func (m *Multiplexer) receive() {
+	defer close(m.done)
+
+	for {
+		resp := m.newResponse()
+
+		if err := m.stream.RecvMsg(resp); err != nil {
+			m.mu.Lock()
+			m.err = fmt.Errorf("%w: receive message: %w", ErrMultiplexerClosed, err)
+			m.mu.Unlock()
+
+			return
+		}
+
+		field, err := m.correlationField(resp)
+		if err != nil {
+			continue
+		}
+
+		id := resp.ProtoReflect().Get(field).String()
+
+		m.mu.Lock()
+		respCh := m.pending[id]
+		m.mu.Unlock()
+
+		// the responses to requests no longer waited for,
+		// and the duplicated responses, are dropped.
+		select {
+		case respCh <- resp:
+		default:
+		}
+	}
+}
+
+func (m *Multiplexer) closeErr() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.err
+}
+
+func (m *Multiplexer) correlationField(msg proto.Message) (protoreflect.FieldDescriptor, error) {
+	desc := msg.ProtoReflect().Descriptor()
+
+	field := desc.Fields().ByName(m.opts.correlationField)
+	if field == nil || field.Kind() != protoreflect.StringKind || field.Cardinality() == protoreflect.Repeated {
+		return nil, fmt.Errorf(
+			"%w: %s has no %s string field",
+			ErrInvalidCorrelationField,
+			desc.FullName(),
+			m.opts.correlationField,
+		)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,175 @@
+package bidi_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/bidi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var echoStreamDesc = &grpc.StreamDesc{
+	StreamName:    "Echo",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// newEchoConn returns a connection to a server that answers, in
+// reverse order, every batch of two apipb.Method requests with their
+// name and the request type url as response type url.
+func newEchoConn(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+
+	desc := *echoStreamDesc
+	desc.Handler = func(_ any, stream grpc.ServerStream) error {
+		for {
+			batch := make([]*apipb.Method, 2)
+
+			for n := range batch {
+				batch[n] = new(apipb.Method)
+
+				if err := stream.RecvMsg(batch[n]); err != nil {
+					return nil
+				}
+			}
+
+			for n := len(batch) - 1; n >= 0; n-- {
+				if err := stream.SendMsg(&apipb.Method{
+					Name:            batch[n].GetName(),
+					ResponseTypeUrl: batch[n].GetRequestTypeUrl(),
+				}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Echo",
+		HandlerType: (*any)(nil),
+		Streams:     []grpc.StreamDesc{desc},
+	}, struct{}{})
+
+	go func() { _ = server.Serve(lis) }()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough://bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(conn.Close()) })
+
+	return conn
+}
+
+func TestMultiplexer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Send", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mux, err := bidi.NewMultiplexer(
+			context.Background(),
+			newEchoConn(t),
+			echoStreamDesc,
+			"/test.Echo/Echo",
+			func() proto.Message { return new(apipb.Method) },
+			bidi.WithCorrelationField("name"),
+		)
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(mux.Close()) })
+
+		var wg sync.WaitGroup
+
+		for _, payload := range []string{"a", "b"} {
+			wg.Add(1)
+
+			go func(payload string) {
+				defer wg.Done()
+
+				resp, err := mux.Send(context.Background(), &apipb.Method{RequestTypeUrl: payload})
+				i.NoErr(err)
+
+				// the responses, sent in reverse order, are matched to their requests.
+				i.Equal(payload, resp.(*apipb.Method).GetResponseTypeUrl())
+			}(payload)
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("InvalidCorrelationField", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mux, err := bidi.NewMultiplexer(
+			context.Background(),
+			newEchoConn(t),
+			echoStreamDesc,
+			"/test.Echo/Echo",
+			func() proto.Message { return new(wrapperspb.StringValue) },
+		)
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(mux.Close()) })
+
+		_, err = mux.Send(context.Background(), wrapperspb.String("a"))
+		i.True(errors.Is(err, bidi.ErrInvalidCorrelationField))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mux, err := bidi.NewMultiplexer(
+			context.Background(),
+			newEchoConn(t),
+			echoStreamDesc,
+			"/test.Echo/Echo",
+			func() proto.Message { return new(apipb.Method) },
+			bidi.WithCorrelationField("name"),
+		)
+		i.NoErr(err)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			// the server waits for a second request that never comes.
+			_, err := mux.Send(context.Background(), &apipb.Method{RequestTypeUrl: "a"})
+			errCh <- err
+		}()
+
+		i.NoErr(mux.Close())
+
+		i.True(errors.Is(<-errCh, bidi.ErrMultiplexerClosed))
+
+		_, err = mux.Send(context.Background(), &apipb.Method{})
+		i.True(errors.Is(err, bidi.ErrMultiplexerClosed))
+	})
+}
@@ -0,0 +1,24 @@
+// Package ctxlog stores and retrieves a request scoped *zap.Logger
+// from a context.
+//
+// It is backed by the ctxzap package, so loggers injected here are
+// visible to the go-grpc-middleware zap interceptors and vice versa.
+package ctxlog
+
+import (
+	"context"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// WithLogger returns a copy of ctx holding the given logger.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return ctxzap.ToContext(ctx, logger)
+}
+
+// FromContext returns the logger stored in ctx.
+// It returns a no-op logger if ctx holds no logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	return ctxzap.Extract(ctx)
+}
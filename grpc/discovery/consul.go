@@ -0,0 +1,243 @@
+// Package discovery provides grpc resolvers that discover the addresses
+// of the servers from a service catalog.
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	consul "github.com/hashicorp/consul/api"
+	"google.golang.org/grpc/resolver"
+)
+
+// ConsulScheme is the scheme of the targets resolved by the consul
+// resolver, e.g. "consul:///users".
+const ConsulScheme = "consul"
+
+const (
+	// consulWaitTime is the maximum duration of a blocking query.
+	consulWaitTime = 5 * time.Minute
+
+	// consulRetryInterval is the delay before retrying a failed query.
+	consulRetryInterval = time.Second
+)
+
+// A ResolverOption configures the consul resolver.
+type ResolverOption interface {
+	apply(*resolverOptions)
+}
+
+type funcResolverOption struct {
+	f func(*resolverOptions)
+}
+
+func (fo *funcResolverOption) apply(o *resolverOptions) {
+	fo.f(o)
+}
+
+func newFuncResolverOption(f func(*resolverOptions)) *funcResolverOption {
+	return &funcResolverOption{
+		f: f,
+	}
+}
+
+type resolverOptions struct {
+	datacenter        string
+	tags              []string
+	fallbackAddresses []string
+}
+
+// WithDatacenter resolves the instances of the given datacenter
+// instead of the datacenter of the consul agent.
+func WithDatacenter(dc string) ResolverOption {
+	return newFuncResolverOption(func(o *resolverOptions) {
+		o.datacenter = dc
+	})
+}
+
+// WithTags resolves only the instances having all the given tags.
+func WithTags(tags []string) ResolverOption {
+	return newFuncResolverOption(func(o *resolverOptions) {
+		o.tags = tags
+	})
+}
+
+// WithFallbackAddresses configures the addresses used while consul
+// is unreachable and no instance was resolved yet.
+func WithFallbackAddresses(addrs []string) ResolverOption {
+	return newFuncResolverOption(func(o *resolverOptions) {
+		o.fallbackAddresses = addrs
+	})
+}
+
+var _ resolver.Builder = (*consulBuilder)(nil)
+
+type consulBuilder struct {
+	client      *consul.Client
+	serviceName string
+	opts        resolverOptions
+}
+
+// NewConsulResolver returns a resolver builder that watches the healthy
+// instances of serviceName in the consul catalog and updates the
+// addresses of the client connection as they change.
+//
+// The builder resolves targets with the ConsulScheme, regardless of
+// their endpoint. It can be registered on a connection using
+// grpcclient.WithResolvers.
+func NewConsulResolver(
+	client *consul.Client,
+	serviceName string,
+	opts ...ResolverOption,
+) resolver.Builder {
+	var o resolverOptions
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return &consulBuilder{
+		client:      client,
+		serviceName: serviceName,
+		opts:        o,
+	}
+}
+
+// Scheme returns the ConsulScheme.
+func (*consulBuilder) Scheme() string {
+	return ConsulScheme
+}
+
+// Build starts watching the consul catalog.
+func (b *consulBuilder) Build(
+	_ resolver.Target,
+	cc resolver.ClientConn,
+	_ resolver.BuildOptions,
+) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &consulResolver{
+		builder: b,
+		cc:      cc,
+		cancel:  cancel,
+	}
+
+	r.wg.Add(1)
+
+	go r.watch(ctx)
+
+	return r, nil
+}
+
+type consulResolver struct {
+	builder *consulBuilder
+	cc      resolver.ClientConn
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ResolveNow is a no-op, the instances are watched continuously.
+func (*consulResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+// Close stops watching the consul catalog.
+func (r *consulResolver) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *consulResolver) watch(ctx context.Context) {
+	defer r.wg.Done()
+
+	var (
+		lastIndex uint64
+		resolved  bool
+	)
+
+	for {
+		addrs, index, err := r.query(ctx, lastIndex)
+
+		switch {
+		case ctx.Err() != nil:
+			return
+
+		case err != nil:
+			if !resolved && len(r.builder.opts.fallbackAddresses) > 0 {
+				_ = r.cc.UpdateState(resolver.State{
+					Addresses: toAddresses(r.builder.opts.fallbackAddresses),
+				})
+			} else {
+				r.cc.ReportError(fmt.Errorf("query consul: %w", err))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(consulRetryInterval):
+			}
+
+			continue
+
+		// the index is reset when consul restarts.
+		case index < lastIndex:
+			lastIndex = 0
+
+			continue
+
+		case index == lastIndex && resolved:
+			continue
+		}
+
+		lastIndex = index
+		resolved = true
+
+		_ = r.cc.UpdateState(resolver.State{Addresses: toAddresses(addrs)})
+	}
+}
+
+// query blocks until the healthy instances change after
+// the given index and returns their addresses.
+func (r *consulResolver) query(ctx context.Context, index uint64) ([]string, uint64, error) {
+	q := (&consul.QueryOptions{
+		Datacenter: r.builder.opts.datacenter,
+		WaitIndex:  index,
+		WaitTime:   consulWaitTime,
+	}).WithContext(ctx)
+
+	entries, meta, err := r.builder.client.Health().ServiceMultipleTags(
+		r.builder.serviceName,
+		r.builder.opts.tags,
+		true,
+		q,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	addrs := make([]string, 0, len(entries))
+
+	for _, e := range entries {
+		host := e.Service.Address
+		if host == "" {
+			host = e.Node.Address
+		}
+
+		addrs = append(addrs, net.JoinHostPort(host, strconv.Itoa(e.Service.Port)))
+	}
+
+	return addrs, meta.LastIndex, nil
+}
+
+func toAddresses(addrs []string) []resolver.Address {
+	resolved := make([]resolver.Address, len(addrs))
+
+	for i, addr := range addrs {
+		resolved[i] = resolver.Address{Addr: addr}
+	}
+
+	return resolved
+}
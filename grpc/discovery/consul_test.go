@@ -0,0 +1,129 @@
+package discovery_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	consul "github.com/hashicorp/consul/api"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/discovery"
+	"google.golang.org/grpc/resolver"
+)
+
+func TestConsulResolver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Watch", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		consulServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/health/service/users" ||
+				r.URL.Query().Get("dc") != "dc2" ||
+				r.URL.Query().Get("tag") != "v1" {
+				t.Errorf("unexpected consul query: %s", r.URL)
+			}
+
+			// block the watch after the first response.
+			if r.URL.Query().Get("index") == "1" {
+				<-r.Context().Done()
+				return
+			}
+
+			w.Header().Set("X-Consul-Index", "1")
+
+			_ = json.NewEncoder(w).Encode([]*consul.ServiceEntry{
+				{
+					Node:    &consul.Node{Address: "10.0.0.1"},
+					Service: &consul.AgentService{Port: 8080},
+				},
+				{
+					Node:    &consul.Node{Address: "10.0.0.1"},
+					Service: &consul.AgentService{Address: "10.0.0.2", Port: 9090},
+				},
+			})
+		}))
+		t.Cleanup(consulServer.Close)
+
+		cc := newTestClientConn()
+
+		r := build(t, consulServer.URL, cc, discovery.WithDatacenter("dc2"), discovery.WithTags([]string{"v1"}))
+		t.Cleanup(r.Close)
+
+		i.Equal([]string{"10.0.0.1:8080", "10.0.0.2:9090"}, <-cc.states)
+	})
+
+	t.Run("Fallback", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		consulServer := httptest.NewServer(http.NotFoundHandler())
+		consulServer.Close()
+
+		cc := newTestClientConn()
+
+		r := build(t, consulServer.URL, cc, discovery.WithFallbackAddresses([]string{"10.0.0.3:8080"}))
+		t.Cleanup(r.Close)
+
+		i.Equal([]string{"10.0.0.3:8080"}, <-cc.states)
+	})
+}
+
+func build(
+	t *testing.T,
+	consulURL string,
+	cc resolver.ClientConn,
+	opts ...discovery.ResolverOption,
+) resolver.Resolver {
+	t.Helper()
+
+	i := is.New(t)
+
+	u, err := url.Parse(consulURL)
+	i.NoErr(err)
+
+	client, err := consul.NewClient(&consul.Config{Address: u.Host})
+	i.NoErr(err)
+
+	builder := discovery.NewConsulResolver(client, "users", opts...)
+	i.Equal(discovery.ConsulScheme, builder.Scheme())
+
+	r, err := builder.Build(resolver.Target{}, cc, resolver.BuildOptions{})
+	i.NoErr(err)
+
+	return r
+}
+
+// testClientConn records the addresses of the resolved states.
+type testClientConn struct {
+	resolver.ClientConn
+
+	states chan []string
+}
+
+func newTestClientConn() *testClientConn {
+	return &testClientConn{states: make(chan []string, 10)}
+}
+
+func (c *testClientConn) UpdateState(s resolver.State) error {
+	addrs := make([]string, len(s.Addresses))
+
+	for i, a := range s.Addresses {
+		addrs[i] = a.Addr
+	}
+
+	select {
+	case c.states <- addrs:
+	case <-time.After(time.Second):
+	}
+
+	return nil
+}
+
+func (*testClientConn) ReportError(error) {}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+)
+
+// ErrEventNotCaptured is returned when the error was not sent to Sentry.
+var ErrEventNotCaptured = errors.New("event not captured")
+
+// ErrorReporter defines how an error is reported to an external service.
+type ErrorReporter interface {
+	Report(ctx context.Context, err error) error
+}
+
+// ErrorReporterFunc is an adapter to allow the use of
+// ordinary functions as an ErrorReporter.
+type ErrorReporterFunc func(ctx context.Context, err error) error
+
+// Report calls f(ctx, err).
+func (f ErrorReporterFunc) Report(ctx context.Context, err error) error {
+	return f(ctx, err)
+}
+
+// NopErrorReporter returns an ErrorReporter that discards all errors.
+func NopErrorReporter() ErrorReporter {
+	return ErrorReporterFunc(func(context.Context, error) error {
+		return nil
+	})
+}
+
+// SentryErrorReporter returns an ErrorReporter that captures
+// the errors using the given hub.
+// If the context carries a hub, that one is used instead.
+// Without either, the errors are captured using sentry.CurrentHub.
+func SentryErrorReporter(hub *sentry.Hub) ErrorReporter {
+	return ErrorReporterFunc(func(ctx context.Context, err error) error {
+		h := hub
+
+		if ctxHub := sentry.GetHubFromContext(ctx); ctxHub != nil {
+			h = ctxHub
+		}
+
+		if h == nil {
+			h = sentry.CurrentHub()
+		}
+
+		if eventID := h.CaptureException(err); eventID == nil {
+			return ErrEventNotCaptured
+		}
+
+		return nil
+	})
+}
+
+var _ ErrorHandler = (*structuredErrorHandler)(nil)
+
+type structuredErrorHandler struct {
+	logger      *zap.Logger
+	reporter    ErrorReporter
+	appErrCheck func(error) bool
+	toStatus    func(error) (*status.Status, error)
+}
+
+// NewStructuredErrorHandler returns an ErrorHandler that logs errors
+// with the given logger and reports them using the reporter.
+//
+// The appErrCheck func decides which errors are application errors,
+// while toStatus converts them to a grpc status.
+func NewStructuredErrorHandler(
+	logger *zap.Logger,
+	reporter ErrorReporter,
+	appErrCheck func(error) bool,
+	toStatus func(error) (*status.Status, error),
+) ErrorHandler {
+	return &structuredErrorHandler{
+		logger:      logger,
+		reporter:    reporter,
+		appErrCheck: appErrCheck,
+		toStatus:    toStatus,
+	}
+}
+
+func (h *structuredErrorHandler) LogError(err error) {
+	h.logger.Error("request error", zap.Error(err))
+}
+
+func (h *structuredErrorHandler) IsApplicationError(err error) bool {
+	return h.appErrCheck(err)
+}
+
+func (h *structuredErrorHandler) ReportError(ctx context.Context, err error) error {
+	return h.reporter.Report(ctx, err)
+}
+
+func (h *structuredErrorHandler) ErrorToGRPCStatus(err error) (*status.Status, error) {
+	return h.toStatus(err)
+}
@@ -0,0 +1,72 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStructuredErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	// nolint: goerr113 // allow dynamic error for this sentinel error.
+	appErr := errors.New("app error")
+
+	var reported []error
+
+	errorHandler := commonsgrpc.NewStructuredErrorHandler(
+		zap.NewExample(),
+		commonsgrpc.ErrorReporterFunc(func(_ context.Context, err error) error {
+			reported = append(reported, err)
+			return nil
+		}),
+		func(err error) bool { return errors.Is(err, appErr) },
+		func(err error) (*status.Status, error) {
+			return status.New(codes.NotFound, err.Error()), nil
+		},
+	)
+
+	t.Run("ApplicationError", func(t *testing.T) {
+		i := is.New(t)
+
+		err := commonsgrpc.HandleError(appErr, errorHandler)
+		i.Equal(codes.NotFound, status.Code(err))
+		i.Equal(0, len(reported))
+	})
+
+	t.Run("InternalError", func(t *testing.T) {
+		i := is.New(t)
+
+		// nolint: goerr113 // allow dynamic error.
+		err := commonsgrpc.HandleError(errors.New("internal"), errorHandler)
+		i.Equal(codes.Internal, status.Code(err))
+		i.Equal(1, len(reported))
+	})
+
+	t.Run("NopReporter", func(t *testing.T) {
+		i := is.New(t)
+
+		i.NoErr(commonsgrpc.NopErrorReporter().Report(context.Background(), appErr))
+	})
+}
+
+func TestSentryErrorReporter(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	// nolint: goerr113 // allow dynamic error for this sentinel error.
+	err := errors.New("error")
+
+	// without a hub, the current hub, having no client, is used.
+	i.True(errors.Is(
+		commonsgrpc.SentryErrorReporter(nil).Report(context.Background(), err),
+		commonsgrpc.ErrEventNotCaptured,
+	))
+}
@@ -0,0 +1,131 @@
+// Package errorhandler builds grpc error handlers out of a registry
+// of application errors.
+package errorhandler
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotRegistered is returned when converting to a grpc status an
+// error that doesn't match any of the registered application errors.
+var ErrNotRegistered = errors.New("error not registered")
+
+type registration struct {
+	target  error
+	code    codes.Code
+	message string
+}
+
+// matches reports whether err matches the registered target.
+//
+// A typed nil pointer target, e.g. (*NotFoundError)(nil), matches
+// every error for which errors.As finds an error of that type in the
+// chain, any other target is matched using errors.Is.
+func (r registration) matches(err error) bool {
+	v := reflect.ValueOf(r.target)
+
+	if v.Kind() != reflect.Pointer || !v.IsNil() {
+		return errors.Is(err, r.target)
+	}
+
+	return errors.As(err, reflect.New(v.Type()).Interface())
+}
+
+// ErrorHandlerBuilder builds a commonsgrpc.ErrorHandler that maps the
+// registered application errors to grpc statuses.
+//
+// The zero value is not usable, use NewErrorHandlerBuilder instead.
+type ErrorHandlerBuilder struct {
+	registrations []registration
+	reporter      commonsgrpc.ErrorReporter
+	logger        *zap.Logger
+}
+
+// NewErrorHandlerBuilder returns a builder with no registered errors,
+// a nop reporter and a nop logger.
+func NewErrorHandlerBuilder() *ErrorHandlerBuilder {
+	return &ErrorHandlerBuilder{
+		reporter: commonsgrpc.NopErrorReporter(),
+		logger:   zap.NewNop(),
+	}
+}
+
+// RegisterApplicationError registers target as an application error
+// converted to a grpc status with the given code and message.
+// If the message is empty the error message is used instead.
+//
+// The registrations are checked in order, the first match wins.
+func (b *ErrorHandlerBuilder) RegisterApplicationError(
+	target error,
+	code codes.Code,
+	message string,
+) *ErrorHandlerBuilder {
+	b.registrations = append(b.registrations, registration{
+		target:  target,
+		code:    code,
+		message: message,
+	})
+
+	return b
+}
+
+// WithReporter sets the reporter used for the internal errors.
+func (b *ErrorHandlerBuilder) WithReporter(reporter commonsgrpc.ErrorReporter) *ErrorHandlerBuilder {
+	b.reporter = reporter
+
+	return b
+}
+
+// WithLogger sets the logger used to log the request errors.
+func (b *ErrorHandlerBuilder) WithLogger(logger *zap.Logger) *ErrorHandlerBuilder {
+	b.logger = logger
+
+	return b
+}
+
+// Build returns the error handler.
+// Registering more errors after Build doesn't affect it.
+func (b *ErrorHandlerBuilder) Build() commonsgrpc.ErrorHandler {
+	registrations := make([]registration, len(b.registrations))
+	copy(registrations, b.registrations)
+
+	lookup := func(err error) (registration, bool) {
+		for _, r := range registrations {
+			if r.matches(err) {
+				return r, true
+			}
+		}
+
+		return registration{}, false
+	}
+
+	return commonsgrpc.NewStructuredErrorHandler(
+		b.logger,
+		b.reporter,
+		func(err error) bool {
+			_, ok := lookup(err)
+
+			return ok
+		},
+		func(err error) (*status.Status, error) {
+			r, ok := lookup(err)
+			if !ok {
+				return nil, fmt.Errorf("%w: %w", ErrNotRegistered, err)
+			}
+
+			message := r.message
+			if message == "" {
+				message = err.Error()
+			}
+
+			return status.New(r.code, message), nil
+		},
+	)
+}
@@ -0,0 +1,77 @@
+package errorhandler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/matryer/is"
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"github.com/purposeinplay/go-commons/grpc/errorhandler"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.field
+}
+
+func TestErrorHandlerBuilder(t *testing.T) {
+	t.Parallel()
+
+	// nolint: goerr113 // allow dynamic error for this sentinel error.
+	errNotFound := errors.New("not found")
+
+	var reported []error
+
+	errorHandler := errorhandler.NewErrorHandlerBuilder().
+		RegisterApplicationError(errNotFound, codes.NotFound, "resource not found").
+		RegisterApplicationError((*validationError)(nil), codes.InvalidArgument, "").
+		WithReporter(commonsgrpc.ErrorReporterFunc(func(_ context.Context, err error) error {
+			reported = append(reported, err)
+			return nil
+		})).
+		Build()
+
+	t.Run("Is", func(t *testing.T) {
+		i := is.New(t)
+
+		err := commonsgrpc.HandleError(fmt.Errorf("get user: %w", errNotFound), errorHandler)
+		i.Equal(codes.NotFound, status.Code(err))
+		i.Equal("resource not found", status.Convert(err).Message())
+		i.Equal(0, len(reported))
+	})
+
+	t.Run("As", func(t *testing.T) {
+		i := is.New(t)
+
+		err := commonsgrpc.HandleError(
+			fmt.Errorf("create user: %w", &validationError{field: "email"}),
+			errorHandler,
+		)
+		i.Equal(codes.InvalidArgument, status.Code(err))
+		i.Equal("create user: invalid email", status.Convert(err).Message())
+		i.Equal(0, len(reported))
+	})
+
+	t.Run("Unregistered", func(t *testing.T) {
+		i := is.New(t)
+
+		// nolint: goerr113 // allow dynamic error.
+		internalErr := errors.New("internal")
+
+		i.True(!errorHandler.IsApplicationError(internalErr))
+
+		_, err := errorHandler.ErrorToGRPCStatus(internalErr)
+		i.True(errors.Is(err, errorhandler.ErrNotRegistered))
+
+		err = commonsgrpc.HandleError(internalErr, errorHandler)
+		i.Equal(codes.Internal, status.Code(err))
+		i.Equal(1, len(reported))
+	})
+}
@@ -28,9 +28,17 @@ type grpcServer struct {
 	grpcServer *grpc.Server
 	listener   net.Listener
 	closed     atomic.Bool
+
+	shutdownTimeout time.Duration
+
+	health *healthPoller
 }
 
 func (s *grpcServer) listenAndServe() error {
+	if s.health != nil {
+		s.health.start(s.grpcServer)
+	}
+
 	return s.grpcServer.Serve(s.listener)
 }
 
@@ -45,109 +53,107 @@ func (s *grpcServer) close() error {
 
 	s.closed.Store(true)
 
-	s.grpcServer.GracefulStop()
+	if s.health != nil {
+		s.health.stop()
+	}
+
+	if s.shutdownTimeout <= 0 {
+		s.grpcServer.GracefulStop()
+
+		return nil
+	}
+
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		s.grpcServer.GracefulStop()
+	}()
+
+	timer := time.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		return nil
+
+	case <-timer.C:
+		s.grpcServer.Stop()
+		<-stopped
 
-	return nil
+		return ErrShutdownTimeout
+	}
 }
 
-// nolint: gocyclo, revive // cyclomatic complexity is 9. FIXME
-// revive complains about tracing being a control flag.
+// newGRPCServer builds the grpc server of NewServer, see ServerBuilder.
+//
+// nolint: revive // complains about tracing being a control flag.
 func newGRPCServer(
 	listener net.Listener,
 	address string,
 	tracing bool,
+	traceExporter trace.Exporter,
+	traceSampler trace.Sampler,
 	defaultGRPCServerOptions []grpc.ServerOption,
 	unaryServerInterceptors []grpc.UnaryServerInterceptor,
+	streamServerInterceptors []grpc.StreamServerInterceptor,
 	registerServer registerServerFunc,
 	logging *logging,
 	errorHandler ErrorHandler,
 	panicHandler PanicHandler,
+	panicReportTimeout time.Duration,
+	panicStatusCode codes.Code,
 	monitorOperationer MonitorOperationer,
 ) (
 	*grpcServer,
 	error,
 ) {
-	grpcListener, err := newGRPCListener(listener, address)
-	if err != nil {
-		return nil, fmt.Errorf("new grpc listener: %w", err)
+	b := &ServerBuilder{
+		address:                  address,
+		listener:                 listener,
+		tracing:                  tracing,
+		traceExporter:            traceExporter,
+		traceSampler:             traceSampler,
+		grpcServerOptions:        defaultGRPCServerOptions,
+		unaryServerInterceptors:  unaryServerInterceptors,
+		streamServerInterceptors: streamServerInterceptors,
+		registerServer:           registerServer,
+		logging:                  logging,
+		errorHandler:             errorHandler,
+		panicHandler:             panicHandler,
+		panicReportTimeout:       panicReportTimeout,
+		panicStatusCode:          panicStatusCode,
+		monitorOperationer:       monitorOperationer,
 	}
 
-	grpcServerOptions := defaultGRPCServerOptions
+	return b.build()
+}
 
-	if tracing {
-		grpcServerOptions, err = setGRPCTracing(grpcServerOptions)
+// setGRPCTracing registers the exporter, defaulting to Stackdriver,
+// and the sampler, defaulting to sampling every trace.
+func setGRPCTracing(
+	serverOptions []grpc.ServerOption,
+	exporter trace.Exporter,
+	sampler trace.Sampler,
+) ([]grpc.ServerOption, error) {
+	if exporter == nil {
+		stackdriverExporter, err := stackdriver.NewExporter(stackdriver.Options{
+			ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		})
 		if err != nil {
-			return nil, fmt.Errorf("set grpc tracing tracing: %w", err)
+			return nil, fmt.Errorf("new exporter: %w", err)
 		}
-	}
-
-	if !isErrorHandlerNil(errorHandler) {
-		// nolint: revive // complains that this lines modifies
-		// an input parameter.
-		unaryServerInterceptors = prependErrorHandler(
-			unaryServerInterceptors,
-			errorHandler,
-		)
-	}
-
-	if !isPanicHandlerNil(panicHandler) {
-		// nolint: revive // complains that this lines modifies
-		// an input parameter.
-		unaryServerInterceptors = prependPanicHandler(
-			unaryServerInterceptors,
-			panicHandler,
-		)
-	}
-
-	if logging != nil {
-		// nolint: revive // complains that this lines modifies
-		// an input parameter.
-		unaryServerInterceptors = prependDebugInterceptor(
-			unaryServerInterceptors,
-			logging,
-		)
-	}
 
-	if !isMonitorOperationerNil(monitorOperationer) {
-		// nolint: revive // complains that this lines modifies
-		// an input parameter.
-		unaryServerInterceptors = append(
-			unaryServerInterceptors,
-			newMonitorOperationUnaryInterceptor(monitorOperationer),
-		)
-	}
-
-	if len(unaryServerInterceptors) > 0 {
-		grpcServerOptions = append(grpcServerOptions,
-			grpc.ChainUnaryInterceptor(
-				unaryServerInterceptors...,
-			))
+		exporter = stackdriverExporter
 	}
 
-	internalGRPCServer := grpc.NewServer(grpcServerOptions...)
-
-	if registerServer != nil {
-		registerServer(internalGRPCServer)
-	}
-
-	return &grpcServer{
-		grpcServer: internalGRPCServer,
-		listener:   grpcListener,
-	}, nil
-}
-
-func setGRPCTracing(
-	serverOptions []grpc.ServerOption,
-) ([]grpc.ServerOption, error) {
-	exporter, err := stackdriver.NewExporter(stackdriver.Options{
-		ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("new exporter: %w", err)
+	if sampler == nil {
+		sampler = trace.AlwaysSample()
 	}
 
 	trace.RegisterExporter(exporter)
-	trace.ApplyConfig(trace.Config{DefaultSampler: trace.AlwaysSample()})
+	trace.ApplyConfig(trace.Config{DefaultSampler: sampler})
 
 	return append(
 		serverOptions,
@@ -181,6 +187,29 @@ func newGRPCListener(
 	return listener, nil
 }
 
+// debugRequestID returns the id the requests are logged with: the
+// request id if it is a valid uuid, otherwise the raw request id, e.g.
+// the trace id, or the zero uuid if the request has no id.
+func debugRequestID(ctx context.Context) string {
+	if id, err := grpcutils.GetValidatedRequestIDFromCtx(ctx); err == nil {
+		return id.String()
+	}
+
+	if requestID, err := grpcutils.GetRequestIDFromCtx(ctx); err == nil {
+		return requestID
+	}
+
+	return uuid.Nil.String()
+}
+
+// debugRemoteAddr returns the ip of the peer the requests are logged
+// with, or an empty string if the request has no peer.
+func debugRemoteAddr(ctx context.Context) string {
+	ip, _ := grpcutils.ExtractPeerIP(ctx)
+
+	return ip
+}
+
 // nolint: gocognit
 func prependDebugInterceptor(
 	interceptors []grpc.UnaryServerInterceptor,
@@ -209,14 +238,13 @@ func prependDebugInterceptor(
 				}
 			}
 
-			requestID, err := grpcutils.GetRequestIDFromCtx(ctx)
-			if err != nil {
-				requestID = uuid.Nil.String()
-			}
+			requestID := debugRequestID(ctx)
+			remoteAddr := debugRemoteAddr(ctx)
 
 			loggingFields := []zap.Field{
 				zap.String("trace_id", requestID),
 				zap.String("method", method),
+				zap.String("remote_addr", remoteAddr),
 			}
 
 			if logging.logRequest {
@@ -237,6 +265,7 @@ func prependDebugInterceptor(
 					"request completed with error",
 					zap.String("trace_id", requestID),
 					zap.String("method", method),
+					zap.String("remote_addr", remoteAddr),
 					zap.Error(err),
 					zap.String("error dump", spew.Sdump(err)),
 					zap.String("code", code.String()),
@@ -250,6 +279,7 @@ func prependDebugInterceptor(
 				"request completed successfully",
 				zap.String("trace_id", requestID),
 				zap.String("method", method),
+				zap.String("remote_addr", remoteAddr),
 				zap.String("code", code.String()),
 				zap.Duration("duration", time.Since(start)),
 				zap.Any("response", resp),
@@ -261,6 +291,72 @@ func prependDebugInterceptor(
 	)
 }
 
+// prependStreamDebugInterceptor is the stream counterpart of
+// prependDebugInterceptor, the messages of the stream are not logged.
+func prependStreamDebugInterceptor(
+	interceptors []grpc.StreamServerInterceptor,
+	logging *logging,
+) []grpc.StreamServerInterceptor {
+	return prependStreamServerOption(
+		func(
+			srv any,
+			ss grpc.ServerStream,
+			info *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			start := time.Now()
+
+			method := path.Base(info.FullMethod)
+
+			for _, m := range logging.ignoredMethods {
+				if method == m {
+					return handler(srv, ss)
+				}
+			}
+
+			requestID := debugRequestID(ss.Context())
+			remoteAddr := debugRemoteAddr(ss.Context())
+
+			logging.logger.Debug(
+				"stream started",
+				zap.String("trace_id", requestID),
+				zap.String("method", method),
+				zap.String("remote_addr", remoteAddr),
+			)
+
+			err := handler(srv, ss)
+
+			code := status.Code(err)
+
+			if err != nil {
+				logging.logger.Debug(
+					"stream completed with error",
+					zap.String("trace_id", requestID),
+					zap.String("method", method),
+					zap.String("remote_addr", remoteAddr),
+					zap.Error(err),
+					zap.String("code", code.String()),
+					zap.Duration("duration", time.Since(start)),
+				)
+
+				return err
+			}
+
+			logging.logger.Debug(
+				"stream completed successfully",
+				zap.String("trace_id", requestID),
+				zap.String("method", method),
+				zap.String("remote_addr", remoteAddr),
+				zap.String("code", code.String()),
+				zap.Duration("duration", time.Since(start)),
+			)
+
+			return nil
+		},
+		interceptors,
+	)
+}
+
 // PanicHandler defines methods for handling a panic.
 type PanicHandler interface {
 	ReportPanic(context.Context, any) error
@@ -268,13 +364,37 @@ type PanicHandler interface {
 	LogError(error)
 }
 
+// defaultPanicReportTimeout is the default timeout of the
+// PanicHandler.ReportPanic calls.
+const defaultPanicReportTimeout = time.Second
+
+// NewRecoveryFuncWithTimeout returns a recovery handler that logs and
+// reports the panics with panicHandler, bounding the ReportPanic calls
+// with timeout, defaulting to 1s if zero, and returns an Internal status.
+func NewRecoveryFuncWithTimeout(
+	panicHandler PanicHandler,
+	timeout time.Duration,
+) grpcrecovery.RecoveryHandlerFunc {
+	return newRecoveryFunc(panicHandler, timeout, codes.Internal)
+}
+
 func newRecoveryFunc(
 	panicHandler PanicHandler,
+	timeout time.Duration,
+	code codes.Code,
 ) grpcrecovery.RecoveryHandlerFunc {
+	if timeout <= 0 {
+		timeout = defaultPanicReportTimeout
+	}
+
+	if code == codes.OK {
+		code = codes.Internal
+	}
+
 	return func(p any) error {
 		ctx, cancelCtx := context.WithTimeout(
 			context.Background(),
-			time.Second,
+			timeout,
 		)
 		defer cancelCtx()
 
@@ -289,17 +409,29 @@ func newRecoveryFunc(
 			))
 		}
 
-		return status.Error(codes.Internal, "internal error.")
+		return status.Error(code, "internal error.")
 	}
 }
 
 func prependPanicHandler(
 	interceptors []grpc.UnaryServerInterceptor,
-	panicHandler PanicHandler,
+	recoveryFunc grpcrecovery.RecoveryHandlerFunc,
 ) []grpc.UnaryServerInterceptor {
 	return prependServerOption(
 		grpcrecovery.UnaryServerInterceptor(
-			grpcrecovery.WithRecoveryHandler(newRecoveryFunc(panicHandler)),
+			grpcrecovery.WithRecoveryHandler(recoveryFunc),
+		),
+		interceptors,
+	)
+}
+
+func prependStreamPanicHandler(
+	interceptors []grpc.StreamServerInterceptor,
+	recoveryFunc grpcrecovery.RecoveryHandlerFunc,
+) []grpc.StreamServerInterceptor {
+	return prependStreamServerOption(
+		grpcrecovery.StreamServerInterceptor(
+			grpcrecovery.WithRecoveryHandler(recoveryFunc),
 		),
 		interceptors,
 	)
@@ -436,6 +568,34 @@ func prependErrorHandler(
 	)
 }
 
+func prependStreamErrorHandler(
+	interceptors []grpc.StreamServerInterceptor,
+	errorHandler ErrorHandler,
+) []grpc.StreamServerInterceptor {
+	return prependStreamServerOption(
+		func(
+			srv any,
+			ss grpc.ServerStream,
+			info *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			err := handler(srv, ss)
+			if err != nil {
+				// nolint: contextcheck // do not pass the stream context,
+				// see prependErrorHandler.
+				return HandleError(fmt.Errorf(
+					"%q: %w",
+					path.Base(info.FullMethod),
+					err,
+				), errorHandler)
+			}
+
+			return nil
+		},
+		interceptors,
+	)
+}
+
 // MonitorOperationer defines.
 type MonitorOperationer interface {
 	MonitorOperation(
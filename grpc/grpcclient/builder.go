@@ -0,0 +1,181 @@
+package grpcclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/oauth"
+)
+
+// RetryOptions configures the retries of the failed calls.
+type RetryOptions struct {
+	// Max is the maximum number of retries, 0 disables the retries.
+	Max uint
+
+	// Backoff is the base of the exponential backoff between the
+	// attempts. Defaults to the 50ms linear backoff of grpc_retry.
+	Backoff time.Duration
+
+	// Jitter is the fraction of the backoff randomly added or
+	// subtracted to it.
+	Jitter float64
+
+	// Codes are the codes of the errors retried.
+	// Defaults to codes.ResourceExhausted and codes.Unavailable.
+	Codes []codes.Code
+
+	// PerRetryTimeout is the timeout of each attempt,
+	// 0 meaning that the attempts share the timeout of the call.
+	PerRetryTimeout time.Duration
+}
+
+func (o RetryOptions) callOptions() []grpcretry.CallOption {
+	callOpts := []grpcretry.CallOption{grpcretry.WithMax(o.Max)}
+
+	if o.Backoff > 0 {
+		callOpts = append(callOpts, grpcretry.WithBackoff(
+			grpcretry.BackoffExponentialWithJitter(o.Backoff, o.Jitter),
+		))
+	}
+
+	if len(o.Codes) > 0 {
+		callOpts = append(callOpts, grpcretry.WithCodes(o.Codes...))
+	}
+
+	if o.PerRetryTimeout > 0 {
+		callOpts = append(callOpts, grpcretry.WithPerRetryTimeout(o.PerRetryTimeout))
+	}
+
+	return callOpts
+}
+
+// ClientBuilder builds client connections, it is the client
+// counterpart of the grpc.ServerBuilder.
+type ClientBuilder struct {
+	dialOptions        []grpc.DialOption
+	unaryInterceptors  []grpc.UnaryClientInterceptor
+	streamInterceptors []grpc.StreamClientInterceptor
+	retry              *RetryOptions
+}
+
+// NewClientBuilder creates a ClientBuilder.
+func NewClientBuilder() *ClientBuilder {
+	return new(ClientBuilder)
+}
+
+// WithUnaryClientInterceptors appends the interceptors to the unary
+// interceptors chain of the connection.
+func (b *ClientBuilder) WithUnaryClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) *ClientBuilder {
+	b.unaryInterceptors = append(b.unaryInterceptors, interceptors...)
+
+	return b
+}
+
+// WithStreamClientInterceptors appends the interceptors to the stream
+// interceptors chain of the connection.
+func (b *ClientBuilder) WithStreamClientInterceptors(interceptors ...grpc.StreamClientInterceptor) *ClientBuilder {
+	b.streamInterceptors = append(b.streamInterceptors, interceptors...)
+
+	return b
+}
+
+// WithJWTToken authenticates the calls with the tokens of tokenSource,
+// sent in the authorization metadata. It requires transport security,
+// see WithTLS.
+func (b *ClientBuilder) WithJWTToken(tokenSource oauth2.TokenSource) *ClientBuilder {
+	b.dialOptions = append(
+		b.dialOptions,
+		grpc.WithPerRPCCredentials(oauth.TokenSource{TokenSource: tokenSource}),
+	)
+
+	return b
+}
+
+// WithTLS secures the connection with cfg.
+func (b *ClientBuilder) WithTLS(cfg *tls.Config) *ClientBuilder {
+	b.dialOptions = append(
+		b.dialOptions,
+		grpc.WithTransportCredentials(credentials.NewTLS(cfg)),
+	)
+
+	return b
+}
+
+// WithOTelTracing traces the calls with the tracer provider tp.
+func (b *ClientBuilder) WithOTelTracing(tp trace.TracerProvider) *ClientBuilder {
+	b.dialOptions = append(
+		b.dialOptions,
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp))),
+	)
+
+	return b
+}
+
+// WithRetry retries the failed unary and server streaming calls.
+//
+// The retry interceptors are the outermost ones, each attempt goes
+// through the interceptors of the connection.
+func (b *ClientBuilder) WithRetry(opts RetryOptions) *ClientBuilder {
+	b.retry = &opts
+
+	return b
+}
+
+// WithDialOptions appends the options to the dial options
+// of the connection, e.g. grpc.WithContextDialer.
+func (b *ClientBuilder) WithDialOptions(opts ...grpc.DialOption) *ClientBuilder {
+	b.dialOptions = append(b.dialOptions, opts...)
+
+	return b
+}
+
+// Build creates the client connection to target.
+//
+// Like for grpc.NewClient, no connection is established until
+// the first call, or until Connect is called.
+func (b *ClientBuilder) Build(target string) (*grpc.ClientConn, error) {
+	unaryInterceptors := b.unaryInterceptors
+	streamInterceptors := b.streamInterceptors
+
+	if b.retry != nil {
+		callOpts := b.retry.callOptions()
+
+		unaryInterceptors = append(
+			[]grpc.UnaryClientInterceptor{grpcretry.UnaryClientInterceptor(callOpts...)},
+			unaryInterceptors...,
+		)
+
+		streamInterceptors = append(
+			[]grpc.StreamClientInterceptor{grpcretry.StreamClientInterceptor(callOpts...)},
+			streamInterceptors...,
+		)
+	}
+
+	dialOptions := append(
+		[]grpc.DialOption{
+			grpc.WithChainUnaryInterceptor(unaryInterceptors...),
+			grpc.WithChainStreamInterceptor(streamInterceptors...),
+		},
+		b.dialOptions...,
+	)
+
+	// nolint: revive
+	if target == "bufnet" {
+		target = "passthrough://bufnet"
+	}
+
+	conn, err := grpc.NewClient(target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc dial: %w", err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,95 @@
+package grpcclient_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/grpcclient"
+	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestClientBuilder(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+
+	greeter := new(unavailableGreeter)
+	greeter.failures.Store(2)
+
+	greetpb.RegisterGreetServiceServer(server, greeter)
+
+	go func() { _ = server.Serve(lis) }()
+
+	t.Cleanup(server.Stop)
+
+	var intercepted atomic.Int32
+
+	conn, err := grpcclient.NewClientBuilder().
+		WithDialOptions(
+			grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+				return lis.Dial()
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		).
+		WithUnaryClientInterceptors(func(
+			ctx context.Context,
+			method string,
+			req, reply any,
+			cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker,
+			opts ...grpc.CallOption,
+		) error {
+			intercepted.Add(1)
+
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}).
+		WithRetry(grpcclient.RetryOptions{
+			Max:     3,
+			Backoff: time.Millisecond,
+		}).
+		Build("bufnet")
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(conn.Close()) })
+
+	resp, err := greetpb.NewGreetServiceClient(conn).Greet(
+		context.Background(),
+		&greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "John"}},
+	)
+	i.NoErr(err)
+	i.Equal("John", resp.GetResult())
+
+	// each attempt goes through the interceptors.
+	i.Equal(int32(3), intercepted.Load())
+}
+
+// unavailableGreeter is unavailable for the first failures calls.
+type unavailableGreeter struct {
+	greetpb.UnimplementedGreetServiceServer
+
+	failures atomic.Int32
+}
+
+func (g *unavailableGreeter) Greet(
+	_ context.Context,
+	req *greetpb.GreetRequest,
+) (*greetpb.GreetResponse, error) {
+	if g.failures.Add(-1) >= 0 {
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+
+	return &greetpb.GreetResponse{Result: req.GetGreeting().GetFirstName()}, nil
+}
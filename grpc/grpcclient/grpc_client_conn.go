@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net"
 
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
 )
 
 // NewConn creates a client connection to the given addr.
@@ -118,3 +120,21 @@ func WithOTEL(handlerOptions ...otelgrpc.Option) OptionConn {
 		)
 	})
 }
+
+// ChainUnaryClientInterceptors returns a dial option that chains the
+// interceptors, the first one being the outermost.
+// It can be used with grpc.NewClient directly, outside of NewConn.
+func ChainUnaryClientInterceptors(interceptors ...grpc.UnaryClientInterceptor) grpc.DialOption {
+	return grpc.WithUnaryInterceptor(grpcmiddleware.ChainUnaryClient(interceptors...))
+}
+
+// WithResolvers registers resolver builders local to the connection,
+// e.g. discovery.NewConsulResolver, selected by the scheme of the addr.
+func WithResolvers(builders ...resolver.Builder) OptionConn {
+	return newFuncConnOption(func(o *connOptions) {
+		o.dialOptions = append(
+			o.dialOptions,
+			grpc.WithResolvers(builders...),
+		)
+	})
+}
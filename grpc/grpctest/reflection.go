@@ -0,0 +1,163 @@
+// Package grpctest provides helpers for integration tests of grpc servers.
+package grpctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+var errServiceNotFound = errors.New("service not found in file descriptors")
+
+// reflectionServices are registered alongside the reflection service
+// and are never reported as unrecognised.
+var reflectionServices = map[string]bool{
+	"grpc.reflection.v1.ServerReflection":      true,
+	"grpc.reflection.v1alpha.ServerReflection": true,
+}
+
+// ValidateReflection discovers the services registered on the server
+// behind conn using the reflection service and reports, with t.Errorf,
+// every expected service or method that is missing and every
+// registered service that is not expected.
+//
+// expected maps the full service names, e.g. "helloworld.Greeter",
+// to their method names.
+func ValidateReflection(t testing.TB, conn *grpc.ClientConn, expected map[string][]string) {
+	t.Helper()
+
+	registered, err := reflectServices(context.Background(), conn)
+	if err != nil {
+		t.Errorf("reflect services: %s", err)
+		return
+	}
+
+	for service, methods := range expected {
+		registeredMethods, ok := registered[service]
+		if !ok {
+			t.Errorf("service %q is not registered", service)
+			continue
+		}
+
+		for _, method := range methods {
+			if !registeredMethods[method] {
+				t.Errorf("method %q of service %q is not registered", method, service)
+			}
+		}
+	}
+
+	extra := make([]string, 0)
+
+	for service := range registered {
+		if _, ok := expected[service]; !ok && !reflectionServices[service] {
+			extra = append(extra, service)
+		}
+	}
+
+	sort.Strings(extra)
+
+	for _, service := range extra {
+		t.Errorf("service %q is registered but not expected", service)
+	}
+}
+
+// reflectServices returns the method names of every registered service.
+func reflectServices(ctx context.Context, conn *grpc.ClientConn) (map[string]map[string]bool, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("open reflection stream: %w", err)
+	}
+
+	defer func() { _ = stream.CloseSend() }()
+
+	resp, err := reflect(stream, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list services: %w", err)
+	}
+
+	services := make(map[string]map[string]bool)
+
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		name := service.GetName()
+
+		resp, err := reflect(stream, &reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+				FileContainingSymbol: name,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("file containing %q: %w", name, err)
+		}
+
+		methods, err := serviceMethods(resp.GetFileDescriptorResponse().GetFileDescriptorProto(), name)
+		if err != nil {
+			return nil, fmt.Errorf("methods of %q: %w", name, err)
+		}
+
+		services[name] = methods
+	}
+
+	return services, nil
+}
+
+func reflect(
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient,
+	req *reflectionpb.ServerReflectionRequest,
+) (*reflectionpb.ServerReflectionResponse, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, fmt.Errorf("send: %w", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("receive: %w", err)
+	}
+
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("reflection error %d: %s", errResp.GetErrorCode(), errResp.GetErrorMessage())
+	}
+
+	return resp, nil
+}
+
+// serviceMethods finds the service among the serialized file
+// descriptors and returns its method names.
+func serviceMethods(files [][]byte, service string) (map[string]bool, error) {
+	for _, b := range files {
+		var file descriptorpb.FileDescriptorProto
+
+		if err := proto.Unmarshal(b, &file); err != nil {
+			return nil, fmt.Errorf("unmarshal file descriptor: %w", err)
+		}
+
+		prefix := ""
+		if pkg := file.GetPackage(); pkg != "" {
+			prefix = pkg + "."
+		}
+
+		for _, s := range file.GetService() {
+			if prefix+s.GetName() != service {
+				continue
+			}
+
+			methods := make(map[string]bool, len(s.GetMethod()))
+
+			for _, m := range s.GetMethod() {
+				methods[m.GetName()] = true
+			}
+
+			return methods, nil
+		}
+	}
+
+	return nil, errServiceNotFound
+}
@@ -0,0 +1,89 @@
+package grpctest_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/grpctest"
+	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestValidateReflection(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	greetpb.RegisterGreetServiceServer(server, greetpb.UnimplementedGreetServiceServer{})
+	reflection.Register(server)
+
+	go func() { _ = server.Serve(lis) }()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(conn.Close()) })
+
+	t.Run("Valid", func(t *testing.T) {
+		i := is.New(t)
+
+		rt := &recordingTB{TB: t}
+
+		grpctest.ValidateReflection(rt, conn, map[string][]string{
+			"GreetService": {"Greet"},
+		})
+
+		i.Equal([]string(nil), rt.errors)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		i := is.New(t)
+
+		rt := &recordingTB{TB: t}
+
+		grpctest.ValidateReflection(rt, conn, map[string][]string{
+			"GreetService":     {"Greet", "Farewell"},
+			"user.UserService": {"GetUser"},
+		})
+
+		i.Equal(2, len(rt.errors))
+	})
+
+	t.Run("Unexpected", func(t *testing.T) {
+		i := is.New(t)
+
+		rt := &recordingTB{TB: t}
+
+		grpctest.ValidateReflection(rt, conn, map[string][]string{})
+
+		i.Equal(1, len(rt.errors))
+	})
+}
+
+// recordingTB records the errors instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,93 @@
+package grpcutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrMetadataKeyNotPresent is returned when the metadata doesn't hold
+// the requested key.
+var ErrMetadataKeyNotPresent = errors.New("metadata key not present")
+
+// SetMetadataValue sets the key of the outgoing metadata to val,
+// replacing the previous values of the key.
+//
+// The strings are stored as is, the other values are encoded as JSON.
+// The context is returned unchanged if val can't be encoded.
+func SetMetadataValue[T any](ctx context.Context, key string, val T) context.Context {
+	var encoded string
+
+	if s, ok := any(val).(string); ok {
+		encoded = s
+	} else {
+		b, err := json.Marshal(val)
+		if err != nil {
+			return ctx
+		}
+
+		encoded = string(b)
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	md = md.Copy()
+
+	md.Set(key, encoded)
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// GetMetadataValue returns the value of the key of the incoming metadata,
+// set by the caller with SetMetadataValue.
+func GetMetadataValue[T any](ctx context.Context, key string) (T, error) {
+	var val T
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return val, ErrMetadataNotFound
+	}
+
+	values := md.Get(key)
+	if len(values) == 0 {
+		return val, fmt.Errorf("%w: %s", ErrMetadataKeyNotPresent, key)
+	}
+
+	if s, ok := any(&val).(*string); ok {
+		*s = values[0]
+
+		return val, nil
+	}
+
+	if err := json.Unmarshal([]byte(values[0]), &val); err != nil {
+		return val, fmt.Errorf("unmarshal %s metadata: %w", key, err)
+	}
+
+	return val, nil
+}
+
+// PropagateIncomingToOutgoing copies the values of the given keys
+// from the incoming to the outgoing metadata, e.g. to pass the request id
+// on to the services called while handling a request.
+// The keys missing from the incoming metadata are skipped.
+func PropagateIncomingToOutgoing(ctx context.Context, keys ...string) context.Context {
+	incoming, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	outgoing, _ := metadata.FromOutgoingContext(ctx)
+
+	outgoing = outgoing.Copy()
+
+	for _, key := range keys {
+		if values := incoming.Get(key); len(values) > 0 {
+			outgoing.Set(key, values...)
+		}
+	}
+
+	return metadata.NewOutgoingContext(ctx, outgoing)
+}
@@ -0,0 +1,75 @@
+package grpcutils_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/grpcutils"
+	"google.golang.org/grpc/metadata"
+)
+
+type tenant struct {
+	ID    string `json:"id"`
+	Level int    `json:"level"`
+}
+
+func TestMetadataValue(t *testing.T) {
+	i := is.New(t)
+
+	ctx := context.Background()
+
+	_, err := grpcutils.GetMetadataValue[string](ctx, "x-user")
+	i.True(errors.Is(err, grpcutils.ErrMetadataNotFound))
+
+	ctx = grpcutils.SetMetadataValue(ctx, "x-user", "john")
+	ctx = grpcutils.SetMetadataValue(ctx, "x-tenant", tenant{ID: "t1", Level: 2})
+	ctx = grpcutils.SetMetadataValue(ctx, "x-tenant", tenant{ID: "t2", Level: 3})
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	i.True(ok)
+
+	i.Equal([]string{"john"}, md.Get("x-user"))
+	i.Equal([]string{`{"id":"t2","level":3}`}, md.Get("x-tenant"))
+
+	// the outgoing metadata of the caller is the incoming one of the callee.
+	ctx = metadata.NewIncomingContext(context.Background(), md)
+
+	user, err := grpcutils.GetMetadataValue[string](ctx, "x-user")
+	i.NoErr(err)
+	i.Equal("john", user)
+
+	tnt, err := grpcutils.GetMetadataValue[tenant](ctx, "x-tenant")
+	i.NoErr(err)
+	i.Equal(tenant{ID: "t2", Level: 3}, tnt)
+
+	_, err = grpcutils.GetMetadataValue[int](ctx, "x-user")
+	i.True(err != nil)
+
+	_, err = grpcutils.GetMetadataValue[string](ctx, "x-missing")
+	i.True(errors.Is(err, grpcutils.ErrMetadataKeyNotPresent))
+}
+
+func TestPropagateIncomingToOutgoing(t *testing.T) {
+	i := is.New(t)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-request-id", "id",
+		"x-tenant", "t1",
+		"authorization", "bearer token",
+	))
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "x-caller", "users")
+
+	ctx = grpcutils.PropagateIncomingToOutgoing(ctx, "x-request-id", "x-tenant", "x-missing")
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	i.True(ok)
+
+	i.Equal(metadata.Pairs(
+		"x-request-id", "id",
+		"x-tenant", "t1",
+		"x-caller", "users",
+	), md)
+}
@@ -0,0 +1,40 @@
+package grpcutils
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc/peer"
+)
+
+// ErrPeerNotFound is returned when the context doesn't hold
+// the peer of the request.
+var ErrPeerNotFound = errors.New("peer not found")
+
+// ExtractPeer returns the address of the peer the request was received from.
+func ExtractPeer(ctx context.Context) (net.Addr, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return nil, ErrPeerNotFound
+	}
+
+	return p.Addr, nil
+}
+
+// ExtractPeerIP returns the address of the peer the request was
+// received from, without the port.
+// Addresses without a port, e.g. of unix sockets, are returned as is.
+func ExtractPeerIP(ctx context.Context) (string, error) {
+	addr, err := ExtractPeer(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String(), nil
+	}
+
+	return host, nil
+}
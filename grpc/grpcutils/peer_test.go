@@ -0,0 +1,42 @@
+package grpcutils_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/grpcutils"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtractPeer(t *testing.T) {
+	i := is.New(t)
+
+	_, err := grpcutils.ExtractPeer(context.Background())
+	i.True(errors.Is(err, grpcutils.ErrPeerNotFound))
+
+	_, err = grpcutils.ExtractPeerIP(context.Background())
+	i.True(errors.Is(err, grpcutils.ErrPeerNotFound))
+
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5432}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+	extracted, err := grpcutils.ExtractPeer(ctx)
+	i.NoErr(err)
+	i.Equal(addr.String(), extracted.String())
+
+	ip, err := grpcutils.ExtractPeerIP(ctx)
+	i.NoErr(err)
+	i.Equal("10.0.0.1", ip)
+
+	ctx = peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.UnixAddr{Name: "/tmp/grpc.sock", Net: "unix"},
+	})
+
+	ip, err = grpcutils.ExtractPeerIP(ctx)
+	i.NoErr(err)
+	i.Equal("/tmp/grpc.sock", ip)
+}
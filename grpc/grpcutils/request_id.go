@@ -3,7 +3,9 @@ package grpcutils
 import (
 	"context"
 	"errors"
+	"fmt"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"go.opentelemetry.io/otel/trace"
@@ -13,6 +15,7 @@ import (
 var (
 	ErrMetadataNotFound    = errors.New("metadata not found")
 	ErrRequestIDNotPresent = errors.New("request id not present")
+	ErrInvalidRequestID    = errors.New("invalid request id")
 )
 
 const requestIDHeader = "x-request-id"
@@ -38,6 +41,44 @@ func GetRequestIDFromCtx(ctx context.Context) (string, error) {
 	return requestIDs[0], nil
 }
 
+// GetValidatedRequestIDFromCtx returns the request id from the grpc
+// context, see GetRequestIDFromCtx, parsed as a version 4 uuid.
+//
+// ErrInvalidRequestID is returned if the request id is not a version 4
+// uuid, which is the case of the ids of the traces.
+func GetValidatedRequestIDFromCtx(ctx context.Context) (uuid.UUID, error) {
+	requestID, err := GetRequestIDFromCtx(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(requestID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %w", ErrInvalidRequestID, err)
+	}
+
+	if id.Version() != 4 {
+		return uuid.Nil, fmt.Errorf("%w: version %d", ErrInvalidRequestID, id.Version())
+	}
+
+	return id, nil
+}
+
+// SetRequestIDInCtx sets the request id in the incoming metadata of ctx,
+// as if it was sent by the client.
+func SetRequestIDInCtx(ctx context.Context, id uuid.UUID) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	md.Set(requestIDHeader, id.String())
+
+	return metadata.NewIncomingContext(ctx, md)
+}
+
 // AppendRequestIDCtx appends a random request id to the ctx.
 func AppendRequestIDCtx(
 	ctx context.Context,
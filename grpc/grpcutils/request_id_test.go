@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/matryer/is"
 	"github.com/purposeinplay/go-commons/grpc/grpcutils"
 	"google.golang.org/grpc/metadata"
@@ -58,3 +59,47 @@ func TestRequestID(t *testing.T) {
 
 	i.Equal("hello", id)
 }
+
+func TestValidatedRequestID(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		requestID := uuid.New()
+
+		ctx := grpcutils.SetRequestIDInCtx(context.Background(), requestID)
+
+		id, err := grpcutils.GetValidatedRequestIDFromCtx(ctx)
+		i.NoErr(err)
+		i.Equal(requestID, id)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		for _, requestID := range []string{"test", uuid.NewSHA1(uuid.NameSpaceDNS, []byte("a")).String()} {
+			ctx := metadata.NewIncomingContext(
+				context.Background(),
+				metadata.New(map[string]string{"x-request-id": requestID}),
+			)
+
+			id, err := grpcutils.GetValidatedRequestIDFromCtx(ctx)
+			i.True(errors.Is(err, grpcutils.ErrInvalidRequestID))
+			i.Equal(uuid.Nil, id)
+		}
+	})
+
+	t.Run("NotPresent", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := grpcutils.GetValidatedRequestIDFromCtx(context.Background())
+		i.True(errors.Is(err, grpcutils.ErrMetadataNotFound))
+	})
+}
@@ -0,0 +1,205 @@
+// Package health aggregates the health checks of the dependencies of a
+// service, e.g. databases or brokers, into grpc health responses.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCheckTimeout is the time after which a check is considered
+// failed, unless configured otherwise with WithCheckTimeout.
+const DefaultCheckTimeout = 5 * time.Second
+
+// ErrUnknownService is returned when no check is registered
+// for the requested service.
+var ErrUnknownService = errors.New("unknown service")
+
+// A CheckerOption configures the DependencyChecker.
+type CheckerOption interface {
+	apply(*checkerOptions)
+}
+
+type funcCheckerOption struct {
+	f func(*checkerOptions)
+}
+
+func (fo *funcCheckerOption) apply(o *checkerOptions) {
+	fo.f(o)
+}
+
+func newFuncCheckerOption(f func(*checkerOptions)) *funcCheckerOption {
+	return &funcCheckerOption{
+		f: f,
+	}
+}
+
+type checkerOptions struct {
+	timeouts map[string]time.Duration
+	logger   *zap.Logger
+}
+
+// WithCheckTimeout sets the timeout of the named check.
+func WithCheckTimeout(name string, d time.Duration) CheckerOption {
+	return newFuncCheckerOption(func(o *checkerOptions) {
+		o.timeouts[name] = d
+	})
+}
+
+// WithLogger sets the logger used to report the failed checks.
+func WithLogger(logger *zap.Logger) CheckerOption {
+	return newFuncCheckerOption(func(o *checkerOptions) {
+		o.logger = logger
+	})
+}
+
+// DependencyChecker runs the health checks of the dependencies.
+type DependencyChecker struct {
+	checks map[string]func(context.Context) error
+	opts   checkerOptions
+}
+
+// NewDependencyChecker returns a DependencyChecker running the given
+// checks, keyed by the name of the dependency.
+func NewDependencyChecker(
+	checks map[string]func(context.Context) error,
+	opts ...CheckerOption,
+) *DependencyChecker {
+	o := checkerOptions{
+		timeouts: make(map[string]time.Duration),
+		logger:   zap.L(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return &DependencyChecker{
+		checks: checks,
+		opts:   o,
+	}
+}
+
+// Check runs the check named service, or all the checks concurrently
+// if service is empty, and reports SERVING only if all of them pass.
+//
+// A warning is logged for each failed check.
+func (c *DependencyChecker) Check(
+	ctx context.Context,
+	service string,
+) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	if service != "" {
+		check, ok := c.checks[service]
+		if !ok {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, fmt.Errorf("%w: %s", ErrUnknownService, service)
+		}
+
+		if !c.run(ctx, service, check) {
+			return healthpb.HealthCheckResponse_NOT_SERVING, nil
+		}
+
+		return healthpb.HealthCheckResponse_SERVING, nil
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		serving = true
+	)
+
+	for name, check := range c.checks {
+		wg.Add(1)
+
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+
+			if !c.run(ctx, name, check) {
+				mu.Lock()
+				serving = false
+				mu.Unlock()
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	if !serving {
+		return healthpb.HealthCheckResponse_NOT_SERVING, nil
+	}
+
+	return healthpb.HealthCheckResponse_SERVING, nil
+}
+
+// run reports whether the check passed within its timeout.
+func (c *DependencyChecker) run(
+	ctx context.Context,
+	name string,
+	check func(context.Context) error,
+) bool {
+	timeout, ok := c.opts.timeouts[name]
+	if !ok {
+		timeout = DefaultCheckTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- check(ctx)
+	}()
+
+	var err error
+
+	// don't wait for checks that ignore the context.
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		c.opts.logger.Warn(
+			"dependency health check failed",
+			zap.String("dependency", name),
+			zap.Error(err),
+		)
+
+		return false
+	}
+
+	return true
+}
+
+// HealthServer returns a grpc health server answering the Check calls
+// with the DependencyChecker.
+func (c *DependencyChecker) HealthServer() healthpb.HealthServer {
+	return &healthServer{checker: c}
+}
+
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+
+	checker *DependencyChecker
+}
+
+func (s *healthServer) Check(
+	ctx context.Context,
+	req *healthpb.HealthCheckRequest,
+) (*healthpb.HealthCheckResponse, error) {
+	servingStatus, err := s.checker.Check(ctx, req.GetService())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &healthpb.HealthCheckResponse{Status: servingStatus}, nil
+}
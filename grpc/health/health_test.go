@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/health"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestDependencyChecker(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.WarnLevel)
+
+	checker := health.NewDependencyChecker(
+		map[string]func(context.Context) error{
+			"postgres": func(context.Context) error { return nil },
+			"kafka":    func(context.Context) error { return errors.New("no brokers") },
+			"redis": func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+		},
+		health.WithCheckTimeout("redis", 10*time.Millisecond),
+		health.WithLogger(zap.New(core)),
+	)
+
+	t.Run("Serving", func(t *testing.T) {
+		i := is.New(t)
+
+		servingStatus, err := checker.Check(context.Background(), "postgres")
+		i.NoErr(err)
+		i.Equal(healthpb.HealthCheckResponse_SERVING, servingStatus)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		i := is.New(t)
+
+		servingStatus, err := checker.Check(context.Background(), "redis")
+		i.NoErr(err)
+		i.Equal(healthpb.HealthCheckResponse_NOT_SERVING, servingStatus)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		i := is.New(t)
+
+		servingStatus, err := checker.HealthServer().Check(context.Background(), &healthpb.HealthCheckRequest{})
+		i.NoErr(err)
+		i.Equal(healthpb.HealthCheckResponse_NOT_SERVING, servingStatus.GetStatus())
+
+		i.Equal(1, logs.FilterField(zap.String("dependency", "kafka")).Len())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		i := is.New(t)
+
+		servingStatus, err := checker.Check(context.Background(), "mysql")
+		i.True(errors.Is(err, health.ErrUnknownService))
+		i.Equal(healthpb.HealthCheckResponse_SERVICE_UNKNOWN, servingStatus)
+	})
+}
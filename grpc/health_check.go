@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// defaultHealthCheckInterval is the default interval between two
+// health checks of the services.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// HealthChecker checks the health of the services of the server,
+// the empty service standing for the server as a whole.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error)
+}
+
+// healthPoller periodically updates the statuses of the grpc health
+// server with the ones reported by the checker.
+type healthPoller struct {
+	checker  HealthChecker
+	interval time.Duration
+	logger   *zap.Logger
+
+	server *health.Server
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+func newHealthPoller(checker HealthChecker) *healthPoller {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &healthPoller{
+		checker:  checker,
+		interval: defaultHealthCheckInterval,
+		logger:   zap.L(),
+		server:   health.NewServer(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// setHealthCheck registers a health server to the grpc server whose
+// statuses are updated every interval, while the server is serving.
+func (s *grpcServer) setHealthCheck(checker HealthChecker, interval time.Duration, logging *logging) {
+	p := newHealthPoller(checker)
+
+	if interval > 0 {
+		p.interval = interval
+	}
+
+	if logging != nil {
+		p.logger = logging.logger
+	}
+
+	healthpb.RegisterHealthServer(s.grpcServer, p.server)
+
+	s.health = p
+}
+
+// start checks the services right away, then every interval,
+// until stop is called.
+func (p *healthPoller) start(s *grpc.Server) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return
+	}
+
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.check(p.ctx, s)
+
+			select {
+			case <-p.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops the checks and sets all the services as NOT_SERVING.
+func (p *healthPoller) stop() {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+
+	p.cancel()
+
+	p.wg.Wait()
+
+	p.server.Shutdown()
+}
+
+func (p *healthPoller) check(ctx context.Context, s *grpc.Server) {
+	services := []string{""}
+
+	for service := range s.GetServiceInfo() {
+		if service != healthpb.Health_ServiceDesc.ServiceName {
+			services = append(services, service)
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, p.interval)
+	defer cancel()
+
+	for _, service := range services {
+		servingStatus, err := p.checker.CheckHealth(checkCtx, service)
+		if err != nil {
+			// the poller is being stopped.
+			if ctx.Err() != nil {
+				return
+			}
+
+			p.logger.Warn(
+				"health check failed",
+				zap.String("service", service),
+				zap.Error(err),
+			)
+
+			if servingStatus != healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+				servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+
+		p.server.SetServingStatus(service, servingStatus)
+	}
+}
@@ -0,0 +1,89 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// An AuditOption configures the audit requirement interceptor.
+type AuditOption interface {
+	apply(*auditOptions)
+}
+
+type funcAuditOption struct {
+	f func(*auditOptions)
+}
+
+func (fo *funcAuditOption) apply(o *auditOptions) {
+	fo.f(o)
+}
+
+func newFuncAuditOption(f func(*auditOptions)) *funcAuditOption {
+	return &funcAuditOption{
+		f: f,
+	}
+}
+
+type auditOptions struct {
+	logger *zap.Logger
+}
+
+// WithAuditLogger configures the logger the violations are reported to,
+// separately from the service logs. Violations are not logged by default.
+func WithAuditLogger(logger *zap.Logger) AuditOption {
+	return newFuncAuditOption(func(o *auditOptions) {
+		o.logger = logger
+	})
+}
+
+// NewAuditRequirement returns an interceptor that rejects, with an
+// Unauthenticated status listing the missing keys, the requests that
+// don't carry all the required metadata keys.
+//
+// The grpc health checking service is exempted.
+func NewAuditRequirement(requiredKeys []string, opts ...AuditOption) grpc.UnaryServerInterceptor {
+	o := auditOptions{
+		logger: zap.NewNop(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if missing := missingKeys(md, requiredKeys); len(missing) > 0 {
+			o.logger.Warn(
+				"audit requirement violation",
+				zap.String("method", info.FullMethod),
+				zap.Strings("missing_keys", missing),
+			)
+
+			return nil, status.Errorf(
+				codes.Unauthenticated,
+				"missing audit metadata: %s",
+				strings.Join(missing, ", "),
+			)
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,68 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuditRequirement(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	audit := interceptor.NewAuditRequirement(
+		[]string{"x-user-id", "x-audit-token"},
+		interceptor.WithAuditLogger(zap.New(core)),
+	)
+
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	t.Run("Missing", func(t *testing.T) {
+		i := is.New(t)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-user-id", "1"),
+		)
+
+		_, err := audit(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"}, handler)
+		i.Equal(codes.Unauthenticated, status.Code(err))
+		i.Equal("missing audit metadata: x-audit-token", status.Convert(err).Message())
+		i.Equal(1, logs.FilterMessage("audit requirement violation").Len())
+	})
+
+	t.Run("Present", func(t *testing.T) {
+		i := is.New(t)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-user-id", "1", "x-audit-token", "token"),
+		)
+
+		resp, err := audit(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"}, handler)
+		i.NoErr(err)
+		i.Equal("ok", resp)
+	})
+
+	t.Run("HealthCheck", func(t *testing.T) {
+		i := is.New(t)
+
+		_, err := audit(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+			handler,
+		)
+		i.NoErr(err)
+	})
+}
@@ -0,0 +1,222 @@
+package interceptor
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const clientTracerName = "github.com/purposeinplay/go-commons/grpc/interceptor"
+
+// NewClientTracingInterceptor returns a client interceptor that records
+// a span for every call and propagates its context to the server using
+// the global TextMapPropagator.
+func NewClientTracingInterceptor(tp oteltrace.TracerProvider) grpc.UnaryClientInterceptor {
+	tracer := tp.Tracer(clientTracerName)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		ctx, span := tracer.Start(
+			ctx,
+			method,
+			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+			oteltrace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.service", path.Dir(method)[1:]),
+				attribute.String("rpc.method", path.Base(method)),
+			),
+		)
+		defer span.End()
+
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+
+		otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+
+		err := invoker(metadata.NewOutgoingContext(ctx, md), method, req, reply, cc, opts...)
+
+		span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(status.Code(err))))
+
+		if err != nil {
+			span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+		}
+
+		return err
+	}
+}
+
+// metadataCarrier adapts metadata.MD to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
+
+// A RetryOption configures the client retry interceptor.
+type RetryOption interface {
+	apply(*retryOptions)
+}
+
+type funcRetryOption struct {
+	f func(*retryOptions)
+}
+
+func (fo *funcRetryOption) apply(o *retryOptions) {
+	fo.f(o)
+}
+
+func newFuncRetryOption(f func(*retryOptions)) *funcRetryOption {
+	return &funcRetryOption{
+		f: f,
+	}
+}
+
+type retryOptions struct {
+	maxRetries int
+	backoff    time.Duration
+	codes      []codes.Code
+}
+
+// WithMaxRetries configures how many times a call is retried, 3 by default.
+func WithMaxRetries(n int) RetryOption {
+	return newFuncRetryOption(func(o *retryOptions) {
+		o.maxRetries = n
+	})
+}
+
+// WithRetryBackoff configures the wait before the first retry, 100ms by
+// default. The wait doubles after each retry.
+func WithRetryBackoff(d time.Duration) RetryOption {
+	return newFuncRetryOption(func(o *retryOptions) {
+		o.backoff = d
+	})
+}
+
+// WithRetryCodes configures the status codes that are retried,
+// only Unavailable by default.
+func WithRetryCodes(c ...codes.Code) RetryOption {
+	return newFuncRetryOption(func(o *retryOptions) {
+		o.codes = c
+	})
+}
+
+// NewClientRetryInterceptor returns a client interceptor that retries,
+// with an exponential backoff, the calls failing with one of the
+// retryable status codes.
+//
+// Only idempotent methods should be called through this interceptor.
+func NewClientRetryInterceptor(opts ...RetryOption) grpc.UnaryClientInterceptor {
+	o := retryOptions{
+		maxRetries: 3,
+		backoff:    100 * time.Millisecond,
+		codes:      []codes.Code{codes.Unavailable},
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		callOpts ...grpc.CallOption,
+	) error {
+		backoff := o.backoff
+
+		for attempt := 0; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, callOpts...)
+			if err == nil || attempt >= o.maxRetries || !o.retryable(status.Code(err)) {
+				return err
+			}
+
+			timer := time.NewTimer(backoff)
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+
+				return err
+
+			case <-timer.C:
+			}
+
+			backoff *= 2
+		}
+	}
+}
+
+func (o retryOptions) retryable(code codes.Code) bool {
+	for _, c := range o.codes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewClientMetadataPropagator returns a client interceptor that copies
+// the given keys from the incoming metadata of the context to the
+// outgoing metadata, passing them along to the downstream services.
+func NewClientMetadataPropagator(keys ...string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		incoming, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		outgoing, _ := metadata.FromOutgoingContext(ctx)
+		outgoing = outgoing.Copy()
+
+		for _, key := range keys {
+			if values := incoming.Get(key); len(values) > 0 && len(outgoing.Get(key)) == 0 {
+				outgoing.Set(key, values...)
+			}
+		}
+
+		return invoker(metadata.NewOutgoingContext(ctx, outgoing), method, req, reply, cc, opts...)
+	}
+}
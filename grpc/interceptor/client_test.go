@@ -0,0 +1,130 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientTracingInterceptor(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+
+	tracing := interceptor.NewClientTracingInterceptor(
+		trace.NewTracerProvider(trace.WithSpanProcessor(recorder)),
+	)
+
+	var spanCtx oteltrace.SpanContext
+
+	err := tracing(
+		context.Background(),
+		"/greet.GreetService/Greet",
+		nil,
+		nil,
+		nil,
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			spanCtx = oteltrace.SpanContextFromContext(ctx)
+			return status.Error(codes.NotFound, "not found")
+		},
+	)
+	i.Equal(codes.NotFound, status.Code(err))
+
+	spans := recorder.Ended()
+	i.Equal(1, len(spans))
+	i.Equal("/greet.GreetService/Greet", spans[0].Name())
+	i.Equal(oteltrace.SpanKindClient, spans[0].SpanKind())
+	i.Equal(spans[0].SpanContext().SpanID(), spanCtx.SpanID())
+}
+
+func TestClientRetryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RetryableCode", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		var calls int
+
+		retry := interceptor.NewClientRetryInterceptor(
+			interceptor.WithMaxRetries(2),
+			interceptor.WithRetryBackoff(time.Millisecond),
+		)
+
+		err := retry(
+			context.Background(),
+			"/greet.GreetService/Greet",
+			nil,
+			nil,
+			nil,
+			func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				calls++
+				return status.Error(codes.Unavailable, "unavailable")
+			},
+		)
+		i.Equal(codes.Unavailable, status.Code(err))
+		i.Equal(3, calls)
+	})
+
+	t.Run("NonRetryableCode", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		var calls int
+
+		err := interceptor.NewClientRetryInterceptor()(
+			context.Background(),
+			"/greet.GreetService/Greet",
+			nil,
+			nil,
+			nil,
+			func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+				calls++
+				return status.Error(codes.InvalidArgument, "invalid")
+			},
+		)
+		i.Equal(codes.InvalidArgument, status.Code(err))
+		i.Equal(1, calls)
+	})
+}
+
+func TestClientMetadataPropagator(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	ctx := metadata.NewIncomingContext(
+		context.Background(),
+		metadata.Pairs("x-user-id", "1", "x-other", "2"),
+	)
+
+	err := interceptor.NewClientMetadataPropagator("x-user-id")(
+		ctx,
+		"/greet.GreetService/Greet",
+		nil,
+		nil,
+		nil,
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			i.Equal([]string{"1"}, md.Get("x-user-id"))
+			i.Equal(0, len(md.Get("x-other")))
+
+			return nil
+		},
+	)
+	i.NoErr(err)
+}
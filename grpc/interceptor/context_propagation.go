@@ -0,0 +1,96 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/purposeinplay/go-commons/grpc/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// The metadata keys read by the built-in extractors.
+const (
+	RequestIDMetadataKey = "x-request-id"
+	UserIDMetadataKey    = "x-user-id"
+)
+
+// A MetadataExtractor returns a copy of ctx holding the values
+// extracted from the incoming metadata md.
+type MetadataExtractor func(ctx context.Context, md metadata.MD) context.Context
+
+type (
+	ctxRequestIDKey struct{}
+	ctxUserIDKey    struct{}
+)
+
+// NewContextPropagationInterceptor returns an interceptor that runs the
+// extractors, in order, on the incoming metadata and passes the
+// resulting context to the handler.
+func NewContextPropagationInterceptor(extractors ...MetadataExtractor) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		for _, extract := range extractors {
+			ctx = extract(ctx, md)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ExtractRequestID stores the x-request-id metadata value in the
+// context, from where it is retrieved using RequestIDFromContext.
+func ExtractRequestID() MetadataExtractor {
+	return extractValue(RequestIDMetadataKey, func(ctx context.Context, v string) context.Context {
+		return context.WithValue(ctx, ctxRequestIDKey{}, v)
+	})
+}
+
+// ExtractUserID stores the x-user-id metadata value in the
+// context, from where it is retrieved using UserIDFromContext.
+func ExtractUserID() MetadataExtractor {
+	return extractValue(UserIDMetadataKey, func(ctx context.Context, v string) context.Context {
+		return context.WithValue(ctx, ctxUserIDKey{}, v)
+	})
+}
+
+// ExtractTenantID stores the value of the key metadata, e.g.
+// tenant.MetadataKey, as the tenant id of the context, from where
+// it is retrieved using tenant.FromContext.
+func ExtractTenantID(key string) MetadataExtractor {
+	return extractValue(key, tenant.WithTenantID)
+}
+
+// RequestIDFromContext returns the request id stored in ctx by ExtractRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxRequestIDKey{}).(string)
+
+	return id, ok
+}
+
+// UserIDFromContext returns the user id stored in ctx by ExtractUserID.
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxUserIDKey{}).(string)
+
+	return id, ok
+}
+
+// extractValue returns an extractor storing the first non empty value
+// of key with store. The context is left unchanged if there is none.
+func extractValue(
+	key string,
+	store func(ctx context.Context, v string) context.Context,
+) MetadataExtractor {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return store(ctx, values[0])
+		}
+
+		return ctx
+	}
+}
@@ -0,0 +1,69 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"github.com/purposeinplay/go-commons/grpc/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestContextPropagation(t *testing.T) {
+	t.Parallel()
+
+	propagate := interceptor.NewContextPropagationInterceptor(
+		interceptor.ExtractRequestID(),
+		interceptor.ExtractUserID(),
+		interceptor.ExtractTenantID(tenant.MetadataKey),
+	)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"}
+
+	t.Run("Present", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-request-id", "req", "x-user-id", "user", "x-tenant-id", "tenant"),
+		)
+
+		_, err := propagate(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+			requestID, ok := interceptor.RequestIDFromContext(ctx)
+			i.True(ok)
+			i.Equal("req", requestID)
+
+			userID, ok := interceptor.UserIDFromContext(ctx)
+			i.True(ok)
+			i.Equal("user", userID)
+
+			i.Equal("tenant", tenant.FromContext(ctx))
+
+			return nil, nil
+		})
+		i.NoErr(err)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := propagate(context.Background(), nil, info, func(ctx context.Context, _ any) (any, error) {
+			_, ok := interceptor.RequestIDFromContext(ctx)
+			i.True(!ok)
+
+			_, ok = interceptor.UserIDFromContext(ctx)
+			i.True(!ok)
+
+			i.Equal("", tenant.FromContext(ctx))
+
+			return nil, nil
+		})
+		i.NoErr(err)
+	})
+}
@@ -0,0 +1,86 @@
+// Package interceptor provides standalone grpc server and client
+// interceptors that can be plugged into any interceptor chain.
+package interceptor
+
+import (
+	"context"
+	"path"
+
+	"github.com/google/uuid"
+	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/purposeinplay/go-commons/grpc/ctxlog"
+	"github.com/purposeinplay/go-commons/grpc/grpcutils"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// NewLoggerInjector returns an interceptor that stores a child of the
+// base logger in the request context, from where it can be retrieved
+// using ctxlog.FromContext.
+//
+// The child logger is enriched with the method name, the request id and
+// the fields returned by the enrichFields functions.
+func NewLoggerInjector(
+	base *zap.Logger,
+	enrichFields ...func(ctx context.Context, info *grpc.UnaryServerInfo) []zap.Field,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		fields := requestFields(ctx, info.FullMethod)
+
+		for _, f := range enrichFields {
+			fields = append(fields, f(ctx, info)...)
+		}
+
+		return handler(ctxlog.WithLogger(ctx, base.With(fields...)), req)
+	}
+}
+
+// NewStreamLoggerInjector is the streaming counterpart of
+// NewLoggerInjector. Besides the method name and the request id,
+// the child logger is enriched with the address of the peer.
+func NewStreamLoggerInjector(
+	base *zap.Logger,
+	enrichFields ...func(ctx context.Context, info *grpc.StreamServerInfo) []zap.Field,
+) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx := stream.Context()
+
+		fields := requestFields(ctx, info.FullMethod)
+
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			fields = append(fields, zap.String("peer_address", p.Addr.String()))
+		}
+
+		for _, f := range enrichFields {
+			fields = append(fields, f(ctx, info)...)
+		}
+
+		wrappedStream := grpcmiddleware.WrapServerStream(stream)
+		wrappedStream.WrappedContext = ctxlog.WithLogger(ctx, base.With(fields...))
+
+		return handler(srv, wrappedStream)
+	}
+}
+
+func requestFields(ctx context.Context, fullMethod string) []zap.Field {
+	requestID, err := grpcutils.GetRequestIDFromCtx(ctx)
+	if err != nil {
+		requestID = uuid.Nil.String()
+	}
+
+	return []zap.Field{
+		zap.String("trace_id", requestID),
+		zap.String("method", path.Base(fullMethod)),
+	}
+}
@@ -0,0 +1,99 @@
+package interceptor_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/ctxlog"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestLoggerInjector(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Unary", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		injector := interceptor.NewLoggerInjector(
+			zap.New(core),
+			func(context.Context, *grpc.UnaryServerInfo) []zap.Field {
+				return []zap.Field{zap.String("custom", "value")}
+			},
+		)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-request-id", "id"),
+		)
+
+		_, err := injector(
+			ctx,
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"},
+			func(ctx context.Context, _ any) (any, error) {
+				ctxlog.FromContext(ctx).Info("handled")
+				return nil, nil
+			},
+		)
+		i.NoErr(err)
+
+		i.Equal(1, logs.Len())
+
+		fields := logs.All()[0].ContextMap()
+		i.Equal("id", fields["trace_id"])
+		i.Equal("Greet", fields["method"])
+		i.Equal("value", fields["custom"])
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		injector := interceptor.NewStreamLoggerInjector(zap.New(core))
+
+		ctx := peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080},
+		})
+
+		err := injector(
+			nil,
+			&testServerStream{ctx: ctx},
+			&grpc.StreamServerInfo{FullMethod: "/greet.GreetService/GreetStream"},
+			func(_ any, stream grpc.ServerStream) error {
+				ctxlog.FromContext(stream.Context()).Info("handled")
+				return nil
+			},
+		)
+		i.NoErr(err)
+
+		i.Equal(1, logs.Len())
+
+		fields := logs.All()[0].ContextMap()
+		i.Equal("GreetStream", fields["method"])
+		i.Equal("127.0.0.1:8080", fields["peer_address"])
+	})
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
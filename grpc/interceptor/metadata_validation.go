@@ -0,0 +1,104 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/purposeinplay/go-commons/grpc/ctxlog"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// A MetadataValidationOption configures the metadata validation interceptor.
+type MetadataValidationOption interface {
+	apply(*metadataValidationOptions)
+}
+
+type funcMetadataValidationOption struct {
+	f func(*metadataValidationOptions)
+}
+
+func (fo *funcMetadataValidationOption) apply(o *metadataValidationOptions) {
+	fo.f(o)
+}
+
+func newFuncMetadataValidationOption(f func(*metadataValidationOptions)) *funcMetadataValidationOption {
+	return &funcMetadataValidationOption{
+		f: f,
+	}
+}
+
+type metadataValidationOptions struct {
+	optional []string
+}
+
+// WithOptional sets the metadata keys whose absence is logged as a
+// warning, with the logger of the request context, without rejecting
+// the request.
+func WithOptional(keys ...string) MetadataValidationOption {
+	return newFuncMetadataValidationOption(func(o *metadataValidationOptions) {
+		o.optional = append(o.optional, keys...)
+	})
+}
+
+// NewMetadataValidationInterceptor returns an interceptor that rejects,
+// with an InvalidArgument status listing the missing keys, the requests
+// that don't carry all the required metadata keys.
+//
+// The grpc health checking service is exempted.
+func NewMetadataValidationInterceptor(
+	required []string,
+	opts ...MetadataValidationOption,
+) grpc.UnaryServerInterceptor {
+	var o metadataValidationOptions
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if missing := missingKeys(md, required); len(missing) > 0 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				"missing required metadata: %s",
+				strings.Join(missing, ", "),
+			)
+		}
+
+		if missing := missingKeys(md, o.optional); len(missing) > 0 {
+			ctxlog.FromContext(ctx).Warn(
+				"missing optional metadata",
+				zap.String("method", info.FullMethod),
+				zap.Strings("missing_keys", missing),
+			)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func missingKeys(md metadata.MD, keys []string) []string {
+	var missing []string
+
+	for _, key := range keys {
+		if len(md.Get(key)) == 0 {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,75 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/ctxlog"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetadataValidation(t *testing.T) {
+	t.Parallel()
+
+	validate := interceptor.NewMetadataValidationInterceptor(
+		[]string{"x-tenant-id", "x-user-id"},
+		interceptor.WithOptional("x-client-version"),
+	)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"}
+
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	t.Run("MissingRequired", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := validate(context.Background(), nil, info, handler)
+		i.Equal(codes.InvalidArgument, status.Code(err))
+		i.Equal("missing required metadata: x-tenant-id, x-user-id", status.Convert(err).Message())
+	})
+
+	t.Run("MissingOptional", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		ctx := metadata.NewIncomingContext(
+			ctxlog.WithLogger(context.Background(), zap.New(core)),
+			metadata.Pairs("x-tenant-id", "1", "x-user-id", "2"),
+		)
+
+		resp, err := validate(ctx, nil, info, handler)
+		i.NoErr(err)
+		i.Equal("ok", resp)
+
+		entries := logs.FilterMessage("missing optional metadata").All()
+		i.Equal(1, len(entries))
+		i.Equal(zapcore.WarnLevel, entries[0].Level)
+	})
+
+	t.Run("HealthCheck", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := validate(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+			handler,
+		)
+		i.NoErr(err)
+	})
+}
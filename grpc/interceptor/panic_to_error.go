@@ -0,0 +1,94 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// A PanicOption configures the panic to error interceptor.
+type PanicOption interface {
+	apply(*panicOptions)
+}
+
+type funcPanicOption struct {
+	f func(*panicOptions)
+}
+
+func (fo *funcPanicOption) apply(o *panicOptions) {
+	fo.f(o)
+}
+
+func newFuncPanicOption(f func(*panicOptions)) *funcPanicOption {
+	return &funcPanicOption{
+		f: f,
+	}
+}
+
+type panicOptions struct {
+	callback func(any)
+}
+
+// WithPanicCallback configures a function called with the recovered
+// value after the panic is logged.
+func WithPanicCallback(f func(any)) PanicOption {
+	return newFuncPanicOption(func(o *panicOptions) {
+		o.callback = f
+	})
+}
+
+// NewPanicToError returns an interceptor that recovers the panics of the
+// handler, logs them together with the stack trace and returns an
+// Internal status error to the client.
+//
+// It is a lightweight alternative to the PanicHandler server option for
+// services that don't report the panics to an external service.
+func NewPanicToError(logger *zap.Logger, opts ...PanicOption) grpc.UnaryServerInterceptor {
+	var o panicOptions
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (_ any, err error) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			logger.Error(
+				"panic recovered",
+				zap.Any("panic", r),
+				zap.String("method", info.FullMethod),
+				zap.ByteString("stack", debug.Stack()),
+			)
+
+			if o.callback != nil {
+				o.callback(r)
+			}
+
+			err = status.Error(codes.Internal, "internal error")
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// NewPanicRecoverLogger returns an interceptor that recovers the panics
+// of the handler, logs them with the full stack trace and returns an
+// Internal status error.
+//
+// It is equivalent to NewPanicToError without options.
+func NewPanicRecoverLogger(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return NewPanicToError(logger)
+}
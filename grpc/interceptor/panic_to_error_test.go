@@ -0,0 +1,67 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPanicToError(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	var recovered any
+
+	panicToError := interceptor.NewPanicToError(
+		zap.New(core),
+		interceptor.WithPanicCallback(func(r any) { recovered = r }),
+	)
+
+	resp, err := panicToError(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"},
+		func(context.Context, any) (any, error) {
+			panic("boom")
+		},
+	)
+	i.Equal(nil, resp)
+	i.Equal(codes.Internal, status.Code(err))
+	i.Equal("boom", recovered)
+
+	i.Equal(1, logs.FilterMessage("panic recovered").Len())
+	i.Equal(zapcore.ErrorLevel, logs.All()[0].Level)
+}
+
+func TestPanicRecoverLogger(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	_, err := interceptor.NewPanicRecoverLogger(zap.New(core))(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"},
+		func(context.Context, any) (any, error) {
+			panic("boom")
+		},
+	)
+	i.Equal(codes.Internal, status.Code(err))
+
+	entries := logs.FilterMessage("panic recovered").All()
+	i.Equal(1, len(entries))
+	i.True(len(entries[0].ContextMap()["stack"].(string)) > 0)
+}
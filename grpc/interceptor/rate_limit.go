@@ -0,0 +1,127 @@
+package interceptor
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRateLimitKey is the key of the limits applied
+// to the methods that are not configured explicitly.
+const DefaultRateLimitKey = "*"
+
+// NewRateLimitInterceptor returns an interceptor that limits the rate of
+// the requests of each method to the limit configured under its full
+// name, e.g. "/users.Users/Get", allowing bursts of up to burst requests.
+//
+// The requests exceeding the limit fail with a ResourceExhausted status.
+// Methods without a limit fall back to the DefaultRateLimitKey limit,
+// they are not limited when it is not configured.
+func NewRateLimitInterceptor(limits map[string]rate.Limit, burst int) grpc.UnaryServerInterceptor {
+	limiters := newMethodLimiters(limits, burst)
+
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if err := limiters.allow(info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// NewRateLimitStreamInterceptor is the stream counterpart of
+// NewRateLimitInterceptor, it takes a token for every message
+// received on the stream.
+func NewRateLimitStreamInterceptor(limits map[string]rate.Limit, burst int) grpc.StreamServerInterceptor {
+	limiters := newMethodLimiters(limits, burst)
+
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &rateLimitedServerStream{
+			ServerStream: ss,
+			allow: func() error {
+				return limiters.allow(info.FullMethod)
+			},
+		})
+	}
+}
+
+type rateLimitedServerStream struct {
+	grpc.ServerStream
+
+	allow func() error
+}
+
+// RecvMsg fails without receiving the message when the limit is exceeded.
+func (s *rateLimitedServerStream) RecvMsg(m any) error {
+	if err := s.allow(); err != nil {
+		return err
+	}
+
+	return s.ServerStream.RecvMsg(m)
+}
+
+// methodLimiters creates the limiters of the methods on their first call.
+type methodLimiters struct {
+	limits map[string]rate.Limit
+	burst  int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newMethodLimiters(limits map[string]rate.Limit, burst int) *methodLimiters {
+	return &methodLimiters{
+		limits:   limits,
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (l *methodLimiters) allow(method string) error {
+	limiter := l.limiter(method)
+
+	if limiter != nil && !limiter.Allow() {
+		return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", method)
+	}
+
+	return nil
+}
+
+// limiter returns nil for the methods that are not limited.
+func (l *methodLimiters) limiter(method string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if limiter, ok := l.limiters[method]; ok {
+		return limiter
+	}
+
+	limit, ok := l.limits[method]
+	if !ok {
+		limit, ok = l.limits[DefaultRateLimitKey]
+	}
+
+	var limiter *rate.Limiter
+
+	if ok {
+		limiter = rate.NewLimiter(limit, l.burst)
+	}
+
+	l.limiters[method] = limiter
+
+	return limiter
+}
@@ -0,0 +1,91 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRateLimitInterceptor(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	// the limits are low enough to not refill the bucket during the test.
+	rateLimit := interceptor.NewRateLimitInterceptor(map[string]rate.Limit{
+		"/users.Users/Get":              rate.Limit(0.001),
+		"/users.Users/List":             rate.Limit(0.001),
+		interceptor.DefaultRateLimitKey: rate.Limit(0.001),
+	}, 2)
+
+	call := func(method string) error {
+		_, err := rateLimit(
+			context.Background(),
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: method},
+			func(context.Context, any) (any, error) { return nil, nil },
+		)
+
+		return err
+	}
+
+	// the burst is allowed.
+	i.NoErr(call("/users.Users/Get"))
+	i.NoErr(call("/users.Users/Get"))
+	i.Equal(codes.ResourceExhausted, status.Code(call("/users.Users/Get")))
+
+	// the methods are limited independently.
+	i.NoErr(call("/users.Users/List"))
+	i.NoErr(call("/users.Users/List"))
+	i.Equal(codes.ResourceExhausted, status.Code(call("/users.Users/List")))
+
+	// the unconfigured methods use the default limit.
+	i.NoErr(call("/users.Users/Delete"))
+	i.NoErr(call("/users.Users/Delete"))
+	i.Equal(codes.ResourceExhausted, status.Code(call("/users.Users/Delete")))
+}
+
+func TestRateLimitStreamInterceptor(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	rateLimit := interceptor.NewRateLimitStreamInterceptor(map[string]rate.Limit{
+		"/users.Users/Watch": rate.Limit(0.001),
+	}, 2)
+
+	var recvErrs []error
+
+	err := rateLimit(
+		nil,
+		&recvServerStream{},
+		&grpc.StreamServerInfo{FullMethod: "/users.Users/Watch"},
+		func(_ any, stream grpc.ServerStream) error {
+			for j := 0; j < 3; j++ {
+				recvErrs = append(recvErrs, stream.RecvMsg(nil))
+			}
+
+			return nil
+		},
+	)
+	i.NoErr(err)
+
+	i.NoErr(recvErrs[0])
+	i.NoErr(recvErrs[1])
+	i.Equal(codes.ResourceExhausted, status.Code(recvErrs[2]))
+}
+
+// recvServerStream receives empty messages.
+type recvServerStream struct {
+	grpc.ServerStream
+}
+
+func (*recvServerStream) RecvMsg(any) error {
+	return nil
+}
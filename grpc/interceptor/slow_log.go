@@ -0,0 +1,49 @@
+package interceptor
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// slowLogStackSize is the maximum size of the captured stack traces.
+const slowLogStackSize = 64 << 10
+
+// NewSlowLogInterceptor returns an interceptor that logs a warning with
+// the stack traces of the running goroutines once a handler runs for
+// longer than threshold, while the handler is still running.
+//
+// The stack traces of all the goroutines are captured, since the trace
+// of the watchdog goroutine alone would not show where the handler
+// is blocked.
+func NewSlowLogInterceptor(threshold time.Duration, logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+
+		timer := time.AfterFunc(threshold, func() {
+			buf := make([]byte, slowLogStackSize)
+
+			buf = buf[:runtime.Stack(buf, true)]
+
+			logger.Warn(
+				"slow request",
+				zap.String("method", info.FullMethod),
+				zap.Duration("elapsed", time.Since(start)),
+				zap.ByteString("stack", buf),
+			)
+		})
+
+		// a watchdog already running is not interrupted.
+		defer timer.Stop()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,63 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+)
+
+func TestSlowLogInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handlerDuration time.Duration
+		expectedLogs    int
+	}{
+		"Fast": {
+			handlerDuration: 0,
+			expectedLogs:    0,
+		},
+		"Slow": {
+			handlerDuration: 100 * time.Millisecond,
+			expectedLogs:    1,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i := is.New(t)
+
+			core, logs := observer.New(zapcore.WarnLevel)
+
+			slowLog := interceptor.NewSlowLogInterceptor(20*time.Millisecond, zap.New(core))
+
+			var logsWhileRunning int
+
+			_, err := slowLog(
+				context.Background(),
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: "/greet.GreetService/Greet"},
+				func(context.Context, any) (any, error) {
+					time.Sleep(test.handlerDuration)
+
+					logsWhileRunning = logs.FilterMessage("slow request").Len()
+
+					return nil, nil
+				},
+			)
+			i.NoErr(err)
+			i.Equal(test.expectedLogs, logsWhileRunning)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorMapping maps the errors matching Target to a status
+// with the given Code and Message.
+type ErrorMapping struct {
+	Target  error
+	Code    codes.Code
+	Message string
+}
+
+// MapNotFound maps target to a NotFound status carrying its message.
+func MapNotFound(target error) ErrorMapping {
+	return ErrorMapping{Target: target, Code: codes.NotFound, Message: target.Error()}
+}
+
+// MapAlreadyExists maps target to an AlreadyExists status carrying its message.
+func MapAlreadyExists(target error) ErrorMapping {
+	return ErrorMapping{Target: target, Code: codes.AlreadyExists, Message: target.Error()}
+}
+
+// MapPermissionDenied maps target to a PermissionDenied status carrying its message.
+func MapPermissionDenied(target error) ErrorMapping {
+	return ErrorMapping{Target: target, Code: codes.PermissionDenied, Message: target.Error()}
+}
+
+// NewStatusCodeMapper returns an interceptor that converts the errors
+// returned by the handlers to the status of the first mapping whose
+// Target matches the error, using errors.Is.
+//
+// Errors that don't match any mapping are returned unchanged.
+func NewStatusCodeMapper(mappings ...ErrorMapping) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+
+		for _, m := range mappings {
+			if errors.Is(err, m.Target) {
+				return nil, status.Error(m.Code, m.Message)
+			}
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,70 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatusCodeMapper(t *testing.T) {
+	t.Parallel()
+
+	var (
+		errUserNotFound = errors.New("user not found")
+		errUserExists   = errors.New("user exists")
+		errForbidden    = errors.New("forbidden")
+	)
+
+	mapper := interceptor.NewStatusCodeMapper(
+		interceptor.MapNotFound(errUserNotFound),
+		interceptor.MapAlreadyExists(errUserExists),
+		interceptor.MapPermissionDenied(errForbidden),
+	)
+
+	tests := map[string]struct {
+		err          error
+		expectedCode codes.Code
+	}{
+		"NotFound": {
+			err:          fmt.Errorf("get user: %w", errUserNotFound),
+			expectedCode: codes.NotFound,
+		},
+		"AlreadyExists": {
+			err:          errUserExists,
+			expectedCode: codes.AlreadyExists,
+		},
+		"PermissionDenied": {
+			err:          errForbidden,
+			expectedCode: codes.PermissionDenied,
+		},
+		"Unmapped": {
+			err:          errors.New("unknown"),
+			expectedCode: codes.Unknown,
+		},
+		"NoError": {
+			expectedCode: codes.OK,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i := is.New(t)
+
+			_, err := mapper(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, any) (any, error) {
+				return nil, test.err
+			})
+			i.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+}
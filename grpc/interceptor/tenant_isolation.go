@@ -0,0 +1,34 @@
+package interceptor
+
+import (
+	"context"
+
+	"github.com/purposeinplay/go-commons/grpc/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewTenantIsolation returns an interceptor that scopes every request
+// to the tenant returned by extractor, e.g. tenant.FromIncomingMetadata.
+//
+// The tenant id is stored in the context, from where the handlers
+// retrieve it using tenant.FromContext. Requests for which the extractor
+// fails are rejected with an Unauthenticated status.
+func NewTenantIsolation(
+	extractor func(ctx context.Context) (string, error),
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		tenantID, err := extractor(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "extract tenant id: %s", err)
+		}
+
+		return handler(tenant.WithTenantID(ctx, tenantID), req)
+	}
+}
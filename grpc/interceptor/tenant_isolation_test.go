@@ -0,0 +1,44 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"github.com/purposeinplay/go-commons/grpc/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	t.Parallel()
+
+	isolation := interceptor.NewTenantIsolation(tenant.FromIncomingMetadata)
+
+	handler := func(ctx context.Context, _ any) (any, error) {
+		return tenant.RequireTenantID(ctx)
+	}
+
+	t.Run("WithTenant", func(t *testing.T) {
+		i := is.New(t)
+
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("x-tenant-id", "acme"),
+		)
+
+		resp, err := isolation(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+		i.NoErr(err)
+		i.Equal("acme", resp)
+	})
+
+	t.Run("WithoutTenant", func(t *testing.T) {
+		i := is.New(t)
+
+		_, err := isolation(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		i.Equal(codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,116 @@
+package interceptor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// VersionMetadataKey is the incoming metadata key carrying
+	// the api version requested by the client.
+	VersionMetadataKey = "x-api-version"
+
+	// VersionMismatchReason is the reason of the errdetails.ErrorInfo
+	// detail attached to the version mismatch errors.
+	VersionMismatchReason = "VERSION_MISMATCH"
+)
+
+// A VersionGuardOption configures the version guard interceptor.
+type VersionGuardOption interface {
+	apply(*versionGuardOptions)
+}
+
+type funcVersionGuardOption struct {
+	f func(*versionGuardOptions)
+}
+
+func (fo *funcVersionGuardOption) apply(o *versionGuardOptions) {
+	fo.f(o)
+}
+
+func newFuncVersionGuardOption(f func(*versionGuardOptions)) *funcVersionGuardOption {
+	return &funcVersionGuardOption{
+		f: f,
+	}
+}
+
+type versionGuardOptions struct {
+	extractor func(ctx context.Context) string
+}
+
+// WithVersionExtractor sets the function returning the version requested
+// by the client, instead of reading it from the x-api-version metadata.
+func WithVersionExtractor(f func(ctx context.Context) string) VersionGuardOption {
+	return newFuncVersionGuardOption(func(o *versionGuardOptions) {
+		o.extractor = f
+	})
+}
+
+// NewVersionGuard returns an interceptor that rejects the requests for
+// versions that are not in supportedVersions with a FailedPrecondition
+// status, carrying an errdetails.ErrorInfo detail with the
+// VersionMismatchReason.
+//
+// supportedVersions must be ordered from the oldest to the newest,
+// requests without a version are treated as requests for the oldest one.
+func NewVersionGuard(supportedVersions []string, opts ...VersionGuardOption) grpc.UnaryServerInterceptor {
+	o := versionGuardOptions{
+		extractor: versionFromIncomingMetadata,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		version := o.extractor(ctx)
+
+		if version == "" && len(supportedVersions) > 0 {
+			version = supportedVersions[0]
+		}
+
+		if !slices.Contains(supportedVersions, version) {
+			return nil, versionMismatchError(version, supportedVersions)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func versionFromIncomingMetadata(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, VersionMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+func versionMismatchError(version string, supportedVersions []string) error {
+	st := status.Newf(codes.FailedPrecondition, "unsupported api version %q", version)
+
+	st, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: VersionMismatchReason,
+		Metadata: map[string]string{
+			"version":            version,
+			"supported_versions": strings.Join(supportedVersions, ","),
+		},
+	})
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "unsupported api version %q", version)
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,82 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/grpc/interceptor"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestVersionGuard(t *testing.T) {
+	t.Parallel()
+
+	guard := interceptor.NewVersionGuard([]string{"v1", "v2"})
+
+	handler := func(context.Context, any) (any, error) {
+		return "ok", nil
+	}
+
+	tests := map[string]struct {
+		md           metadata.MD
+		expectedCode codes.Code
+	}{
+		"Supported": {
+			md:           metadata.Pairs(interceptor.VersionMetadataKey, "v2"),
+			expectedCode: codes.OK,
+		},
+		"Empty": {
+			md:           metadata.MD{},
+			expectedCode: codes.OK,
+		},
+		"Unsupported": {
+			md:           metadata.Pairs(interceptor.VersionMetadataKey, "v3"),
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i := is.New(t)
+
+			_, err := guard(
+				metadata.NewIncomingContext(context.Background(), test.md),
+				nil,
+				&grpc.UnaryServerInfo{},
+				handler,
+			)
+			i.Equal(test.expectedCode, status.Code(err))
+
+			if err == nil {
+				return
+			}
+
+			details := status.Convert(err).Details()
+			i.Equal(1, len(details))
+			i.Equal(interceptor.VersionMismatchReason, details[0].(*errdetails.ErrorInfo).GetReason())
+		})
+	}
+
+	t.Run("Extractor", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		guard := interceptor.NewVersionGuard(
+			[]string{"v1"},
+			interceptor.WithVersionExtractor(func(context.Context) string { return "v2" }),
+		)
+
+		_, err := guard(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+		i.Equal(codes.FailedPrecondition, status.Code(err))
+	})
+}
@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMemoryStatsInterval is the default minimum interval between
+// two reads of the memory stats.
+const defaultMemoryStatsInterval = 100 * time.Millisecond
+
+// memoryShedder rejects requests while the heap in use is above the
+// high watermark, until it drops below the low watermark.
+type memoryShedder struct {
+	highWatermark, lowWatermark uint64
+	interval                    time.Duration
+	logger                      *zap.Logger
+
+	mu       sync.Mutex
+	readAt   time.Time
+	shedding bool
+}
+
+func newMemoryShedder(highWatermark, lowWatermark uint64) *memoryShedder {
+	return &memoryShedder{
+		highWatermark: highWatermark,
+		lowWatermark:  lowWatermark,
+		interval:      defaultMemoryStatsInterval,
+		logger:        zap.L(),
+	}
+}
+
+// shed reports whether the request should be rejected.
+func (m *memoryShedder) shed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if time.Since(m.readAt) < m.interval {
+		return m.shedding
+	}
+
+	var stats runtime.MemStats
+
+	runtime.ReadMemStats(&stats)
+
+	m.readAt = time.Now()
+
+	switch {
+	case !m.shedding && stats.HeapInuse > m.highWatermark:
+		m.shedding = true
+
+		m.logger.Warn(
+			"memory shedding activated",
+			zap.Uint64("heap_inuse", stats.HeapInuse),
+			zap.Uint64("high_watermark", m.highWatermark),
+		)
+
+	case m.shedding && stats.HeapInuse < m.lowWatermark:
+		m.shedding = false
+
+		m.logger.Info(
+			"memory shedding deactivated",
+			zap.Uint64("heap_inuse", stats.HeapInuse),
+			zap.Uint64("low_watermark", m.lowWatermark),
+		)
+	}
+
+	return m.shedding
+}
+
+func (m *memoryShedder) unaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		if m.shed() {
+			return nil, status.Error(codes.ResourceExhausted, "server is low on memory")
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,227 @@
+// Package metrics exports prometheus metrics of the grpc server.
+//
+// The metrics and their labels are the ones exported by
+// github.com/grpc-ecosystem/go-grpc-prometheus, so the existing
+// dashboards keep working.
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// The grpc_type label values.
+const (
+	unary        = "unary"
+	clientStream = "client_stream"
+	serverStream = "server_stream"
+	bidiStream   = "bidi_stream"
+)
+
+// A MetricsOption configures the prometheus interceptors.
+type MetricsOption interface {
+	apply(*metricsOptions)
+}
+
+type funcMetricsOption struct {
+	f func(*metricsOptions)
+}
+
+func (fo *funcMetricsOption) apply(o *metricsOptions) {
+	fo.f(o)
+}
+
+func newFuncMetricsOption(f func(*metricsOptions)) *funcMetricsOption {
+	return &funcMetricsOption{
+		f: f,
+	}
+}
+
+type metricsOptions struct {
+	registerer prometheus.Registerer
+	buckets    []float64
+}
+
+// WithRegisterer sets the registerer of the metrics.
+// Defaults to prometheus.DefaultRegisterer.
+func WithRegisterer(reg prometheus.Registerer) MetricsOption {
+	return newFuncMetricsOption(func(o *metricsOptions) {
+		o.registerer = reg
+	})
+}
+
+// WithHandlingTimeBuckets sets the buckets of the handling time histogram.
+// Defaults to prometheus.DefBuckets.
+func WithHandlingTimeBuckets(buckets []float64) MetricsOption {
+	return newFuncMetricsOption(func(o *metricsOptions) {
+		o.buckets = buckets
+	})
+}
+
+// NewPrometheusInterceptor returns the unary and the stream interceptors
+// exporting the grpc_server_handled_total counter, the
+// grpc_server_handling_seconds histogram and the
+// grpc_server_msg_received_total and grpc_server_msg_sent_total counters,
+// labeled by grpc_type, grpc_service and grpc_method, and by grpc_code
+// for the handled requests.
+//
+// The metrics are registered when the interceptors are created, it panics
+// if they are already registered with the registerer.
+func NewPrometheusInterceptor(
+	opts ...MetricsOption,
+) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	o := metricsOptions{
+		registerer: prometheus.DefaultRegisterer,
+		buckets:    prometheus.DefBuckets,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	m := newServerMetrics(o.buckets)
+
+	o.registerer.MustRegister(m.handled, m.handlingSeconds, m.msgReceived, m.msgSent)
+
+	return m.unaryInterceptor, m.streamInterceptor
+}
+
+type serverMetrics struct {
+	handled         *prometheus.CounterVec
+	handlingSeconds *prometheus.HistogramVec
+	msgReceived     *prometheus.CounterVec
+	msgSent         *prometheus.CounterVec
+}
+
+func newServerMetrics(buckets []float64) *serverMetrics {
+	labels := []string{"grpc_type", "grpc_service", "grpc_method"}
+
+	return &serverMetrics{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(labels, "grpc_code")),
+		handlingSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of response latency (seconds) of gRPC that had been application-level handled by the server.",
+			Buckets: buckets,
+		}, labels),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of RPC stream messages received on the server.",
+		}, labels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of gRPC stream messages sent by the server.",
+		}, labels),
+	}
+}
+
+func (m *serverMetrics) unaryInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+
+	service, method := splitMethodName(info.FullMethod)
+
+	m.msgReceived.WithLabelValues(unary, service, method).Inc()
+
+	resp, err := handler(ctx, req)
+	if err == nil {
+		m.msgSent.WithLabelValues(unary, service, method).Inc()
+	}
+
+	m.observe(unary, service, method, start, err)
+
+	return resp, err
+}
+
+func (m *serverMetrics) streamInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+
+	typ := streamType(info)
+
+	service, method := splitMethodName(info.FullMethod)
+
+	err := handler(srv, &monitoredServerStream{
+		ServerStream: ss,
+		received:     m.msgReceived.WithLabelValues(typ, service, method),
+		sent:         m.msgSent.WithLabelValues(typ, service, method),
+	})
+
+	m.observe(typ, service, method, start, err)
+
+	return err
+}
+
+func (m *serverMetrics) observe(typ, service, method string, start time.Time, err error) {
+	m.handled.WithLabelValues(typ, service, method, status.Code(err).String()).Inc()
+	m.handlingSeconds.WithLabelValues(typ, service, method).Observe(time.Since(start).Seconds())
+}
+
+// monitoredServerStream counts the messages received and sent
+// through the stream.
+type monitoredServerStream struct {
+	grpc.ServerStream
+
+	received, sent prometheus.Counter
+}
+
+func (s *monitoredServerStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent.Inc()
+	}
+
+	return err
+}
+
+func (s *monitoredServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received.Inc()
+	}
+
+	return err
+}
+
+func streamType(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return bidiStream
+
+	case info.IsClientStream:
+		return clientStream
+
+	case info.IsServerStream:
+		return serverStream
+
+	default:
+		return unary
+	}
+}
+
+// splitMethodName splits "/package.Service/Method" into its
+// service and method names.
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+
+	if i := strings.Index(fullMethod, "/"); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+
+	return "unknown", "unknown"
+}
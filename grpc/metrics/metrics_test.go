@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/purposeinplay/go-commons/grpc/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrometheusInterceptor(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	reg := prometheus.NewRegistry()
+
+	unaryInterceptor, streamInterceptor := metrics.NewPrometheusInterceptor(
+		metrics.WithRegisterer(reg),
+	)
+
+	_, err := unaryInterceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"},
+		func(context.Context, any) (any, error) { return nil, nil },
+	)
+	i.NoErr(err)
+
+	_, err = unaryInterceptor(
+		context.Background(),
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"},
+		func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.NotFound, "not found")
+		},
+	)
+	i.Equal(codes.NotFound, status.Code(err))
+
+	err = streamInterceptor(
+		nil,
+		&mockServerStream{},
+		&grpc.StreamServerInfo{FullMethod: "/users.Users/Watch", IsServerStream: true},
+		func(_ any, ss grpc.ServerStream) error {
+			i.NoErr(ss.RecvMsg(nil))
+			i.NoErr(ss.SendMsg(nil))
+			i.NoErr(ss.SendMsg(nil))
+
+			return nil
+		},
+	)
+	i.NoErr(err)
+
+	i.NoErr(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP grpc_server_handled_total Total number of RPCs completed on the server, regardless of success or failure.
+# TYPE grpc_server_handled_total counter
+grpc_server_handled_total{grpc_code="NotFound",grpc_method="Get",grpc_service="users.Users",grpc_type="unary"} 1
+grpc_server_handled_total{grpc_code="OK",grpc_method="Get",grpc_service="users.Users",grpc_type="unary"} 1
+grpc_server_handled_total{grpc_code="OK",grpc_method="Watch",grpc_service="users.Users",grpc_type="server_stream"} 1
+# HELP grpc_server_msg_received_total Total number of RPC stream messages received on the server.
+# TYPE grpc_server_msg_received_total counter
+grpc_server_msg_received_total{grpc_method="Get",grpc_service="users.Users",grpc_type="unary"} 2
+grpc_server_msg_received_total{grpc_method="Watch",grpc_service="users.Users",grpc_type="server_stream"} 1
+# HELP grpc_server_msg_sent_total Total number of gRPC stream messages sent by the server.
+# TYPE grpc_server_msg_sent_total counter
+grpc_server_msg_sent_total{grpc_method="Get",grpc_service="users.Users",grpc_type="unary"} 1
+grpc_server_msg_sent_total{grpc_method="Watch",grpc_service="users.Users",grpc_type="server_stream"} 2
+`),
+		"grpc_server_handled_total",
+		"grpc_server_msg_received_total",
+		"grpc_server_msg_sent_total",
+	))
+
+	i.Equal(2, testutil.CollectAndCount(reg, "grpc_server_handling_seconds"))
+}
+
+type mockServerStream struct {
+	grpc.ServerStream
+}
+
+func (*mockServerStream) Context() context.Context { return context.Background() }
+
+func (*mockServerStream) SendMsg(any) error { return nil }
+
+func (*mockServerStream) RecvMsg(any) error { return nil }
@@ -2,8 +2,12 @@ package grpc
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
@@ -11,13 +15,20 @@ import (
 	grpcrecovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	grpcctxtags "github.com/grpc-ecosystem/go-grpc-middleware/tags"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/purposeinplay/go-commons/grpc/metrics"
 	"github.com/rs/cors"
+	octrace "go.opencensus.io/trace"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/tap"
 	"google.golang.org/protobuf/encoding/protojson"
-	"go.opentelemetry.io/otel/trace"
 )
 
 // A ServerOption sets options such as credentials,
@@ -62,6 +73,8 @@ type httpRoute struct {
 
 type serverOptions struct {
 	tracing                       bool
+	traceExporter                 octrace.Exporter
+	traceSampler                  octrace.Sampler
 	gateway                       bool
 	debugStandardLibraryEndpoints bool
 	logging                       *logging
@@ -74,10 +87,23 @@ type serverOptions struct {
 	registerGateway               registerGatewayFunc
 	grpcListener                  net.Listener
 	unaryServerInterceptors       []grpc.UnaryServerInterceptor
+	streamServerInterceptors      []grpc.StreamServerInterceptor
 	errorHandler                  ErrorHandler
 	panicHandler                  PanicHandler
+	panicReportTimeout            time.Duration
+	panicStatusCode               codes.Code
 	monitorOperationer            MonitorOperationer
 	gatewayCorsOptions            cors.Options
+	certReloader                  *certReloader
+	tlsConfig                     *tls.Config
+	memoryShedder                 *memoryShedder
+	memoryStatsInterval           time.Duration
+	shutdownTimeout               time.Duration
+	healthChecker                 HealthChecker
+	healthCheckInterval           time.Duration
+	reflection                    bool
+	tapHandlers                   []tap.ServerInHandle
+	err                           error
 }
 
 // WithAddress configures the Server to listen to the given address
@@ -140,12 +166,69 @@ func WithGRPCListener(lis net.Listener) ServerOption {
 }
 
 // WithTracing enables tracing for both servers.
+// Unless configured with WithTraceExporter, the traces of the grpc
+// server are exported to Stackdriver, see WithStackdriverTracing.
+//
+// Deprecated: the tracing is based on opencensus, which is no longer
+// maintained. Use WithOTelTracing instead.
 func WithTracing() ServerOption {
 	return newFuncServerOption(func(o *serverOptions) {
 		o.tracing = true
 	})
 }
 
+// WithTraceExporter enables tracing for both servers, the traces of the
+// grpc server being exported with exporter and sampled with sampler.
+// A nil sampler samples every trace.
+//
+// Deprecated: the tracing is based on opencensus, which is no longer
+// maintained. Use WithOTelTracing instead.
+func WithTraceExporter(exporter octrace.Exporter, sampler octrace.Sampler) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.tracing = true
+		o.traceExporter = exporter
+		o.traceSampler = sampler
+	})
+}
+
+// WithStackdriverTracing enables tracing for both servers, the traces of
+// the grpc server being exported to Stackdriver, in the project set by
+// the GOOGLE_CLOUD_PROJECT environment variable.
+//
+// Deprecated: the tracing is based on opencensus, which is no longer
+// maintained. It's kept for the consumers exporting to Stackdriver,
+// new consumers should use WithOTelTracing.
+func WithStackdriverTracing() ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.tracing = true
+		o.traceExporter = nil
+		o.traceSampler = nil
+	})
+}
+
+// WithOTelTracing adds the interceptors tracing the unary and the stream
+// calls of the GRPC server with the OpenTelemetry tracer provider tp, the
+// span contexts of the callers being extracted from the metadata by mp.
+func WithOTelTracing(tp trace.TracerProvider, mp propagation.TextMapPropagator) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		handlerOptions := []otelgrpc.Option{
+			otelgrpc.WithTracerProvider(tp),
+			otelgrpc.WithPropagators(mp),
+		}
+
+		o.unaryServerInterceptors = append(
+			o.unaryServerInterceptors,
+			//nolint:staticcheck // the interceptors are kept by otelgrpc until the stats handler is stable.
+			otelgrpc.UnaryServerInterceptor(handlerOptions...),
+		)
+		o.streamServerInterceptors = append(
+			o.streamServerInterceptors,
+			//nolint:staticcheck // the interceptors are kept by otelgrpc until the stats handler is stable.
+			otelgrpc.StreamServerInterceptor(handlerOptions...),
+		)
+	})
+}
+
 // WithNoGateway disables the gateway server.
 // ! Prefer to use this only in testing.
 func WithNoGateway() ServerOption {
@@ -257,6 +340,23 @@ func WithPanicHandler(panicHandler PanicHandler) ServerOption {
 	})
 }
 
+// WithPanicReportTimeout bounds the PanicHandler.ReportPanic calls.
+// Defaults to 1s.
+func WithPanicReportTimeout(d time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.panicReportTimeout = d
+	})
+}
+
+// WithPanicStatusCode sets the code of the status returned for the
+// panics recovered by the PanicHandler, e.g. codes.Unavailable.
+// Defaults to codes.Internal.
+func WithPanicStatusCode(code codes.Code) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.panicStatusCode = code
+	})
+}
+
 // WithErrorHandler adds an interceptor to the GRPC server
 // that intercepts and handles the error returned by the handler.
 func WithErrorHandler(errorHandler ErrorHandler) ServerOption {
@@ -292,6 +392,30 @@ func WithUnaryServerInterceptor(
 	})
 }
 
+// WithStreamServerInterceptor adds a stream interceptor to the GRPC server.
+func WithStreamServerInterceptor(
+	streamInterceptor grpc.StreamServerInterceptor,
+) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.streamServerInterceptors = append(
+			o.streamServerInterceptors,
+			streamInterceptor,
+		)
+	})
+}
+
+// WithPrometheusInterceptor adds the unary and the stream interceptors
+// exporting the prometheus metrics of the GRPC server.
+// See metrics.NewPrometheusInterceptor for the exported metrics.
+func WithPrometheusInterceptor(opts ...metrics.MetricsOption) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		unaryInterceptor, streamInterceptor := metrics.NewPrometheusInterceptor(opts...)
+
+		o.unaryServerInterceptors = append(o.unaryServerInterceptors, unaryInterceptor)
+		o.streamServerInterceptors = append(o.streamServerInterceptors, streamInterceptor)
+	})
+}
+
 // WithDebugStandardLibraryEndpoints registers the debug routes from
 // the standard library to the gateway.
 func WithDebugStandardLibraryEndpoints() ServerOption {
@@ -332,6 +456,218 @@ func WithOTEL(handlerOptions ...otelgrpc.Option) ServerOption {
 	})
 }
 
+// WithTapHandler configures a handler that is called for every new
+// stream before the RPC is dispatched, allowing requests to be rejected
+// at the transport layer.
+//
+// The option can be given several times: the handlers are called in
+// order, until one of them rejects the stream. It must not be combined
+// with a grpc.InTapHandle given through WithGRPCServerOptions.
+func WithTapHandler(handler tap.ServerInHandle) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.tapHandlers = append(o.tapHandlers, handler)
+	})
+}
+
+// chainTapHandlers returns a tap handler calling the handlers in order,
+// each with the context returned by the previous one.
+func chainTapHandlers(handlers []tap.ServerInHandle) tap.ServerInHandle {
+	return func(ctx context.Context, info *tap.Info) (context.Context, error) {
+		for _, handler := range handlers {
+			var err error
+
+			if ctx, err = handler(ctx, info); err != nil {
+				return nil, err
+			}
+		}
+
+		return ctx, nil
+	}
+}
+
+// WithMaxConcurrentStreams limits the number of concurrent streams,
+// including unary RPCs, each client connection can open.
+//
+// The limit applies per connection: a client opening several connections
+// gets n streams on each of them. To bound the load of the whole server
+// use an interceptor that counts the RPCs across all connections.
+func WithMaxConcurrentStreams(n uint32) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.grpcServerOptions = append(
+			o.grpcServerOptions,
+			grpc.MaxConcurrentStreams(n),
+		)
+	})
+}
+
+// WithMaxRecvMsgSize sets the maximum size, in bytes, of the messages
+// the server can receive. Defaults to 4MB.
+func WithMaxRecvMsgSize(n int) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.grpcServerOptions = append(
+			o.grpcServerOptions,
+			grpc.MaxRecvMsgSize(n),
+		)
+	})
+}
+
+// WithMaxSendMsgSize sets the maximum size, in bytes, of the messages
+// the server can send. Defaults to math.MaxInt32.
+func WithMaxSendMsgSize(n int) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.grpcServerOptions = append(
+			o.grpcServerOptions,
+			grpc.MaxSendMsgSize(n),
+		)
+	})
+}
+
+// WithKeepalive configures the keepalive parameters of the server
+// connections and the policy enforced on the keepalive pings of the
+// clients, e.g. to close the idle connections before a load balancer
+// silently drops them.
+func WithKeepalive(params keepalive.ServerParameters, policy keepalive.EnforcementPolicy) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.grpcServerOptions = append(
+			o.grpcServerOptions,
+			grpc.KeepaliveParams(params),
+			grpc.KeepaliveEnforcementPolicy(policy),
+		)
+	})
+}
+
+// WithDefaultCompression compresses all the responses with the named
+// compressor, unless the client does not support it.
+//
+// The compressor must be registered beforehand, e.g. by importing
+// google.golang.org/grpc/encoding/gzip, otherwise NewServer fails.
+func WithDefaultCompression(name string) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if encoding.GetCompressor(name) == nil {
+			o.err = errors.Join(o.err, fmt.Errorf("%w: %s", ErrCompressorNotRegistered, name))
+
+			return
+		}
+
+		o.unaryServerInterceptors = append(
+			o.unaryServerInterceptors,
+			func(
+				ctx context.Context,
+				req any,
+				_ *grpc.UnaryServerInfo,
+				handler grpc.UnaryHandler,
+			) (any, error) {
+				// fails when the client does not accept the compressor,
+				// in which case the response is sent uncompressed.
+				_ = grpc.SetSendCompressor(ctx, name)
+
+				return handler(ctx, req)
+			},
+		)
+	})
+}
+
+// WithTLSConfig serves the grpc server over TLS using cfg, which must
+// hold a certificate, either in Certificates or through GetCertificate.
+//
+// For mutual TLS, set cfg.ClientAuth to tls.RequireAndVerifyClientCert
+// and cfg.ClientCAs to the pool of the accepted client certificate
+// authorities.
+//
+// Like WithDynamicTLS, it requires the gateway dial options
+// to be overridden, or the gateway disabled.
+func WithTLSConfig(cfg *tls.Config) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		if cfg == nil || (len(cfg.Certificates) == 0 && cfg.GetCertificate == nil) {
+			o.err = errors.Join(o.err, ErrTLSCertificateRequired)
+
+			return
+		}
+
+		o.tlsConfig = cfg
+	})
+}
+
+// WithDynamicTLS serves the grpc server over TLS using the key pair
+// from certFile and keyFile, reloaded from disk every reloadInterval so
+// the certificate can be rotated without a restart.
+//
+// NewServer fails if the key pair can't be loaded initially. Later
+// reload failures are logged and the previous certificate is kept.
+//
+// The gateway dials the grpc server with insecure credentials, so the
+// gateway dial options must be overridden, or the gateway disabled.
+func WithDynamicTLS(certFile, keyFile string, reloadInterval time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		reloader, err := newCertReloader(certFile, keyFile, reloadInterval)
+		if err != nil {
+			o.err = errors.Join(o.err, fmt.Errorf("dynamic tls: %w", err))
+
+			return
+		}
+
+		o.certReloader = reloader
+	})
+}
+
+// WithMemoryShedding rejects new requests with a ResourceExhausted
+// status once the heap in use exceeds highWatermark bytes, until it
+// drops below lowWatermark bytes.
+//
+// The memory stats are read at most once per interval,
+// see WithMemoryStatsInterval.
+func WithMemoryShedding(highWatermark, lowWatermark uint64) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.memoryShedder = newMemoryShedder(highWatermark, lowWatermark)
+	})
+}
+
+// WithMemoryStatsInterval sets the minimum interval between two reads
+// of the memory stats used by WithMemoryShedding. Defaults to 100ms.
+func WithMemoryStatsInterval(d time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.memoryStatsInterval = d
+	})
+}
+
+// WithShutdownTimeout bounds the graceful stop of the grpc server on
+// Close. Once the timeout elapses, the remaining connections are closed
+// and Close returns ErrShutdownTimeout.
+//
+// By default, Close waits for all the pending requests to finish.
+func WithShutdownTimeout(d time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.shutdownTimeout = d
+	})
+}
+
+// WithHealthCheck registers the grpc health service, answering with the
+// statuses reported by checker for the server, as the empty service,
+// and for each of its services. The statuses are checked every 10s
+// unless configured otherwise with WithHealthCheckInterval.
+func WithHealthCheck(checker HealthChecker) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.healthChecker = checker
+	})
+}
+
+// WithHealthCheckInterval sets the interval between two health checks,
+// see WithHealthCheck.
+func WithHealthCheckInterval(d time.Duration) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.healthCheckInterval = d
+	})
+}
+
+// WithReflection registers, if enabled, the grpc reflection service,
+// exposing the schema of the services to any caller. It is disabled
+// by default and should be enabled only in development environments.
+func WithReflection(enabled bool) ServerOption {
+	return newFuncServerOption(func(o *serverOptions) {
+		o.reflection = enabled
+	})
+}
+
 func defaultServerOptions() serverOptions {
 	return serverOptions{
 		tracing:                       false,
@@ -0,0 +1,308 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// ErrServerNotInPool is returned when promoting a server
+// that was not spun by the pool.
+var ErrServerNotInPool = errors.New("go-commons.grpc: server not in pool")
+
+// RegisterServerFunc defines how we can register
+// a grpc service to a grpc server spun by a ServerPool.
+type RegisterServerFunc func(server *grpc.Server)
+
+// A PoolOption configures a ServerPool.
+type PoolOption interface {
+	apply(*poolOptions)
+}
+
+type funcPoolOption struct {
+	f func(*poolOptions)
+}
+
+func (fpo *funcPoolOption) apply(po *poolOptions) {
+	fpo.f(po)
+}
+
+func newFuncPoolOption(f func(*poolOptions)) *funcPoolOption {
+	return &funcPoolOption{
+		f: f,
+	}
+}
+
+type poolOptions struct {
+	address       string
+	listener      net.Listener
+	serverOptions []ServerOption
+}
+
+// WithPoolAddress configures the address the pool accepts
+// the client connections on.
+func WithPoolAddress(a string) PoolOption {
+	return newFuncPoolOption(func(o *poolOptions) {
+		o.address = a
+	})
+}
+
+// WithPoolListener configures the pool to accept the client
+// connections on the given listener instead of configuring an address.
+func WithPoolListener(lis net.Listener) PoolOption {
+	return newFuncPoolOption(func(o *poolOptions) {
+		o.listener = lis
+	})
+}
+
+// WithPoolServerOptions configures the options applied to
+// every server spun by the pool.
+//
+// The address, listener, gateway and register options are
+// always overwritten by the pool.
+func WithPoolServerOptions(opts ...ServerOption) PoolOption {
+	return newFuncPoolOption(func(o *poolOptions) {
+		o.serverOptions = append(o.serverOptions, opts...)
+	})
+}
+
+// ServerPool accepts client connections on a single address and
+// forwards them to the active server of the pool.
+//
+// New servers are spun on the next available local port and can be promoted
+// to active without restarting the process, which allows hot-swapping
+// server implementations with zero downtime.
+type ServerPool struct {
+	opts poolOptions
+
+	active atomic.Pointer[Server]
+
+	mu       sync.Mutex
+	servers  map[*Server]struct{}
+	listener net.Listener
+	closed   bool
+}
+
+// NewServerPool creates a ServerPool without any server.
+//
+// The pool has not started to accept connections yet.
+func NewServerPool(opts ...PoolOption) *ServerPool {
+	o := poolOptions{
+		address: "0.0.0.0:7350",
+	}
+
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+
+	return &ServerPool{
+		opts:    o,
+		servers: make(map[*Server]struct{}),
+	}
+}
+
+// Spin creates a new grpc server listening on the next available local port
+// and starts it. The server does not receive pool connections
+// until it is promoted.
+func (p *ServerPool) Spin(registerFunc RegisterServerFunc) (*Server, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrServerClosed
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+
+	opts := append(
+		append([]ServerOption(nil), p.opts.serverOptions...),
+		WithGRPCListener(lis),
+		WithNoGateway(),
+		WithRegisterServerFunc(registerServerFunc(registerFunc)),
+	)
+
+	s, err := NewServer(opts...)
+	if err != nil {
+		_ = lis.Close()
+
+		return nil, fmt.Errorf("new server: %w", err)
+	}
+
+	p.servers[s] = struct{}{}
+
+	go func() {
+		if err := s.ListenAndServe(); err != nil {
+			s.logDebug("pool server stopped: " + err.Error())
+		}
+	}()
+
+	return s, nil
+}
+
+// Promote atomically makes s the active server, so that the new connections
+// are forwarded to it, and gracefully stops the previously active server.
+//
+// Promote returns once the previous server is drained.
+func (p *ServerPool) Promote(s *Server) error {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return ErrServerClosed
+	}
+
+	if _, ok := p.servers[s]; !ok {
+		p.mu.Unlock()
+
+		return ErrServerNotInPool
+	}
+
+	previous := p.active.Swap(s)
+	if previous == s {
+		p.mu.Unlock()
+
+		return nil
+	}
+
+	if previous != nil {
+		delete(p.servers, previous)
+	}
+
+	p.mu.Unlock()
+
+	if previous == nil {
+		return nil
+	}
+
+	if err := previous.Close(); err != nil {
+		return fmt.Errorf("close previous server: %w", err)
+	}
+
+	return nil
+}
+
+// Active returns the currently active server,
+// or nil if no server was promoted yet.
+func (p *ServerPool) Active() *Server {
+	return p.active.Load()
+}
+
+// ListenAndServe accepts client connections and forwards them
+// to the active server until the pool is closed.
+func (p *ServerPool) ListenAndServe() error {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return ErrServerClosed
+	}
+
+	lis := p.opts.listener
+
+	if lis == nil {
+		var err error
+
+		lis, err = net.Listen("tcp", p.opts.address)
+		if err != nil {
+			p.mu.Unlock()
+
+			return fmt.Errorf("listen: %w", err)
+		}
+	}
+
+	p.listener = lis
+
+	p.mu.Unlock()
+
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			p.mu.Lock()
+			closed := p.closed
+			p.mu.Unlock()
+
+			if closed {
+				return nil
+			}
+
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		go p.forward(conn)
+	}
+}
+
+func (p *ServerPool) forward(conn net.Conn) {
+	defer conn.Close()
+
+	active := p.active.Load()
+	if active == nil {
+		return
+	}
+
+	backend, err := net.Dial("tcp", active.grpcServer.addr())
+	if err != nil {
+		active.logDebug("dial pool server: " + err.Error())
+
+		return
+	}
+
+	defer backend.Close()
+
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(backend, conn)
+		done <- struct{}{}
+	}()
+
+	go func() {
+		_, _ = io.Copy(conn, backend)
+		done <- struct{}{}
+	}()
+
+	// once one side is done, closing both connections unblocks the other copy.
+	<-done
+}
+
+// Close stops accepting client connections and closes all the servers
+// of the pool.
+// Safe to use concurrently and can be called multiple times.
+func (p *ServerPool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil
+	}
+
+	p.closed = true
+
+	var errs error
+
+	if p.listener != nil {
+		if err := p.listener.Close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("close listener: %w", err))
+		}
+	}
+
+	for s := range p.servers {
+		if err := s.Close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("close server: %w", err))
+		}
+	}
+
+	p.servers = nil
+
+	return errs
+}
@@ -0,0 +1,83 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/matryer/is"
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerPool(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	pool := commonsgrpc.NewServerPool(commonsgrpc.WithPoolListener(lis))
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- pool.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(pool.Close())
+		i.NoErr(<-errCh)
+	})
+
+	spin := func(result string) *commonsgrpc.Server {
+		s, err := pool.Spin(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &fixedGreeterService{result: result})
+		})
+		i.NoErr(err)
+
+		return s
+	}
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	greet := func() string {
+		resp, err := greetClient.Greet(
+			context.Background(),
+			&greetpb.GreetRequest{Greeting: &greetpb.Greeting{}},
+			grpc.WaitForReady(true),
+		)
+		i.NoErr(err)
+
+		return resp.GetResult()
+	}
+
+	blue := spin("blue")
+	i.NoErr(pool.Promote(blue))
+	i.Equal(blue, pool.Active())
+	i.Equal("blue", greet())
+
+	green := spin("green")
+	i.NoErr(pool.Promote(green))
+	i.Equal(green, pool.Active())
+	i.Equal("green", greet())
+}
+
+type fixedGreeterService struct {
+	greetpb.UnimplementedGreetServiceServer
+	result string
+}
+
+func (s *fixedGreeterService) Greet(
+	context.Context,
+	*greetpb.GreetRequest,
+) (*greetpb.GreetResponse, error) {
+	return &greetpb.GreetResponse{Result: s.result}, nil
+}
@@ -9,12 +9,26 @@ import (
 	"github.com/oklog/run"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 // ErrServerClosed indicates that the operation is now illegal because of
 // the server has been closed.
 var ErrServerClosed = errors.New("go-commons.grpc: server closed")
 
+// ErrTLSCertificateRequired is returned when a TLS config
+// without a certificate is configured.
+var ErrTLSCertificateRequired = errors.New("go-commons.grpc: tls certificate required")
+
+// ErrCompressorNotRegistered is returned when a server option names
+// a compressor that is not registered.
+var ErrCompressorNotRegistered = errors.New("go-commons.grpc: compressor not registered")
+
+// ErrShutdownTimeout is returned by Close when the grpc server
+// didn't stop gracefully within the shutdown timeout and was forced to.
+var ErrShutdownTimeout = errors.New("go-commons.grpc: shutdown timeout exceeded")
+
 type (
 	// registerServerFunc defines how we can register
 	// a grpc service to a grpc server.
@@ -52,28 +66,92 @@ func NewServer(opt ...ServerOption) (*Server, error) {
 		o.apply(&opts)
 	}
 
+	if opts.err != nil {
+		return nil, fmt.Errorf("apply options: %w", opts.err)
+	}
+
 	aggregatorServer := new(Server)
 
 	if opts.logging != nil {
 		aggregatorServer.logging = opts.logging
 	}
 
+	if opts.memoryShedder != nil {
+		if opts.memoryStatsInterval > 0 {
+			opts.memoryShedder.interval = opts.memoryStatsInterval
+		}
+
+		if opts.logging != nil {
+			opts.memoryShedder.logger = opts.logging.logger
+		}
+
+		// shed the load before running any other interceptor: the
+		// chain of this option runs before the one of the builder,
+		// including the debug, panic and error handling interceptors.
+		opts.grpcServerOptions = append(
+			opts.grpcServerOptions,
+			grpc.ChainUnaryInterceptor(opts.memoryShedder.unaryServerInterceptor()),
+		)
+	}
+
+	// grpc accepts a single tap handler.
+	if len(opts.tapHandlers) > 0 {
+		opts.grpcServerOptions = append(
+			opts.grpcServerOptions,
+			grpc.InTapHandle(chainTapHandlers(opts.tapHandlers)),
+		)
+	}
+
+	if opts.certReloader != nil {
+		if opts.logging != nil {
+			opts.certReloader.logger = opts.logging.logger
+		}
+
+		opts.tlsConfig = opts.certReloader.tlsConfig()
+	}
+
+	if opts.tlsConfig != nil {
+		opts.grpcServerOptions = append(
+			opts.grpcServerOptions,
+			grpc.Creds(credentials.NewTLS(opts.tlsConfig)),
+		)
+	}
+
 	grpcServerWithListener, err := newGRPCServer(
 		opts.grpcListener,
 		opts.address,
 		opts.tracing,
+		opts.traceExporter,
+		opts.traceSampler,
 		opts.grpcServerOptions,
 		opts.unaryServerInterceptors,
+		opts.streamServerInterceptors,
 		opts.registerServer,
 		aggregatorServer.logging,
 		opts.errorHandler,
 		opts.panicHandler,
+		opts.panicReportTimeout,
+		opts.panicStatusCode,
 		opts.monitorOperationer,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new gRPC server: %w", err)
 	}
 
+	grpcServerWithListener.shutdownTimeout = opts.shutdownTimeout
+
+	if opts.reflection {
+		reflection.Register(grpcServerWithListener.grpcServer)
+	}
+
+	if opts.healthChecker != nil {
+		grpcServerWithListener.setHealthCheck(
+			opts.healthChecker,
+			opts.healthCheckInterval,
+			aggregatorServer.logging,
+		)
+	}
+
 	aggregatorServer.grpcServer = grpcServerWithListener
 
 	// return here if a gateway server is not wanted.
@@ -0,0 +1,307 @@
+package grpc
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+// ServerBuilder builds a Server running only the grpc server,
+// without the gateway server.
+type ServerBuilder struct {
+	address                  string
+	listener                 net.Listener
+	tracing                  bool
+	traceExporter            trace.Exporter
+	traceSampler             trace.Sampler
+	grpcServerOptions        []grpc.ServerOption
+	unaryServerInterceptors  []grpc.UnaryServerInterceptor
+	streamServerInterceptors []grpc.StreamServerInterceptor
+	registerServer           registerServerFunc
+	logging                  *logging
+	errorHandler             ErrorHandler
+	panicHandler             PanicHandler
+	panicReportTimeout       time.Duration
+	panicStatusCode          codes.Code
+	monitorOperationer       MonitorOperationer
+	healthChecker            HealthChecker
+	healthCheckInterval      time.Duration
+	reflection               bool
+}
+
+// NewServerBuilder creates a ServerBuilder listening,
+// by default, to 0.0.0.0:7349.
+func NewServerBuilder() *ServerBuilder {
+	return &ServerBuilder{
+		address: defaultServerOptions().address,
+	}
+}
+
+// WithAddress sets the address of the grpc server, as in the WithAddress
+// server option the grpc server listens to the same address but port-1.
+func (b *ServerBuilder) WithAddress(addr string) *ServerBuilder {
+	b.address = addr
+
+	return b
+}
+
+// WithListener sets the listener of the grpc server,
+// the address is ignored.
+func (b *ServerBuilder) WithListener(l net.Listener) *ServerBuilder {
+	b.listener = l
+
+	return b
+}
+
+// WithTracing enables the opencensus tracing of the grpc server,
+// exporting the traces with exporter, defaulting to Stackdriver if nil.
+func (b *ServerBuilder) WithTracing(exporter trace.Exporter) *ServerBuilder {
+	b.tracing = true
+	b.traceExporter = exporter
+
+	return b
+}
+
+// WithUnaryInterceptors appends the interceptors to the unary
+// interceptors chain of the grpc server.
+func (b *ServerBuilder) WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) *ServerBuilder {
+	b.unaryServerInterceptors = append(b.unaryServerInterceptors, interceptors...)
+
+	return b
+}
+
+// WithStreamInterceptors appends the interceptors to the stream
+// interceptors chain of the grpc server.
+func (b *ServerBuilder) WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) *ServerBuilder {
+	b.streamServerInterceptors = append(b.streamServerInterceptors, interceptors...)
+
+	return b
+}
+
+// WithErrorHandler sets the handler of the errors returned by the services.
+func (b *ServerBuilder) WithErrorHandler(errorHandler ErrorHandler) *ServerBuilder {
+	b.errorHandler = errorHandler
+
+	return b
+}
+
+// WithPanicHandler sets the handler of the panics raised by the services.
+func (b *ServerBuilder) WithPanicHandler(panicHandler PanicHandler) *ServerBuilder {
+	b.panicHandler = panicHandler
+
+	return b
+}
+
+// WithPanicReportTimeout bounds the PanicHandler.ReportPanic calls.
+// Defaults to 1s.
+func (b *ServerBuilder) WithPanicReportTimeout(d time.Duration) *ServerBuilder {
+	b.panicReportTimeout = d
+
+	return b
+}
+
+// WithPanicStatusCode sets the code of the status returned for the
+// recovered panics, e.g. codes.Unavailable. Defaults to codes.Internal.
+func (b *ServerBuilder) WithPanicStatusCode(code codes.Code) *ServerBuilder {
+	b.panicStatusCode = code
+
+	return b
+}
+
+// WithDebugLogger enables the logging of the requests,
+// see the WithDebug server option.
+func (b *ServerBuilder) WithDebugLogger(
+	logger *zap.Logger,
+	logRequest bool,
+	ignoredMethods ...string,
+) *ServerBuilder {
+	b.logging = &logging{
+		logger:         logger,
+		ignoredMethods: ignoredMethods,
+		logRequest:     logRequest,
+	}
+
+	return b
+}
+
+// WithServerOptions appends the options to the options of the grpc server.
+func (b *ServerBuilder) WithServerOptions(opts ...grpc.ServerOption) *ServerBuilder {
+	b.grpcServerOptions = append(b.grpcServerOptions, opts...)
+
+	return b
+}
+
+// WithMaxRecvMsgSize sets the maximum size, in bytes, of the messages
+// the server can receive. Defaults to 4MB.
+func (b *ServerBuilder) WithMaxRecvMsgSize(n int) *ServerBuilder {
+	return b.WithServerOptions(grpc.MaxRecvMsgSize(n))
+}
+
+// WithMaxSendMsgSize sets the maximum size, in bytes, of the messages
+// the server can send. Defaults to math.MaxInt32.
+func (b *ServerBuilder) WithMaxSendMsgSize(n int) *ServerBuilder {
+	return b.WithServerOptions(grpc.MaxSendMsgSize(n))
+}
+
+// WithKeepalive configures the keepalive parameters of the server
+// connections and the policy enforced on the client pings.
+func (b *ServerBuilder) WithKeepalive(
+	params keepalive.ServerParameters,
+	policy keepalive.EnforcementPolicy,
+) *ServerBuilder {
+	return b.WithServerOptions(
+		grpc.KeepaliveParams(params),
+		grpc.KeepaliveEnforcementPolicy(policy),
+	)
+}
+
+// WithRegisterServer registers the grpc services to the grpc server.
+func (b *ServerBuilder) WithRegisterServer(f func(server *grpc.Server)) *ServerBuilder {
+	b.registerServer = f
+
+	return b
+}
+
+// WithHealthCheck registers the grpc health service,
+// see the WithHealthCheck server option.
+func (b *ServerBuilder) WithHealthCheck(checker HealthChecker) *ServerBuilder {
+	b.healthChecker = checker
+
+	return b
+}
+
+// WithHealthCheckInterval sets the interval between two health checks.
+// Defaults to 10s.
+func (b *ServerBuilder) WithHealthCheckInterval(d time.Duration) *ServerBuilder {
+	b.healthCheckInterval = d
+
+	return b
+}
+
+// WithReflection registers, if enabled, the grpc reflection service,
+// see the WithReflection server option. Disabled by default.
+func (b *ServerBuilder) WithReflection(enabled bool) *ServerBuilder {
+	b.reflection = enabled
+
+	return b
+}
+
+// Build creates the Server. The server has not started
+// to accept requests yet.
+func (b *ServerBuilder) Build() (*Server, error) {
+	grpcServer, err := b.build()
+	if err != nil {
+		return nil, fmt.Errorf("new gRPC server: %w", err)
+	}
+
+	if b.reflection {
+		reflection.Register(grpcServer.grpcServer)
+	}
+
+	if b.healthChecker != nil {
+		grpcServer.setHealthCheck(b.healthChecker, b.healthCheckInterval, b.logging)
+	}
+
+	return &Server{
+		grpcServer: grpcServer,
+		logging:    b.logging,
+	}, nil
+}
+
+// nolint: gocyclo // cyclomatic complexity is 9. FIXME
+func (b *ServerBuilder) build() (*grpcServer, error) {
+	grpcListener, err := newGRPCListener(b.listener, b.address)
+	if err != nil {
+		return nil, fmt.Errorf("new grpc listener: %w", err)
+	}
+
+	// copied, so that building doesn't modify the builder.
+	grpcServerOptions := append([]grpc.ServerOption(nil), b.grpcServerOptions...)
+
+	if b.tracing {
+		grpcServerOptions, err = setGRPCTracing(grpcServerOptions, b.traceExporter, b.traceSampler)
+		if err != nil {
+			return nil, fmt.Errorf("set grpc tracing tracing: %w", err)
+		}
+	}
+
+	unaryServerInterceptors := append([]grpc.UnaryServerInterceptor(nil), b.unaryServerInterceptors...)
+	streamServerInterceptors := append([]grpc.StreamServerInterceptor(nil), b.streamServerInterceptors...)
+
+	if !isErrorHandlerNil(b.errorHandler) {
+		unaryServerInterceptors = prependErrorHandler(
+			unaryServerInterceptors,
+			b.errorHandler,
+		)
+
+		streamServerInterceptors = prependStreamErrorHandler(
+			streamServerInterceptors,
+			b.errorHandler,
+		)
+	}
+
+	if !isPanicHandlerNil(b.panicHandler) {
+		recoveryFunc := newRecoveryFunc(b.panicHandler, b.panicReportTimeout, b.panicStatusCode)
+
+		unaryServerInterceptors = prependPanicHandler(
+			unaryServerInterceptors,
+			recoveryFunc,
+		)
+
+		streamServerInterceptors = prependStreamPanicHandler(
+			streamServerInterceptors,
+			recoveryFunc,
+		)
+	}
+
+	if b.logging != nil {
+		unaryServerInterceptors = prependDebugInterceptor(
+			unaryServerInterceptors,
+			b.logging,
+		)
+
+		streamServerInterceptors = prependStreamDebugInterceptor(
+			streamServerInterceptors,
+			b.logging,
+		)
+	}
+
+	if !isMonitorOperationerNil(b.monitorOperationer) {
+		unaryServerInterceptors = append(
+			unaryServerInterceptors,
+			newMonitorOperationUnaryInterceptor(b.monitorOperationer),
+		)
+	}
+
+	if len(unaryServerInterceptors) > 0 {
+		grpcServerOptions = append(grpcServerOptions,
+			grpc.ChainUnaryInterceptor(
+				unaryServerInterceptors...,
+			))
+	}
+
+	if len(streamServerInterceptors) > 0 {
+		grpcServerOptions = append(grpcServerOptions,
+			grpc.ChainStreamInterceptor(
+				streamServerInterceptors...,
+			))
+	}
+
+	internalGRPCServer := grpc.NewServer(grpcServerOptions...)
+
+	if b.registerServer != nil {
+		b.registerServer(internalGRPCServer)
+	}
+
+	return &grpcServer{
+		grpcServer: internalGRPCServer,
+		listener:   grpcListener,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
+	"github.com/purposeinplay/go-commons/grpc/test_data/mock"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerBuilder(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	errorHandler := &mock.ErrorHandlerMock{
+		ErrorToGRPCStatusFunc: func(err error) (*status.Status, error) {
+			return status.New(codes.InvalidArgument, err.Error()), nil
+		},
+		IsApplicationErrorFunc: func(error) bool { return true },
+		LogErrorFunc:           func(error) {},
+		ReportErrorFunc:        func(context.Context, error) error { return nil },
+	}
+
+	var intercepted atomic.Int32
+
+	grpcServer, err := commonsgrpc.NewServerBuilder().
+		WithListener(lis).
+		WithDebugLogger(zap.NewExample(), true).
+		WithErrorHandler(errorHandler).
+		WithUnaryInterceptors(func(
+			ctx context.Context,
+			req any,
+			_ *grpc.UnaryServerInfo,
+			handler grpc.UnaryHandler,
+		) (any, error) {
+			intercepted.Add(1)
+
+			return handler(ctx, req)
+		}).
+		WithRegisterServer(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{
+				greetFunc: func() error {
+					return errors.New("invalid greeting")
+				},
+			})
+		}).
+		Build()
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	_, err = greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+		Greeting: &greetpb.Greeting{FirstName: "a", LastName: "b"},
+	})
+	i.Equal(codes.InvalidArgument, status.Code(err))
+	i.Equal(int32(1), intercepted.Load())
+}
+
+func TestServerBuilderPanic(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	reportTimeout := make(chan time.Duration, 1)
+
+	panicHandler := &mock.PanicHandlerMock{
+		LogErrorFunc: func(error) {},
+		LogPanicFunc: func(any) {},
+		ReportPanicFunc: func(ctx context.Context, _ any) error {
+			deadline, _ := ctx.Deadline()
+			reportTimeout <- time.Until(deadline)
+
+			return nil
+		},
+	}
+
+	grpcServer, err := commonsgrpc.NewServerBuilder().
+		WithListener(lis).
+		WithPanicHandler(panicHandler).
+		WithPanicReportTimeout(time.Minute).
+		WithPanicStatusCode(codes.Unavailable).
+		WithRegisterServer(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{
+				greetFunc: func() error {
+					panic("panic")
+				},
+			})
+		}).
+		Build()
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	_, err = greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+		Greeting: &greetpb.Greeting{FirstName: "a", LastName: "b"},
+	})
+	i.Equal(codes.Unavailable, status.Code(err))
+
+	timeout := <-reportTimeout
+	i.True(timeout > time.Second && timeout <= time.Minute)
+}
+
+func TestNewRecoveryFuncWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	var timeout time.Duration
+
+	recoveryFunc := commonsgrpc.NewRecoveryFuncWithTimeout(&mock.PanicHandlerMock{
+		LogErrorFunc: func(error) {},
+		LogPanicFunc: func(any) {},
+		ReportPanicFunc: func(ctx context.Context, _ any) error {
+			deadline, _ := ctx.Deadline()
+			timeout = time.Until(deadline)
+
+			return nil
+		},
+	}, 0)
+
+	err := recoveryFunc("panic")
+	i.Equal(codes.Internal, status.Code(err))
+
+	// the zero timeout defaults to 1s.
+	i.True(timeout > 0 && timeout <= time.Second)
+}
+
+func TestServerBuilderDebugLogging(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	grpcServer, err := commonsgrpc.NewServerBuilder().
+		WithListener(lis).
+		WithDebugLogger(zap.New(core), false).
+		WithRegisterServer(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{
+				greetFunc: func() error { return nil },
+			})
+		}).
+		Build()
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	_, err = greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+		Greeting: &greetpb.Greeting{FirstName: "a", LastName: "b"},
+	})
+	i.NoErr(err)
+
+	entries := logs.FilterMessage("request started").All()
+	i.Equal(1, len(entries))
+
+	// the bufconn addresses have no port.
+	i.Equal("bufconn", entries[0].ContextMap()["remote_addr"])
+
+	// every field is logged once.
+	keys := make(map[string]struct{}, len(entries[0].Context))
+
+	for _, f := range entries[0].Context {
+		_, ok := keys[f.Key]
+		i.True(!ok)
+
+		keys[f.Key] = struct{}{}
+	}
+}
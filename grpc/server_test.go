@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -21,12 +23,21 @@ import (
 	"github.com/purposeinplay/go-commons/grpc/grpcclient"
 	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
 	"github.com/purposeinplay/go-commons/grpc/test_data/mock"
+	octrace "go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -550,6 +561,458 @@ func TestMonitorOperation(t *testing.T) {
 	i.Equal(1, len(monitorOperationer.MonitorOperationCalls()))
 }
 
+func TestMaxConcurrentStreams(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+		release     = make(chan struct{})
+	)
+
+	greeter := &greeterService{
+		greetFunc: func() error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			<-release
+
+			return nil
+		},
+	}
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithMaxConcurrentStreams(1),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, greeter)
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	const streams = 3
+
+	var wg sync.WaitGroup
+
+	wg.Add(streams)
+
+	for j := 0; j < streams; j++ {
+		go func() {
+			defer wg.Done()
+
+			_, err := greetClient.Greet(
+				context.Background(),
+				&greetpb.GreetRequest{Greeting: &greetpb.Greeting{}},
+			)
+			i.NoErr(err)
+		}()
+	}
+
+	// give the client time to open the streams.
+	time.Sleep(200 * time.Millisecond)
+
+	i.Equal(int32(1), inFlight.Load())
+
+	close(release)
+
+	wg.Wait()
+
+	i.Equal(int32(1), maxInFlight.Load())
+}
+
+func TestTapHandlers(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	type ctxKey struct{}
+
+	var calls []string
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithTapHandler(func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+			calls = append(calls, "first")
+
+			return context.WithValue(ctx, ctxKey{}, "first"), nil
+		}),
+		commonsgrpc.WithTapHandler(func(ctx context.Context, _ *tap.Info) (context.Context, error) {
+			// the context of the previous handler is passed along.
+			calls = append(calls, fmt.Sprint(ctx.Value(ctxKey{})))
+
+			return nil, status.Error(codes.PermissionDenied, "rejected")
+		}),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{
+				greetFunc: func() error {
+					t.Error("unexpected rpc call")
+
+					return nil
+				},
+			})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	_, err = greetClient.Greet(
+		context.Background(),
+		&greetpb.GreetRequest{Greeting: &greetpb.Greeting{}},
+	)
+	i.Equal(codes.PermissionDenied, status.Code(err))
+	i.Equal([]string{"first", "first"}, calls)
+}
+
+func TestDefaultCompression(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NotRegistered", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(bufconn.Listen(1)),
+			commonsgrpc.WithDefaultCompression("unknown"),
+		)
+		i.True(errors.Is(err, commonsgrpc.ErrCompressorNotRegistered))
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		const bufSize = 1024 * 1024
+
+		lis := bufconn.Listen(bufSize)
+		bufDialer := func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}
+
+		grpcServer, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(lis),
+			commonsgrpc.WithDefaultCompression(gzip.Name),
+			commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+				greetpb.RegisterGreetServiceServer(server, &greeterService{})
+			}),
+		)
+		i.NoErr(err)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- grpcServer.ListenAndServe()
+		}()
+
+		t.Cleanup(func() {
+			i.NoErr(grpcServer.Close())
+			i.NoErr(<-errCh)
+		})
+
+		greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+		resp, err := greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+			Greeting: &greetpb.Greeting{
+				FirstName: "John",
+				LastName:  "Doe",
+			},
+		})
+		i.NoErr(err)
+		i.Equal("JohnDoe", resp.GetResult())
+	})
+}
+
+func TestMemoryShedding(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		highWatermark uint64
+		expectedCode  codes.Code
+	}{
+		"BelowWatermark": {
+			highWatermark: math.MaxUint64,
+			expectedCode:  codes.OK,
+		},
+		"AboveWatermark": {
+			highWatermark: 1,
+			expectedCode:  codes.ResourceExhausted,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i := is.New(t)
+
+			const bufSize = 1024 * 1024
+
+			lis := bufconn.Listen(bufSize)
+			bufDialer := func(context.Context, string) (net.Conn, error) {
+				return lis.Dial()
+			}
+
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			grpcServer, err := commonsgrpc.NewServer(
+				commonsgrpc.WithGRPCListener(lis),
+				commonsgrpc.WithMemoryShedding(test.highWatermark, 0),
+				commonsgrpc.WithMemoryStatsInterval(time.Millisecond),
+				commonsgrpc.WithErrorHandler(commonsgrpc.NewStructuredErrorHandler(
+					zap.New(core),
+					commonsgrpc.ErrorReporterFunc(func(context.Context, error) error {
+						return nil
+					}),
+					func(error) bool { return false },
+					func(error) (*status.Status, error) { return status.New(codes.Internal, "internal"), nil },
+				)),
+				commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+					greetpb.RegisterGreetServiceServer(server, &greeterService{})
+				}),
+			)
+			i.NoErr(err)
+
+			errCh := make(chan error, 1)
+
+			go func() {
+				errCh <- grpcServer.ListenAndServe()
+			}()
+
+			t.Cleanup(func() {
+				i.NoErr(grpcServer.Close())
+				i.NoErr(<-errCh)
+			})
+
+			greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+			_, err = greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+				Greeting: &greetpb.Greeting{
+					FirstName: "John",
+					LastName:  "Doe",
+				},
+			})
+			i.Equal(test.expectedCode, status.Code(err))
+
+			// the shed requests don't reach the error handler.
+			i.Equal(0, logs.FilterMessage("request error").Len())
+		})
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	panicHandler := &mock.PanicHandlerMock{
+		LogErrorFunc:    func(error) {},
+		LogPanicFunc:    func(any) {},
+		ReportPanicFunc: func(context.Context, any) error { return nil },
+	}
+
+	var intercepted atomic.Bool
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithPanicHandler(panicHandler),
+		commonsgrpc.WithStreamServerInterceptor(func(
+			srv any,
+			ss grpc.ServerStream,
+			_ *grpc.StreamServerInfo,
+			handler grpc.StreamHandler,
+		) error {
+			intercepted.Store(true)
+
+			return handler(srv, ss)
+		}),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			server.RegisterService(&grpc.ServiceDesc{
+				ServiceName: "test.Stream",
+				HandlerType: (*any)(nil),
+				Streams: []grpc.StreamDesc{
+					{
+						StreamName:    "Panic",
+						ServerStreams: true,
+						Handler: func(any, grpc.ServerStream) error {
+							panic("panic")
+						},
+					},
+				},
+			}, struct{}{})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	clientConn, err := grpcclient.NewConn(
+		"bufnet",
+		grpcclient.WithContextDialer(bufDialer),
+		grpcclient.WithNoTLS(),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	stream, err := clientConn.NewStream(
+		context.Background(),
+		&grpc.StreamDesc{ServerStreams: true},
+		"/test.Stream/Panic",
+	)
+	i.NoErr(err)
+
+	err = stream.RecvMsg(new(greetpb.GreetResponse))
+	i.Equal(codes.Internal, status.Code(err))
+	i.True(intercepted.Load())
+	i.Equal(1, len(panicHandler.LogPanicCalls()))
+}
+
+func TestTraceExporter(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	exporter := &spanRecorder{}
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithTraceExporter(exporter, octrace.AlwaysSample()),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		octrace.UnregisterExporter(exporter)
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	_, err = greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+		Greeting: &greetpb.Greeting{FirstName: "John"},
+	})
+	i.NoErr(err)
+
+	// the server span may end after the client receives the response.
+	deadline := time.Now().Add(time.Second)
+
+	for len(exporter.names()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	i.Equal([]string{"GreetService.Greet"}, exporter.names())
+}
+
+var _ octrace.Exporter = (*spanRecorder)(nil)
+
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []*octrace.SpanData
+}
+
+func (r *spanRecorder) ExportSpan(s *octrace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, s)
+}
+
+func (r *spanRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, len(r.spans))
+
+	for i, s := range r.spans {
+		names[i] = s.Name
+	}
+
+	return names
+}
+
 var _ greetpb.GreetServiceServer = (*greeterService)(nil)
 
 type greeterService struct {
@@ -698,3 +1161,352 @@ func newGreeterClient(
 
 	return greetpb.NewGreetServiceClient(clientConn)
 }
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	started := make(chan struct{})
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithNoGateway(),
+		commonsgrpc.WithShutdownTimeout(50*time.Millisecond),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			server.RegisterService(&grpc.ServiceDesc{
+				ServiceName: "test.Stream",
+				HandlerType: (*any)(nil),
+				Streams: []grpc.StreamDesc{
+					{
+						StreamName:    "Block",
+						ServerStreams: true,
+						Handler: func(_ any, ss grpc.ServerStream) error {
+							close(started)
+
+							<-ss.Context().Done()
+
+							return nil
+						},
+					},
+				},
+			}, struct{}{})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	clientConn, err := grpcclient.NewConn(
+		"bufnet",
+		grpcclient.WithContextDialer(bufDialer),
+		grpcclient.WithNoTLS(),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	_, err = clientConn.NewStream(
+		context.Background(),
+		&grpc.StreamDesc{ServerStreams: true},
+		"/test.Stream/Block",
+	)
+	i.NoErr(err)
+
+	<-started
+
+	i.True(errors.Is(grpcServer.Close(), commonsgrpc.ErrShutdownTimeout))
+	i.NoErr(<-errCh)
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const bufSize = 1024 * 1024
+
+	lis := bufconn.Listen(bufSize)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	var serving atomic.Bool
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithHealthCheck(healthCheckerFunc(func(
+			_ context.Context,
+			service string,
+		) (healthpb.HealthCheckResponse_ServingStatus, error) {
+			if service != "" {
+				return healthpb.HealthCheckResponse_SERVING, nil
+			}
+
+			if !serving.Load() {
+				return healthpb.HealthCheckResponse_UNKNOWN, errors.New("database unreachable")
+			}
+
+			return healthpb.HealthCheckResponse_SERVING, nil
+		})),
+		commonsgrpc.WithHealthCheckInterval(10*time.Millisecond),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, new(greeterService))
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	clientConn, err := grpcclient.NewConn(
+		"bufnet",
+		grpcclient.WithContextDialer(bufDialer),
+		grpcclient.WithNoTLS(),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { _ = clientConn.Close() })
+
+	healthClient := healthpb.NewHealthClient(clientConn)
+
+	waitStatus := func(service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+
+		deadline := time.Now().Add(time.Second)
+
+		for {
+			resp, err := healthClient.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err == nil && resp.GetStatus() == expected {
+				return
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatalf("service %q: expected status %s, got %s, err: %v", service, expected, resp.GetStatus(), err)
+			}
+
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	// the failed checks are reported as not serving.
+	waitStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	waitStatus("GreetService", healthpb.HealthCheckResponse_SERVING)
+
+	serving.Store(true)
+
+	waitStatus("", healthpb.HealthCheckResponse_SERVING)
+}
+
+type healthCheckerFunc func(context.Context, string) (healthpb.HealthCheckResponse_ServingStatus, error)
+
+func (f healthCheckerFunc) CheckHealth(
+	ctx context.Context,
+	service string,
+) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	return f(ctx, service)
+}
+
+func TestReflection(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts []commonsgrpc.ServerOption
+
+		expectedCode codes.Code
+	}{
+		"Default": {
+			expectedCode: codes.Unimplemented,
+		},
+		"Enabled": {
+			opts:         []commonsgrpc.ServerOption{commonsgrpc.WithReflection(true)},
+			expectedCode: codes.OK,
+		},
+		"Disabled": {
+			opts:         []commonsgrpc.ServerOption{commonsgrpc.WithReflection(false)},
+			expectedCode: codes.Unimplemented,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			i := is.New(t)
+
+			lis := bufconn.Listen(1024 * 1024)
+
+			grpcServer, err := commonsgrpc.NewServer(append(
+				test.opts,
+				commonsgrpc.WithGRPCListener(lis),
+				commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+					greetpb.RegisterGreetServiceServer(server, new(greeterService))
+				}),
+			)...)
+			i.NoErr(err)
+
+			errCh := make(chan error, 1)
+
+			go func() {
+				errCh <- grpcServer.ListenAndServe()
+			}()
+
+			t.Cleanup(func() {
+				i.NoErr(grpcServer.Close())
+				i.NoErr(<-errCh)
+			})
+
+			clientConn, err := grpcclient.NewConn(
+				"bufnet",
+				grpcclient.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+					return lis.Dial()
+				}),
+				grpcclient.WithNoTLS(),
+			)
+			i.NoErr(err)
+
+			t.Cleanup(func() { _ = clientConn.Close() })
+
+			stream, err := reflectionpb.NewServerReflectionClient(clientConn).
+				ServerReflectionInfo(context.Background())
+			i.NoErr(err)
+
+			i.NoErr(stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}))
+
+			_, err = stream.Recv()
+			i.Equal(test.expectedCode, status.Code(err))
+		})
+	}
+}
+
+func TestKeepalive(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithKeepalive(
+			keepalive.ServerParameters{
+				MaxConnectionAge:      100 * time.Millisecond,
+				MaxConnectionAgeGrace: 100 * time.Millisecond,
+			},
+			keepalive.EnforcementPolicy{MinTime: time.Minute},
+		),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{
+				greetFunc: func() error { return nil },
+			})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	clientConn, err := grpcclient.NewConn(
+		"bufnet",
+		grpcclient.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpcclient.WithNoTLS(),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(clientConn.Close()) })
+
+	_, err = greetpb.NewGreetServiceClient(clientConn).Greet(
+		context.Background(),
+		&greetpb.GreetRequest{Greeting: &greetpb.Greeting{}},
+	)
+	i.NoErr(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// the server closes the connection once it reaches its max age.
+	i.True(clientConn.WaitForStateChange(ctx, connectivity.Ready))
+}
+
+func TestMaxMsgSize(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	lis := bufconn.Listen(1024 * 1024)
+	bufDialer := func(context.Context, string) (net.Conn, error) {
+		return lis.Dial()
+	}
+
+	grpcServer, err := commonsgrpc.NewServer(
+		commonsgrpc.WithGRPCListener(lis),
+		commonsgrpc.WithMaxRecvMsgSize(4096),
+		commonsgrpc.WithMaxSendMsgSize(1024),
+		commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+			greetpb.RegisterGreetServiceServer(server, &greeterService{})
+		}),
+	)
+	i.NoErr(err)
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- grpcServer.ListenAndServe()
+	}()
+
+	t.Cleanup(func() {
+		i.NoErr(grpcServer.Close())
+		i.NoErr(<-errCh)
+	})
+
+	greetClient := newGreeterClient(t, "bufnet", bufDialer)
+
+	greet := func(size int) error {
+		_, err := greetClient.Greet(context.Background(), &greetpb.GreetRequest{
+			Greeting: &greetpb.Greeting{FirstName: strings.Repeat("a", size)},
+		})
+
+		return err
+	}
+
+	i.NoErr(greet(512))
+
+	// the response, echoing the name, exceeds the send limit.
+	i.Equal(codes.ResourceExhausted, status.Code(greet(2048)))
+
+	// the request exceeds the receive limit.
+	i.Equal(codes.ResourceExhausted, status.Code(greet(8192)))
+}
@@ -0,0 +1,56 @@
+// Package tenant stores and retrieves the id of the tenant a request
+// is scoped to from a context.
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrNoTenantID is returned when a context or the request
+// metadata holds no tenant id.
+var ErrNoTenantID = errors.New("no tenant id")
+
+// MetadataKey is the metadata key carrying the tenant id.
+const MetadataKey = "x-tenant-id"
+
+type ctxTenantIDKey struct{}
+
+// WithTenantID returns a copy of ctx holding the tenant id.
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxTenantIDKey{}, id)
+}
+
+// FromContext returns the tenant id stored in ctx.
+// It returns an empty string if ctx holds no tenant id.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxTenantIDKey{}).(string)
+
+	return id
+}
+
+// RequireTenantID returns the tenant id stored in ctx,
+// or ErrNoTenantID if there is none.
+func RequireTenantID(ctx context.Context) (string, error) {
+	id := FromContext(ctx)
+	if id == "" {
+		return "", ErrNoTenantID
+	}
+
+	return id, nil
+}
+
+// FromIncomingMetadata returns the tenant id carried by the x-tenant-id
+// key of the incoming metadata. It can be used as the extractor of
+// the tenant isolation interceptor.
+func FromIncomingMetadata(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if values := md.Get(MetadataKey); len(values) > 0 && values[0] != "" {
+		return values[0], nil
+	}
+
+	return "", ErrNoTenantID
+}
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// certExpiryWarningWindow is the remaining validity of a certificate
+// under which a warning is logged on reload.
+const certExpiryWarningWindow = 30 * 24 * time.Hour
+
+// certReloader caches a certificate key pair loaded from disk,
+// reloading it once the reload interval elapses.
+type certReloader struct {
+	certFile, keyFile string
+	interval          time.Duration
+	logger            *zap.Logger
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	loadedAt time.Time
+}
+
+func newCertReloader(certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		logger:   zap.L(),
+	}
+
+	cert, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	r.cert = cert
+	r.loadedAt = time.Now()
+
+	return r, nil
+}
+
+func (r *certReloader) load() (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load key pair: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate: %w", err)
+	}
+
+	cert.Leaf = leaf
+
+	return &cert, nil
+}
+
+func (r *certReloader) tlsConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+
+	if time.Since(r.loadedAt) < r.interval {
+		defer r.mu.RUnlock()
+
+		return r.cert, nil
+	}
+
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// another handshake may have reloaded the certificate meanwhile.
+	if time.Since(r.loadedAt) < r.interval {
+		return r.cert, nil
+	}
+
+	// retry on the next interval, not on every handshake.
+	r.loadedAt = time.Now()
+
+	cert, err := r.load()
+	if err != nil {
+		r.logger.Error(
+			"reload tls certificate, serving the previous one",
+			zap.String("cert_file", r.certFile),
+			zap.Error(err),
+		)
+
+		return r.cert, nil
+	}
+
+	if remaining := time.Until(cert.Leaf.NotAfter); remaining < certExpiryWarningWindow {
+		r.logger.Warn(
+			"tls certificate expires soon",
+			zap.String("cert_file", r.certFile),
+			zap.Time("not_after", cert.Leaf.NotAfter),
+		)
+	}
+
+	r.cert = cert
+
+	return r.cert, nil
+}
@@ -0,0 +1,239 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	commonsgrpc "github.com/purposeinplay/go-commons/grpc"
+	"github.com/purposeinplay/go-commons/grpc/test_data/greetpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestDynamicTLS(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MissingFiles", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(bufconn.Listen(1)),
+			commonsgrpc.WithDynamicTLS("missing.crt", "missing.key", time.Minute),
+		)
+		i.True(err != nil)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		dir := t.TempDir()
+		certFile := filepath.Join(dir, "tls.crt")
+		keyFile := filepath.Join(dir, "tls.key")
+
+		writeCertificate(t, certFile, keyFile, 1)
+
+		lis := bufconn.Listen(1024 * 1024)
+
+		grpcServer, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(lis),
+			commonsgrpc.WithDynamicTLS(certFile, keyFile, 50*time.Millisecond),
+		)
+		i.NoErr(err)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- grpcServer.ListenAndServe()
+		}()
+
+		t.Cleanup(func() {
+			i.NoErr(grpcServer.Close())
+			i.NoErr(<-errCh)
+		})
+
+		serialNumber := func() int64 {
+			conn, err := lis.Dial()
+			i.NoErr(err)
+
+			tlsConn := tls.Client(conn, &tls.Config{
+				// nolint: gosec // the test certificates are self-signed.
+				InsecureSkipVerify: true,
+				NextProtos:         []string{"h2"},
+			})
+			defer func() { _ = tlsConn.Close() }()
+
+			i.NoErr(tlsConn.Handshake())
+
+			return tlsConn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+		}
+
+		i.Equal(int64(1), serialNumber())
+
+		writeCertificate(t, certFile, keyFile, 2)
+
+		time.Sleep(100 * time.Millisecond)
+
+		i.Equal(int64(2), serialNumber())
+	})
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MissingCertificate", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(bufconn.Listen(1)),
+			commonsgrpc.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+		)
+		i.True(errors.Is(err, commonsgrpc.ErrTLSCertificateRequired))
+	})
+
+	t.Run("MutualTLS", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		serverCert := newKeyPair(t, 1)
+		clientCert := newKeyPair(t, 2)
+
+		lis := bufconn.Listen(1024 * 1024)
+
+		grpcServer, err := commonsgrpc.NewServer(
+			commonsgrpc.WithGRPCListener(lis),
+			commonsgrpc.WithTLSConfig(&tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{serverCert},
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+				ClientCAs:    newCertPool(clientCert),
+			}),
+			commonsgrpc.WithRegisterServerFunc(func(server *grpc.Server) {
+				greetpb.RegisterGreetServiceServer(server, &greeterService{})
+			}),
+		)
+		i.NoErr(err)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- grpcServer.ListenAndServe()
+		}()
+
+		t.Cleanup(func() {
+			i.NoErr(grpcServer.Close())
+			i.NoErr(<-errCh)
+		})
+
+		greet := func(clientCerts ...tls.Certificate) error {
+			conn, err := grpc.NewClient(
+				"passthrough:///localhost",
+				grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+					return lis.Dial()
+				}),
+				grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					Certificates: clientCerts,
+					RootCAs:      newCertPool(serverCert),
+				})),
+			)
+			i.NoErr(err)
+
+			defer func() { _ = conn.Close() }()
+
+			_, err = greetpb.NewGreetServiceClient(conn).Greet(
+				context.Background(),
+				&greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "John"}},
+			)
+
+			return err
+		}
+
+		i.NoErr(greet(clientCert))
+		i.Equal(codes.Unavailable, status.Code(greet()))
+	})
+}
+
+func newKeyPair(t *testing.T, serialNumber int64) tls.Certificate {
+	t.Helper()
+
+	i := is.New(t)
+
+	cert, err := tls.X509KeyPair(generateCertificate(t, serialNumber))
+	i.NoErr(err)
+
+	return cert
+}
+
+func newCertPool(certs ...tls.Certificate) *x509.CertPool {
+	pool := x509.NewCertPool()
+
+	for _, c := range certs {
+		leaf, _ := x509.ParseCertificate(c.Certificate[0])
+		pool.AddCert(leaf)
+	}
+
+	return pool
+}
+
+func writeCertificate(t *testing.T, certFile, keyFile string, serialNumber int64) {
+	t.Helper()
+
+	i := is.New(t)
+
+	certPEM, keyPEM := generateCertificate(t, serialNumber)
+
+	i.NoErr(os.WriteFile(certFile, certPEM, 0o600))
+	i.NoErr(os.WriteFile(keyFile, keyPEM, 0o600))
+}
+
+// generateCertificate returns a self-signed certificate for localhost
+// and its key, PEM encoded.
+func generateCertificate(t *testing.T, serialNumber int64) (certPEM, keyPEM []byte) {
+	t.Helper()
+
+	i := is.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	i.NoErr(err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	i.NoErr(err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	i.NoErr(err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -45,3 +45,19 @@ func isMonitorOperationerNil(monitorOperationer MonitorOperationer) bool {
 		(reflect.ValueOf(c).Kind() == reflect.Ptr &&
 			reflect.ValueOf(c).IsNil())
 }
+
+func prependStreamServerOption(
+	newInterceptor grpc.StreamServerInterceptor,
+	interceptors []grpc.StreamServerInterceptor,
+) []grpc.StreamServerInterceptor {
+	newInterceptors := make(
+		[]grpc.StreamServerInterceptor,
+		len(interceptors)+1,
+	)
+
+	copy(newInterceptors[1:], interceptors)
+
+	newInterceptors[0] = newInterceptor
+
+	return newInterceptors
+}
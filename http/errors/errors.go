@@ -0,0 +1,96 @@
+// Package errors translates the errors of the application
+// into JSON http error responses.
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	commonshttp "github.com/purposeinplay/go-commons/http"
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// ErrorTranslator maps the errors of the application
+// to the status and the message of the http response.
+type ErrorTranslator interface {
+	Translate(err error) (status int, message string)
+}
+
+// A Rule translates the errors it matches to Status and Message.
+type Rule struct {
+	match func(error) bool
+
+	// The status code of the response.
+	Status int
+
+	// The user facing message of the response,
+	// defaults to the text of the status code.
+	Message string
+}
+
+// Is returns a rule matching the errors that are target,
+// according to errors.Is.
+func Is(target error, status int, message string) Rule {
+	return Rule{
+		match: func(err error) bool {
+			return errors.Is(err, target)
+		},
+		Status:  status,
+		Message: message,
+	}
+}
+
+// As returns a rule matching the errors that can be
+// assigned to a T, according to errors.As.
+func As[T error](status int, message string) Rule {
+	return Rule{
+		match: func(err error) bool {
+			var target T
+			return errors.As(err, &target)
+		},
+		Status:  status,
+		Message: message,
+	}
+}
+
+var _ ErrorTranslator = (*defaultTranslator)(nil)
+
+type defaultTranslator struct {
+	rules []Rule
+}
+
+// NewDefaultTranslator returns an ErrorTranslator applying the first
+// of rules matching the error. The errors not matched by any rule are
+// translated to an Internal Server Error, hiding their details.
+func NewDefaultTranslator(rules ...Rule) ErrorTranslator {
+	return &defaultTranslator{
+		rules: rules,
+	}
+}
+
+func (t *defaultTranslator) Translate(err error) (int, string) {
+	for _, r := range t.rules {
+		if !r.match(err) {
+			continue
+		}
+
+		if r.Message == "" {
+			return r.Status, http.StatusText(r.Status)
+		}
+
+		return r.Status, r.Message
+	}
+
+	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+}
+
+// WriteError writes the JSON error response of err,
+// translated by translator.
+func WriteError(w http.ResponseWriter, err error, translator ErrorTranslator) {
+	status, message := translator.Translate(err)
+
+	_ = render.SendJSON(w, status, &commonshttp.HTTPError{
+		Code:    status,
+		Message: message,
+	})
+}
@@ -0,0 +1,72 @@
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httperrors "github.com/purposeinplay/go-commons/http/errors"
+)
+
+var errNotFound = errors.New("not found")
+
+type validationError struct {
+	field string
+}
+
+func (e *validationError) Error() string {
+	return "invalid " + e.field
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	translator := httperrors.NewDefaultTranslator(
+		httperrors.Is(errNotFound, http.StatusNotFound, "resource not found"),
+		httperrors.As[*validationError](http.StatusUnprocessableEntity, ""),
+	)
+
+	tests := map[string]struct {
+		err          error
+		expectedCode int
+		expectedBody string
+	}{
+		"Is": {
+			err:          fmt.Errorf("get user: %w", errNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedBody: `{"code":404,"msg":"resource not found"}`,
+		},
+		"As": {
+			err:          fmt.Errorf("create user: %w", &validationError{field: "email"}),
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: `{"code":422,"msg":"Unprocessable Entity"}`,
+		},
+		"Unmatched": {
+			err:          errors.New("connection refused"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"code":500,"msg":"Internal Server Error"}`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rr := httptest.NewRecorder()
+
+			httperrors.WriteError(rr, test.err, translator)
+
+			if rr.Code != test.expectedCode {
+				t.Errorf("invalid status code, expected: %d, received: %d", test.expectedCode, rr.Code)
+			}
+
+			if body := rr.Body.String(); body != test.expectedBody {
+				t.Errorf("invalid body, expected: %s, received: %s", test.expectedBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Package metrics exports prometheus metrics of the http handlers.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsOption configures the prometheus middleware.
+type MetricsOption func(*metricsOptions)
+
+type metricsOptions struct {
+	pathTemplate func(*http.Request) string
+	registerer   prometheus.Registerer
+}
+
+// WithPathTemplate sets the function returning the path label of a request,
+// called once the request is handled. Defaults to the raw url path,
+// use ChiRoutePattern to avoid the high cardinality of the path parameters.
+func WithPathTemplate(f func(*http.Request) string) MetricsOption {
+	return func(o *metricsOptions) {
+		o.pathTemplate = f
+	}
+}
+
+// WithRegisterer sets the registerer of the metrics.
+// Defaults to prometheus.DefaultRegisterer.
+func WithRegisterer(reg prometheus.Registerer) MetricsOption {
+	return func(o *metricsOptions) {
+		o.registerer = reg
+	}
+}
+
+// ChiRoutePattern returns the chi route pattern matched by the request,
+// e.g. "/users/{id}", or the url path if the request isn't routed by chi.
+func ChiRoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return r.URL.Path
+}
+
+// NewPrometheusMiddleware returns a middleware that exports the
+// http_requests_total counter and the http_request_duration_seconds and
+// http_response_size_bytes histograms, labeled by method, path and status.
+//
+// The metrics are registered when the middleware is created, it panics
+// if they are already registered with the registerer.
+func NewPrometheusMiddleware(opts ...MetricsOption) func(http.Handler) http.Handler {
+	o := metricsOptions{
+		pathTemplate: func(r *http.Request) string {
+			return r.URL.Path
+		},
+		registerer: prometheus.DefaultRegisterer,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	labels := []string{"method", "path", "status"}
+
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of handled http requests.",
+	}, labels)
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the http requests handling.",
+		Buckets: prometheus.DefBuckets,
+	}, labels)
+
+	size := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_response_size_bytes",
+		Help:    "Size of the http response bodies.",
+		Buckets: prometheus.ExponentialBuckets(100, 10, 6),
+	}, labels)
+
+	o.registerer.MustRegister(requests, duration, size)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			rw := &responseRecorder{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(rw, r)
+
+			l := prometheus.Labels{
+				"method": r.Method,
+				"path":   o.pathTemplate(r),
+				"status": strconv.Itoa(rw.status),
+			}
+
+			requests.With(l).Inc()
+			duration.With(l).Observe(time.Since(start).Seconds())
+			size.With(l).Observe(float64(rw.size))
+		})
+	}
+}
+
+// responseRecorder records the status code and the size of the response.
+type responseRecorder struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+	size        int
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	n, err := w.ResponseWriter.Write(b)
+
+	w.size += n
+
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,77 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/purposeinplay/go-commons/http/metrics"
+)
+
+func TestPrometheusMiddleware(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	r := chi.NewRouter()
+
+	r.Use(metrics.NewPrometheusMiddleware(
+		metrics.WithRegisterer(reg),
+		metrics.WithPathTemplate(metrics.ChiRoutePattern),
+	))
+
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte("user"))
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/0"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP http_requests_total Number of handled http requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/users/{id}",status="200"} 2
+http_requests_total{method="GET",path="/users/{id}",status="404"} 1
+`), "http_requests_total"); err != nil {
+		t.Error(err)
+	}
+
+	if n := testutil.CollectAndCount(reg, "http_request_duration_seconds"); n != 2 {
+		t.Errorf("invalid duration series count, expected: 2, received: %d", n)
+	}
+
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP http_response_size_bytes Size of the http response bodies.
+# TYPE http_response_size_bytes histogram
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="100"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="1000"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="10000"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="100000"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="1e+06"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="1e+07"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="200",le="+Inf"} 2
+http_response_size_bytes_sum{method="GET",path="/users/{id}",status="200"} 8
+http_response_size_bytes_count{method="GET",path="/users/{id}",status="200"} 2
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="100"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="1000"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="10000"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="100000"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="1e+06"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="1e+07"} 1
+http_response_size_bytes_bucket{method="GET",path="/users/{id}",status="404",le="+Inf"} 1
+http_response_size_bytes_sum{method="GET",path="/users/{id}",status="404"} 0
+http_response_size_bytes_count{method="GET",path="/users/{id}",status="404"} 1
+`), "http_response_size_bytes"); err != nil {
+		t.Error(err)
+	}
+}
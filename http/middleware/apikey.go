@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	commonshttp "github.com/purposeinplay/go-commons/http"
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// ErrInvalidAPIKey is returned by an APIKeyStore when the key is not known.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+const (
+	authorizationHeader = "Authorization"
+	apiKeyScheme        = "ApiKey "
+)
+
+// APIKeyInfo describes the owner of an API key.
+type APIKeyInfo struct {
+	TenantID  string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// APIKeyStore validates API keys.
+type APIKeyStore interface {
+	Validate(ctx context.Context, key string) (APIKeyInfo, error)
+}
+
+var _ APIKeyStore = staticAPIKeyStore(nil)
+
+type staticAPIKeyStore map[string]APIKeyInfo
+
+// NewStaticAPIKeyStore returns an APIKeyStore backed by the given map.
+// It is meant for tests and local development.
+func NewStaticAPIKeyStore(keys map[string]APIKeyInfo) APIKeyStore {
+	return staticAPIKeyStore(keys)
+}
+
+func (s staticAPIKeyStore) Validate(_ context.Context, key string) (APIKeyInfo, error) {
+	info, ok := s[key]
+	if !ok {
+		return APIKeyInfo{}, ErrInvalidAPIKey
+	}
+
+	return info, nil
+}
+
+type ctxAPIKeyInfoKey struct{}
+
+// APIKeyInfoFromContext returns the APIKeyInfo stored in the context
+// by the API key middleware.
+func APIKeyInfoFromContext(ctx context.Context) (APIKeyInfo, bool) {
+	info, ok := ctx.Value(ctxAPIKeyInfoKey{}).(APIKeyInfo)
+
+	return info, ok
+}
+
+// APIKeyOption configures the API key middleware.
+type APIKeyOption func(*apiKeyOptions)
+
+type apiKeyOptions struct {
+	header string
+}
+
+// WithHeader reads the raw API key from the given header instead of
+// the "Authorization: ApiKey <key>" header.
+func WithHeader(header string) APIKeyOption {
+	return func(o *apiKeyOptions) {
+		o.header = header
+	}
+}
+
+// NewAPIKeyMiddleware returns a middleware that validates the API key
+// of the request against the store.
+//
+// Requests with a missing, unknown or expired key are rejected with
+// 401 Unauthorized, otherwise the APIKeyInfo is stored in the request context.
+// The store errors other than ErrInvalidAPIKey are answered with
+// 500 Internal Server Error.
+func NewAPIKeyMiddleware(
+	store APIKeyStore,
+	opts ...APIKeyOption,
+) func(http.Handler) http.Handler {
+	var o apiKeyOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromRequest(r, o.header)
+			if key == "" {
+				sendUnauthorized(w, "missing api key")
+				return
+			}
+
+			info, err := store.Validate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, ErrInvalidAPIKey) {
+					sendUnauthorized(w, "invalid api key")
+					return
+				}
+
+				// nolint: errcheck // the client can't be notified of a failed write.
+				_ = render.SendJSON(
+					w,
+					http.StatusInternalServerError,
+					commonshttp.InternalServerError("validate api key"),
+				)
+
+				return
+			}
+
+			if info.ExpiresAt != nil && info.ExpiresAt.Before(time.Now()) {
+				sendUnauthorized(w, "expired api key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(
+				context.WithValue(r.Context(), ctxAPIKeyInfoKey{}, info),
+			))
+		})
+	}
+}
+
+func apiKeyFromRequest(r *http.Request, header string) string {
+	if header != "" {
+		return strings.TrimSpace(r.Header.Get(header))
+	}
+
+	authz := r.Header.Get(authorizationHeader)
+
+	if len(authz) < len(apiKeyScheme) || !strings.EqualFold(authz[:len(apiKeyScheme)], apiKeyScheme) {
+		return ""
+	}
+
+	return strings.TrimSpace(authz[len(apiKeyScheme):])
+}
+
+func sendUnauthorized(w http.ResponseWriter, msg string) {
+	// nolint: errcheck // the client can't be notified of a failed write.
+	_ = render.SendJSON(w, http.StatusUnauthorized, commonshttp.UnauthorizedError(msg))
+}
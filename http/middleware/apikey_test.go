@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	expired := time.Now().Add(-time.Hour)
+
+	store := middleware.NewStaticAPIKeyStore(map[string]middleware.APIKeyInfo{
+		"valid":   {TenantID: "tenant", Scopes: []string{"read"}},
+		"expired": {TenantID: "tenant", ExpiresAt: &expired},
+	})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok := middleware.APIKeyInfoFromContext(r.Context())
+		if !ok || info.TenantID != "tenant" {
+			t.Error("expected api key info in context")
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := map[string]struct {
+		store        middleware.APIKeyStore
+		opts         []middleware.APIKeyOption
+		header       string
+		value        string
+		expectedCode int
+	}{
+		"Valid": {
+			header:       "Authorization",
+			value:        "ApiKey valid",
+			expectedCode: http.StatusOK,
+		},
+		"CustomHeader": {
+			opts:         []middleware.APIKeyOption{middleware.WithHeader("X-Api-Key")},
+			header:       "X-Api-Key",
+			value:        "valid",
+			expectedCode: http.StatusOK,
+		},
+		"Missing": {
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Unknown": {
+			header:       "Authorization",
+			value:        "ApiKey unknown",
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Expired": {
+			header:       "Authorization",
+			value:        "ApiKey expired",
+			expectedCode: http.StatusUnauthorized,
+		},
+		"WrongScheme": {
+			header:       "Authorization",
+			value:        "Bearer valid",
+			expectedCode: http.StatusUnauthorized,
+		},
+		"StoreError": {
+			store:        failingAPIKeyStore{},
+			header:       "Authorization",
+			value:        "ApiKey valid",
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				r.Header.Set(test.header, test.value)
+			}
+
+			rr := httptest.NewRecorder()
+
+			testStore := store
+			if test.store != nil {
+				testStore = test.store
+			}
+
+			middleware.NewAPIKeyMiddleware(testStore, test.opts...)(next).ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedCode {
+				t.Errorf("invalid status code, expected: %d, received: %d", test.expectedCode, rr.Code)
+			}
+		})
+	}
+}
+
+type failingAPIKeyStore struct{}
+
+func (failingAPIKeyStore) Validate(context.Context, string) (middleware.APIKeyInfo, error) {
+	// nolint: goerr113 // allow dynamic error.
+	return middleware.APIKeyInfo{}, errors.New("store unavailable")
+}
@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// defaultMaxBodySize is the default maximum size of the logged bodies.
+const defaultMaxBodySize = 64 << 10
+
+// BodyLogOption configures the body logger middleware.
+type BodyLogOption func(*bodyLogOptions)
+
+type bodyLogOptions struct {
+	redactedFields []string
+	maxBodySize    int64
+}
+
+// WithRedactJSONFields zeroes the values of the given keys, matched
+// case-insensitively at any depth, in the JSON bodies before logging them.
+func WithRedactJSONFields(fields ...string) BodyLogOption {
+	return func(o *bodyLogOptions) {
+		o.redactedFields = append(o.redactedFields, fields...)
+	}
+}
+
+// WithMaxBodySize truncates the logged bodies to n bytes. Defaults to 64KiB.
+// The bodies read by the handlers and sent to the clients are not truncated.
+func WithMaxBodySize(n int64) BodyLogOption {
+	return func(o *bodyLogOptions) {
+		o.maxBodySize = n
+	}
+}
+
+// NewBodyLoggerMiddleware returns a middleware that logs the request and
+// the response bodies of every request once it's handled.
+//
+// The request body is restored for the next handlers, and the response
+// is written through as usual. The bodies that can't be parsed as JSON,
+// e.g. the truncated ones, are logged as is.
+func NewBodyLoggerMiddleware(logger *zap.Logger, opts ...BodyLogOption) func(http.Handler) http.Handler {
+	o := bodyLogOptions{
+		maxBodySize: defaultMaxBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody []byte
+
+			if r.Body != nil {
+				// read up to one byte past the limit to detect the truncation.
+				reqBody, _ = io.ReadAll(io.LimitReader(r.Body, o.maxBodySize+1))
+
+				r.Body = readCloser{
+					Reader: io.MultiReader(bytes.NewReader(reqBody), r.Body),
+					Closer: r.Body,
+				}
+			}
+
+			bw := &bodyCapturingWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+				maxSize:        o.maxBodySize,
+			}
+
+			next.ServeHTTP(bw, r)
+
+			logger.Info(
+				"http request body",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", bw.status),
+				zap.String("request_body", o.loggedBody(reqBody)),
+				zap.Bool("request_body_truncated", int64(len(reqBody)) > o.maxBodySize),
+				zap.String("response_body", o.loggedBody(bw.body.Bytes())),
+				zap.Bool("response_body_truncated", bw.truncated),
+			)
+		})
+	}
+}
+
+// loggedBody truncates and redacts the body.
+func (o *bodyLogOptions) loggedBody(body []byte) string {
+	if int64(len(body)) > o.maxBodySize {
+		return string(body[:o.maxBodySize])
+	}
+
+	if len(o.redactedFields) == 0 {
+		return string(body)
+	}
+
+	var v any
+
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(o.redact(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+func (o *bodyLogOptions) redact(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, field := range v {
+			if o.isRedacted(k) {
+				v[k] = zeroValue(field)
+			} else {
+				v[k] = o.redact(field)
+			}
+		}
+
+	case []any:
+		for i, elem := range v {
+			v[i] = o.redact(elem)
+		}
+	}
+
+	return v
+}
+
+func (o *bodyLogOptions) isRedacted(key string) bool {
+	for _, f := range o.redactedFields {
+		if strings.EqualFold(f, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zeroValue returns the zero value of the JSON type of v.
+func zeroValue(v any) any {
+	switch v.(type) {
+	case string:
+		return ""
+	case float64:
+		return 0
+	case bool:
+		return false
+	default:
+		return nil
+	}
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyCapturingWriter records the status code and the beginning
+// of the response body.
+type bodyCapturingWriter struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+
+	maxSize   int64
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *bodyCapturingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyCapturingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	if remaining := w.maxSize - int64(w.body.Len()); int64(len(b)) > remaining {
+		w.body.Write(b[:max(remaining, 0)])
+		w.truncated = true
+	} else {
+		w.body.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *bodyCapturingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBodyLoggerMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts        []middleware.BodyLogOption
+		requestBody string
+
+		expectedRequestBody  string
+		expectedResponseBody string
+		expectedTruncated    bool
+	}{
+		"Redacted": {
+			opts:                 []middleware.BodyLogOption{middleware.WithRedactJSONFields("password", "token")},
+			requestBody:          `{"user":{"name":"john","password":"secret"},"age":3}`,
+			expectedRequestBody:  `{"age":3,"user":{"name":"john","password":""}}`,
+			expectedResponseBody: `{"token":""}`,
+		},
+		"Truncated": {
+			opts:                 []middleware.BodyLogOption{middleware.WithMaxBodySize(5)},
+			requestBody:          `{"user":"john"}`,
+			expectedRequestBody:  `{"use`,
+			expectedResponseBody: `{"tok`,
+			expectedTruncated:    true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zapcore.InfoLevel)
+
+			var handlerBody string
+
+			handler := middleware.NewBodyLoggerMiddleware(zap.New(core), test.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					b, _ := io.ReadAll(r.Body)
+					handlerBody = string(b)
+
+					_, _ = w.Write([]byte(`{"token":"abc"}`))
+				},
+			))
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.requestBody)))
+
+			if handlerBody != test.requestBody {
+				t.Errorf("request body not restored: %s", handlerBody)
+			}
+
+			if body := rr.Body.String(); body != `{"token":"abc"}` {
+				t.Errorf("invalid response body: %s", body)
+			}
+
+			if logs.Len() != 1 {
+				t.Fatalf("expected one log, received: %d", logs.Len())
+			}
+
+			fields := logs.All()[0].ContextMap()
+
+			if fields["request_body"] != test.expectedRequestBody {
+				t.Errorf("invalid logged request body: %s", fields["request_body"])
+			}
+
+			if fields["response_body"] != test.expectedResponseBody {
+				t.Errorf("invalid logged response body: %s", fields["response_body"])
+			}
+
+			if fields["request_body_truncated"] != test.expectedTruncated ||
+				fields["response_body_truncated"] != test.expectedTruncated {
+				t.Errorf("invalid truncation: %v", fields)
+			}
+		})
+	}
+}
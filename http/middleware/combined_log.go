@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// combinedLogTimeFormat is the time layout of the Combined Log Format.
+const combinedLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// NewCombinedLogMiddleware returns a middleware that writes one line per
+// request to w, in the Apache Combined Log Format:
+//
+//	<ip> - <user> [<time>] "<method> <uri> <proto>" <status> <bytes> "<referrer>" "<user-agent>"
+//
+// The user is the request id set by chi's RequestID middleware, if any.
+// Writes to w are serialised, so it can be shared by concurrent requests.
+func NewCombinedLogMiddleware(w io.Writer) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			cw := &countingWriter{
+				ResponseWriter: rw,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(cw, r)
+
+			line := fmt.Sprintf(
+				"%s - %s [%s] %q %d %s %q %q\n",
+				remoteIP(r),
+				orDash(chimiddleware.GetReqID(r.Context())),
+				start.Format(combinedLogTimeFormat),
+				r.Method+" "+r.RequestURI+" "+r.Proto,
+				cw.status,
+				bytesOrDash(cw.bytes),
+				r.Referer(),
+				r.UserAgent(),
+			)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// nolint: errcheck // logging must not fail the request.
+			_, _ = io.WriteString(w, line)
+		})
+	}
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return orDash(r.RemoteAddr)
+	}
+
+	return host
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func bytesOrDash(n int64) string {
+	if n == 0 {
+		return "-"
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+// countingWriter records the status code and the size of the response.
+type countingWriter struct {
+	http.ResponseWriter
+
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *countingWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	n, err := w.ResponseWriter.Write(b)
+
+	w.bytes += int64(n)
+
+	return n, err
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestCombinedLogMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := chimiddleware.RequestID(middleware.NewCombinedLogMiddleware(&buf)(
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte("hello"))
+		}),
+	))
+
+	r := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("Referer", "https://example.com")
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set(chimiddleware.RequestIDHeader, "req-1")
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	expected := regexp.MustCompile(
+		`^10\.0\.0\.1 - req-1 \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] ` +
+			`"POST /users\?id=1 HTTP/1\.1" 201 5 "https://example\.com" "test-agent"\n$`,
+	)
+
+	if !expected.MatchString(buf.String()) {
+		t.Errorf("invalid log line: %s", buf.String())
+	}
+}
@@ -0,0 +1,281 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// CompressionOption configures the compression middleware.
+type CompressionOption func(*compressionOptions)
+
+type compressionOptions struct {
+	skipContentTypes []string
+	minSize          int
+}
+
+// WithSkipContentTypes disables the compression of the responses with the
+// given content types, e.g. the already compressed "image/jpeg".
+// A type ending in "/*", e.g. "video/*", matches all its subtypes.
+func WithSkipContentTypes(types ...string) CompressionOption {
+	return func(o *compressionOptions) {
+		o.skipContentTypes = append(o.skipContentTypes, types...)
+	}
+}
+
+// WithMinSize disables the compression of the responses smaller than
+// n bytes, which wouldn't get much smaller.
+func WithMinSize(n int) CompressionOption {
+	return func(o *compressionOptions) {
+		o.minSize = n
+	}
+}
+
+// NewCompressionMiddleware returns a middleware that compresses the
+// responses using brotli or gzip, in this order of preference, depending
+// on the encodings allowed by the Accept-Encoding header.
+// The responses are sent as is to the clients that accept neither.
+//
+// The level is a gzip level, e.g. gzip.BestSpeed, mapped to the closest
+// brotli level for the brotli responses.
+func NewCompressionMiddleware(level int, opts ...CompressionOption) func(http.Handler) http.Handler {
+	var o compressionOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				opts:           &o,
+				encoding:       encoding,
+				level:          level,
+				status:         http.StatusOK,
+			}
+
+			defer cw.close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding returns the preferred encoding among the accepted ones,
+// or an empty string if none of them is accepted.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := make(map[string]float64)
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+
+		q := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || k != "q" {
+				continue
+			}
+
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		accepted[strings.ToLower(strings.TrimSpace(coding))] = q
+	}
+
+	for _, encoding := range []string{"br", "gzip"} {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+
+		if ok && q > 0 {
+			return encoding
+		}
+	}
+
+	return ""
+}
+
+// compressWriter buffers the beginning of the response until it's
+// known whether the response should be compressed.
+type compressWriter struct {
+	http.ResponseWriter
+
+	opts     *compressionOptions
+	encoding string
+	level    int
+
+	status      int
+	wroteHeader bool
+	buf         bytes.Buffer
+
+	// started is set once the header is sent, after which the
+	// response is written through the encoder, if any.
+	started bool
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.status = code
+	w.wroteHeader = true
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	if w.started {
+		return w.write(b)
+	}
+
+	w.buf.Write(b)
+
+	if w.buf.Len() >= w.opts.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends the buffered response, compressed if possible,
+// to the client.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		if err := w.start(w.buf.Len() > 0); err != nil {
+			return
+		}
+	}
+
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// start sends the header and the buffered response, choosing whether
+// the rest of the response is compressed.
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+
+	h := w.Header()
+
+	if h.Get("Content-Type") == "" && w.buf.Len() > 0 {
+		h.Set("Content-Type", http.DetectContentType(w.buf.Bytes()))
+	}
+
+	if compress && h.Get("Content-Encoding") == "" && !w.opts.isSkipped(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+
+		w.encoder = w.newEncoder()
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.write(w.buf.Bytes())
+
+	w.buf.Reset()
+
+	return err
+}
+
+func (w *compressWriter) write(b []byte) (int, error) {
+	if w.encoder != nil {
+		return w.encoder.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// close sends the responses smaller than the minimum size as is,
+// and flushes the encoder of the compressed ones.
+func (w *compressWriter) close() {
+	if !w.started {
+		if err := w.start(false); err != nil {
+			return
+		}
+	}
+
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
+
+func (w *compressWriter) newEncoder() io.WriteCloser {
+	if w.encoding == "br" {
+		return brotli.NewWriterLevel(w.ResponseWriter, brotliLevel(w.level))
+	}
+
+	// the level is validated by the middleware constructor.
+	gw, _ := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+
+	return gw
+}
+
+// brotliLevel maps the gzip level to a brotli level.
+func brotliLevel(level int) int {
+	switch level {
+	case gzip.DefaultCompression:
+		return brotli.DefaultCompression
+
+	case gzip.HuffmanOnly, gzip.NoCompression:
+		return brotli.BestSpeed
+
+	default:
+		return level * brotli.BestCompression / gzip.BestCompression
+	}
+}
+
+func (o *compressionOptions) isSkipped(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, skipped := range o.skipContentTypes {
+		if mediaRangeMatches(strings.ToLower(skipped), mediaType) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestCompressionMiddleware(t *testing.T) {
+	t.Parallel()
+
+	body := strings.Repeat("compressible ", 20)
+
+	tests := map[string]struct {
+		opts           []middleware.CompressionOption
+		acceptEncoding string
+		contentType    string
+		body           string
+
+		expectedEncoding string
+	}{
+		"Brotli": {
+			acceptEncoding:   "gzip, deflate, br",
+			body:             body,
+			expectedEncoding: "br",
+		},
+		"Gzip": {
+			acceptEncoding:   "gzip, br;q=0",
+			body:             body,
+			expectedEncoding: "gzip",
+		},
+		"Wildcard": {
+			acceptEncoding:   "*",
+			body:             body,
+			expectedEncoding: "br",
+		},
+		"NotAccepted": {
+			acceptEncoding:   "deflate",
+			body:             body,
+			expectedEncoding: "",
+		},
+		"SkippedContentType": {
+			opts:             []middleware.CompressionOption{middleware.WithSkipContentTypes("image/*")},
+			acceptEncoding:   "gzip",
+			contentType:      "image/jpeg",
+			body:             body,
+			expectedEncoding: "",
+		},
+		"BelowMinSize": {
+			opts:             []middleware.CompressionOption{middleware.WithMinSize(1024)},
+			acceptEncoding:   "gzip",
+			body:             body,
+			expectedEncoding: "",
+		},
+		"AboveMinSize": {
+			opts:             []middleware.CompressionOption{middleware.WithMinSize(16)},
+			acceptEncoding:   "gzip",
+			body:             body,
+			expectedEncoding: "gzip",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := middleware.NewCompressionMiddleware(gzip.DefaultCompression, test.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					if test.contentType != "" {
+						w.Header().Set("Content-Type", test.contentType)
+					}
+
+					w.WriteHeader(http.StatusCreated)
+
+					// write in chunks to exercise the buffering.
+					for _, word := range strings.SplitAfter(test.body, " ") {
+						_, _ = io.WriteString(w, word)
+					}
+				},
+			))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			r.Header.Set("Accept-Encoding", test.acceptEncoding)
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != http.StatusCreated {
+				t.Errorf("invalid status code, expected: %d, received: %d", http.StatusCreated, rr.Code)
+			}
+
+			encoding := rr.Header().Get("Content-Encoding")
+			if encoding != test.expectedEncoding {
+				t.Fatalf("invalid encoding, expected: %q, received: %q", test.expectedEncoding, encoding)
+			}
+
+			var reader io.Reader = rr.Body
+
+			switch encoding {
+			case "br":
+				reader = brotli.NewReader(rr.Body)
+
+			case "gzip":
+				gr, err := gzip.NewReader(rr.Body)
+				if err != nil {
+					t.Fatalf("new gzip reader: %s", err)
+				}
+
+				reader = gr
+			}
+
+			decoded, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("read body: %s", err)
+			}
+
+			if string(decoded) != test.body {
+				t.Errorf("invalid body, expected: %q, received: %q", test.body, decoded)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/cors"
+)
+
+// CORSOption configures the CORS middleware.
+type CORSOption func(*corsOptions)
+
+type corsOptions struct {
+	allowedOrigins   []string
+	allowedMethods   []string
+	allowedHeaders   []string
+	allowCredentials bool
+	maxAge           time.Duration
+}
+
+// WithAllowedOrigins sets the origins allowed to make cross-origin
+// requests. "*" allows any origin, unless credentials are allowed.
+func WithAllowedOrigins(origins ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.allowedOrigins = append(o.allowedOrigins, origins...)
+	}
+}
+
+// WithAllowedMethods sets the methods allowed in cross-origin
+// requests. Defaults to GET, POST and HEAD.
+func WithAllowedMethods(methods ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.allowedMethods = append(o.allowedMethods, methods...)
+	}
+}
+
+// WithAllowedHeaders sets the headers that the cross-origin requests
+// may carry. Defaults to Origin, Accept and Content-Type.
+func WithAllowedHeaders(headers ...string) CORSOption {
+	return func(o *corsOptions) {
+		o.allowedHeaders = append(o.allowedHeaders, headers...)
+	}
+}
+
+// WithAllowCredentials allows the cross-origin requests to carry cookies
+// and authorization headers.
+//
+// As required by the CORS spec, the "*" wildcard doesn't match any origin
+// once credentials are allowed, the origins must be listed explicitly.
+func WithAllowCredentials() CORSOption {
+	return func(o *corsOptions) {
+		o.allowCredentials = true
+	}
+}
+
+// WithMaxAge sets how long the results of the preflight requests
+// can be cached.
+func WithMaxAge(d time.Duration) CORSOption {
+	return func(o *corsOptions) {
+		o.maxAge = d
+	}
+}
+
+// NewCORSMiddleware returns a middleware that handles the preflight
+// OPTIONS requests and sets the Access-Control-* headers of the
+// cross-origin requests.
+//
+// No origin is allowed until configured using WithAllowedOrigins.
+func NewCORSMiddleware(opts ...CORSOption) func(http.Handler) http.Handler {
+	var o corsOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return cors.New(cors.Options{
+		AllowOriginFunc:  o.isOriginAllowed,
+		AllowedMethods:   o.allowedMethods,
+		AllowedHeaders:   o.allowedHeaders,
+		AllowCredentials: o.allowCredentials,
+		MaxAge:           int(o.maxAge.Seconds()),
+	}).Handler
+}
+
+func (o *corsOptions) isOriginAllowed(_ *http.Request, origin string) bool {
+	for _, allowed := range o.allowedOrigins {
+		if allowed == "*" && !o.allowCredentials {
+			return true
+		}
+
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+
+	return false
+}
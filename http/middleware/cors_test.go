@@ -0,0 +1,143 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts    []middleware.CORSOption
+		method  string
+		headers map[string]string
+
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		"PreflightAllowed": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("https://example.com"),
+				middleware.WithAllowedMethods(http.MethodPut),
+				middleware.WithAllowedHeaders("Authorization"),
+				middleware.WithMaxAge(time.Minute),
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                         "https://example.com",
+				"Access-Control-Request-Method":  http.MethodPut,
+				"Access-Control-Request-Headers": "Authorization",
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://example.com",
+				"Access-Control-Allow-Methods": http.MethodPut,
+				"Access-Control-Allow-Headers": "Authorization",
+				"Access-Control-Max-Age":       "60",
+			},
+		},
+		"PreflightMethodNotAllowed": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("https://example.com"),
+			},
+			method: http.MethodOptions,
+			headers: map[string]string{
+				"Origin":                        "https://example.com",
+				"Access-Control-Request-Method": http.MethodDelete,
+			},
+			expectedStatus: http.StatusOK,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		"SimpleAllowed": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("*"),
+			},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+		"SimpleOriginNotAllowed": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("https://example.com"),
+			},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://evil.com"},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+		"CredentialsAllowed": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("https://example.com"),
+				middleware.WithAllowCredentials(),
+			},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://example.com",
+				"Access-Control-Allow-Credentials": "true",
+			},
+		},
+		"CredentialsWildcardRejected": {
+			opts: []middleware.CORSOption{
+				middleware.WithAllowedOrigins("*"),
+				middleware.WithAllowCredentials(),
+			},
+			method:         http.MethodGet,
+			headers:        map[string]string{"Origin": "https://example.com"},
+			expectedStatus: http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "",
+				"Access-Control-Allow-Credentials": "",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := middleware.NewCORSMiddleware(test.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+			))
+
+			r := httptest.NewRequest(test.method, "/", nil)
+
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedStatus {
+				t.Errorf("invalid status code, expected: %d, received: %d", test.expectedStatus, rr.Code)
+			}
+
+			for k, v := range test.expectedHeaders {
+				if received := rr.Header().Get(k); received != v {
+					t.Errorf("invalid %s header, expected: %q, received: %q", k, v, received)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestHMACSignatureMiddleware(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+
+	sign := func(payload string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(payload))
+
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if string(body) != "payload" {
+			t.Errorf("body not restored, received: %s", body)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := map[string]struct {
+		opts         []middleware.HMACOption
+		headers      map[string]string
+		expectedCode int
+	}{
+		"Valid": {
+			headers:      map[string]string{"X-Signature-256": sign("payload")},
+			expectedCode: http.StatusOK,
+		},
+		"CustomHeader": {
+			opts:         []middleware.HMACOption{middleware.WithSignatureHeader("X-Hub-Signature-256")},
+			headers:      map[string]string{"X-Hub-Signature-256": sign("payload")},
+			expectedCode: http.StatusOK,
+		},
+		"Missing": {
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Invalid": {
+			headers:      map[string]string{"X-Signature-256": sign("other")},
+			expectedCode: http.StatusUnauthorized,
+		},
+		"Timestamp": {
+			opts: []middleware.HMACOption{middleware.WithToleranceWindow(time.Minute)},
+			headers: map[string]string{
+				"X-Signature-256":       sign(now + ".payload"),
+				"X-Signature-Timestamp": now,
+			},
+			expectedCode: http.StatusOK,
+		},
+		"TooLarge": {
+			opts:         []middleware.HMACOption{middleware.WithHMACMaxBodySize(3)},
+			headers:      map[string]string{"X-Signature-256": sign("payload")},
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		"ExpiredTimestamp": {
+			opts: []middleware.HMACOption{middleware.WithToleranceWindow(time.Minute)},
+			headers: map[string]string{
+				"X-Signature-256":       sign(old + ".payload"),
+				"X-Signature-Timestamp": old,
+			},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+
+			rr := httptest.NewRecorder()
+
+			middleware.NewHMACSignatureMiddleware(secret, test.opts...)(next).ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedCode {
+				t.Errorf("invalid status code, expected: %d, received: %d", test.expectedCode, rr.Code)
+			}
+		})
+	}
+
+	t.Run("TooLargeUnknownLength", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+		r.ContentLength = -1
+		r.Header.Set("X-Signature-256", sign("payload"))
+
+		rr := httptest.NewRecorder()
+
+		middleware.NewHMACSignatureMiddleware(
+			secret,
+			middleware.WithHMACMaxBodySize(3),
+		)(next).ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("invalid status code, expected: 413, received: %d", rr.Code)
+		}
+	})
+}
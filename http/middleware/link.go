@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type paginationLinks struct {
+	self, next, prev string
+}
+
+type ctxPaginationLinksKey struct{}
+
+// SetPaginationLinks sets the pagination URLs written by the link header
+// middleware. Empty URLs are omitted from the header.
+//
+// It must be called before the handler starts writing the response.
+func SetPaginationLinks(ctx context.Context, self, next, prev string) {
+	links, ok := ctx.Value(ctxPaginationLinksKey{}).(*paginationLinks)
+	if !ok {
+		return
+	}
+
+	links.self, links.next, links.prev = self, next, prev
+}
+
+// BuildPageURL returns base with the page and per_page query
+// parameters set.
+func BuildPageURL(base *url.URL, page, perPage int) string {
+	u := *base
+
+	q := u.Query()
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(perPage))
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// NewLinkHeaderMiddleware returns a middleware that sets the RFC 5988
+// Link response header from the URLs given to SetPaginationLinks:
+//
+//	Link: <https://api/users?page=3>; rel="next", <https://api/users?page=1>; rel="prev"
+func NewLinkHeaderMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lw := &linkWriter{
+				ResponseWriter: w,
+				links:          new(paginationLinks),
+			}
+
+			next.ServeHTTP(lw, r.WithContext(
+				context.WithValue(r.Context(), ctxPaginationLinksKey{}, lw.links),
+			))
+
+			lw.setHeader()
+		})
+	}
+}
+
+// linkWriter sets the Link header before the response headers are sent.
+type linkWriter struct {
+	http.ResponseWriter
+
+	links *paginationLinks
+	once  sync.Once
+}
+
+func (w *linkWriter) WriteHeader(code int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *linkWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *linkWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *linkWriter) setHeader() {
+	w.once.Do(func() {
+		var values []string
+
+		for _, l := range []struct{ url, rel string }{
+			{w.links.self, "self"},
+			{w.links.next, "next"},
+			{w.links.prev, "prev"},
+		} {
+			if l.url != "" {
+				values = append(values, "<"+l.url+`>; rel="`+l.rel+`"`)
+			}
+		}
+
+		if len(values) > 0 {
+			w.Header().Set("Link", strings.Join(values, ", "))
+		}
+	})
+}
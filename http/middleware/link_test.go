@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestLinkHeaderMiddleware(t *testing.T) {
+	t.Parallel()
+
+	base, err := url.Parse("https://api.example.com/users?sort=name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := middleware.NewLinkHeaderMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetPaginationLinks(
+			r.Context(),
+			"",
+			middleware.BuildPageURL(base, 3, 10),
+			middleware.BuildPageURL(base, 1, 10),
+		)
+
+		_, _ = w.Write([]byte("[]"))
+	}))
+
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	expected := `<https://api.example.com/users?page=3&per_page=10&sort=name>; rel="next", ` +
+		`<https://api.example.com/users?page=1&per_page=10&sort=name>; rel="prev"`
+
+	if link := rr.Header().Get("Link"); link != expected {
+		t.Errorf("invalid link header, expected: %s, received: %s", expected, link)
+	}
+}
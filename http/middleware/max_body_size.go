@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// multipartMaxMemory is the part of the multipart forms stored in memory,
+// the rest of it being stored in temporary files.
+const multipartMaxMemory = 32 << 20
+
+// NewMaxBodySizeMiddleware returns a middleware that limits the request
+// bodies to maxBytes.
+//
+// The requests declaring a larger Content-Length and the form requests
+// whose body exceeds the limit are answered with a 413 before reaching
+// the next handler. The forms are parsed so that the next handlers
+// can use r.Form and r.MultipartForm. For the other bodies, the reads
+// past the limit return a *http.MaxBytesError.
+func NewMaxBodySizeMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > maxBytes {
+				writeRequestEntityTooLarge(w)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+
+			if err := parseForm(r); err != nil {
+				var maxBytesErr *http.MaxBytesError
+
+				if errors.As(err, &maxBytesErr) {
+					writeRequestEntityTooLarge(w)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseForm parses the url-encoded and the multipart form bodies.
+func parseForm(r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		return r.ParseForm()
+
+	case "multipart/form-data":
+		return r.ParseMultipartForm(multipartMaxMemory)
+
+	default:
+		return nil
+	}
+}
+
+func writeRequestEntityTooLarge(w http.ResponseWriter) {
+	_ = render.SendJSON(w, http.StatusRequestEntityTooLarge, map[string]string{
+		"error": "request entity too large",
+	})
+}
@@ -0,0 +1,109 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestMaxBodySizeMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		contentType   string
+		body          string
+		unknownLength bool
+
+		expectedStatus int
+		expectedBody   string
+	}{
+		"WithinLimit": {
+			contentType:    "application/json",
+			body:           `{"a":1}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"a":1}`,
+		},
+		"ContentLengthExceeded": {
+			contentType:    "application/json",
+			body:           `{"name":"john"}`,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"request entity too large"}`,
+		},
+		"FormExceeded": {
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "name=john&age=3",
+			unknownLength:  true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   `{"error":"request entity too large"}`,
+		},
+		"FormWithinLimit": {
+			contentType:    "application/x-www-form-urlencoded",
+			body:           "name=john",
+			unknownLength:  true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "john",
+		},
+		"BodyExceeded": {
+			contentType:    "application/json",
+			body:           `{"name":"john"}`,
+			unknownLength:  true,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "max bytes",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := middleware.NewMaxBodySizeMiddleware(10)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					if r.Form != nil {
+						_, _ = io.WriteString(w, r.Form.Get("name"))
+						return
+					}
+
+					b, err := io.ReadAll(r.Body)
+
+					var maxBytesErr *http.MaxBytesError
+
+					if errors.As(err, &maxBytesErr) {
+						w.WriteHeader(http.StatusBadRequest)
+						_, _ = io.WriteString(w, "max bytes")
+
+						return
+					}
+
+					_, _ = w.Write(b)
+				},
+			))
+
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(test.body))
+
+			r.Header.Set("Content-Type", test.contentType)
+
+			if test.unknownLength {
+				r.ContentLength = -1
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedStatus {
+				t.Errorf("invalid status code, expected: %d, received: %d", test.expectedStatus, rr.Code)
+			}
+
+			if body := rr.Body.String(); body != test.expectedBody {
+				t.Errorf("invalid body, expected: %s, received: %s", test.expectedBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrNotProtoMessage is returned by the protobuf renderer
+// when the data is not a proto.Message.
+var ErrNotProtoMessage = errors.New("data is not a proto message")
+
+// Renderer writes data to the response in a given format.
+type Renderer interface {
+	Render(w http.ResponseWriter, data any) error
+}
+
+// RendererFunc is an adapter to allow the use of
+// ordinary functions as a Renderer.
+type RendererFunc func(w http.ResponseWriter, data any) error
+
+// Render calls f(w, data).
+func (f RendererFunc) Render(w http.ResponseWriter, data any) error {
+	return f(w, data)
+}
+
+// JSONRenderer returns a Renderer that encodes the data as JSON.
+func JSONRenderer() Renderer {
+	return RendererFunc(func(w http.ResponseWriter, data any) error {
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			return fmt.Errorf("encode json: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// XMLRenderer returns a Renderer that encodes the data as XML.
+func XMLRenderer() Renderer {
+	return RendererFunc(func(w http.ResponseWriter, data any) error {
+		if err := xml.NewEncoder(w).Encode(data); err != nil {
+			return fmt.Errorf("encode xml: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// ProtobufRenderer returns a Renderer that encodes the data, which must be
+// a proto.Message, in the protobuf wire format.
+func ProtobufRenderer() Renderer {
+	return RendererFunc(func(w http.ResponseWriter, data any) error {
+		m, ok := data.(proto.Message)
+		if !ok {
+			return ErrNotProtoMessage
+		}
+
+		b, err := proto.Marshal(m)
+		if err != nil {
+			return fmt.Errorf("marshal proto: %w", err)
+		}
+
+		if _, err := w.Write(b); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+
+		return nil
+	})
+}
+
+type ctxRendererKey struct{}
+
+// GetRenderer returns the Renderer selected by the content
+// negotiation middleware, or nil if there is none.
+func GetRenderer(ctx context.Context) Renderer {
+	r, _ := ctx.Value(ctxRendererKey{}).(Renderer)
+
+	return r
+}
+
+// NewContentNegotiationMiddleware returns a middleware that selects,
+// based on the Accept header, the renderer used for the response.
+//
+// The renderers are keyed by content type, e.g. "application/json".
+// The selected renderer is stored in the request context and its content
+// type is set on the response. Requests that accept none of the content
+// types are rejected with 406 Not Acceptable.
+//
+// A content type given q=0 is not served, even if a wildcard matches it.
+func NewContentNegotiationMiddleware(
+	renderers map[string]Renderer,
+) func(http.Handler) http.Handler {
+	contentTypes := make([]string, 0, len(renderers))
+
+	for ct := range renderers {
+		contentTypes = append(contentTypes, ct)
+	}
+
+	// wildcards match the content types in a deterministic order.
+	sort.Strings(contentTypes)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+
+			contentType, ok := negotiate(r.Header.Get("Accept"), contentTypes)
+			if !ok {
+				w.WriteHeader(http.StatusNotAcceptable)
+				return
+			}
+
+			w.Header().Set("Content-Type", contentType)
+
+			next.ServeHTTP(w, r.WithContext(
+				context.WithValue(r.Context(), ctxRendererKey{}, renderers[contentType]),
+			))
+		})
+	}
+}
+
+type acceptedRange struct {
+	mediaRange string
+	q          float64
+}
+
+func negotiate(accept string, contentTypes []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	var ranges, rejected []acceptedRange
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaRange, params, _ := strings.Cut(part, ";")
+
+		ar := acceptedRange{
+			mediaRange: strings.ToLower(strings.TrimSpace(mediaRange)),
+			q:          1,
+		}
+
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || k != "q" {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(v, 64); err == nil {
+				ar.q = q
+			}
+		}
+
+		if ar.q > 0 {
+			ranges = append(ranges, ar)
+		} else {
+			rejected = append(rejected, ar)
+		}
+	}
+
+	// prefer higher quality first, then the more specific ranges.
+	sort.SliceStable(ranges, func(i, j int) bool {
+		if ranges[i].q != ranges[j].q {
+			return ranges[i].q > ranges[j].q
+		}
+
+		return strings.Count(ranges[i].mediaRange, "*") < strings.Count(ranges[j].mediaRange, "*")
+	})
+
+	for _, ar := range ranges {
+		for _, ct := range contentTypes {
+			lowerCT := strings.ToLower(ct)
+
+			if mediaRangeMatches(ar.mediaRange, lowerCT) && !isRejected(lowerCT, ar.mediaRange, rejected) {
+				return ct, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// isRejected reports whether a range given q=0, at least as specific
+// as the matched range, matches the content type, e.g. application/xml
+// for "application/xml;q=0, */*".
+func isRejected(contentType, matched string, rejected []acceptedRange) bool {
+	for _, ar := range rejected {
+		if mediaRangeMatches(ar.mediaRange, contentType) &&
+			strings.Count(ar.mediaRange, "*") <= strings.Count(matched, "*") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func mediaRangeMatches(mediaRange, contentType string) bool {
+	if mediaRange == "*/*" || mediaRange == contentType {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(mediaRange, "/*")
+
+	return ok && strings.HasPrefix(contentType, prefix+"/")
+}
@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+type user struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := middleware.NewContentNegotiationMiddleware(map[string]middleware.Renderer{
+		"application/json": middleware.JSONRenderer(),
+		"application/xml":  middleware.XMLRenderer(),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := middleware.GetRenderer(r.Context()).Render(w, user{Name: "john"}); err != nil {
+			t.Error(err)
+		}
+	}))
+
+	tests := map[string]struct {
+		accept              string
+		expectedCode        int
+		expectedContentType string
+	}{
+		"NoAccept": {
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		"XML": {
+			accept:              "application/xml",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/xml",
+		},
+		"Quality": {
+			accept:              "application/json;q=0.5, application/xml;q=0.9",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/xml",
+		},
+		"Wildcard": {
+			accept:              "text/html, application/*;q=0.8",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		"NotAcceptable": {
+			accept:       "text/html",
+			expectedCode: http.StatusNotAcceptable,
+		},
+		"RejectedFromWildcard": {
+			accept:              "application/json;q=0, */*",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/xml",
+		},
+		"RejectedXMLFromWildcard": {
+			accept:              "application/xml;q=0, */*",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		"AllRejected": {
+			accept:       "application/*;q=0, */*",
+			expectedCode: http.StatusNotAcceptable,
+		},
+		"MoreSpecificAccepted": {
+			accept:              "application/*;q=0, application/xml",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/xml",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.accept != "" {
+				r.Header.Set("Accept", test.accept)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedCode {
+				t.Fatalf("invalid status code, expected: %d, received: %d", test.expectedCode, rr.Code)
+			}
+
+			if ct := rr.Header().Get("Content-Type"); ct != test.expectedContentType {
+				t.Errorf("invalid content type, expected: %s, received: %s", test.expectedContentType, ct)
+			}
+
+			if vary := rr.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("invalid vary, expected: Accept, received: %s", vary)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// ErrInvalidResponse wraps the errors of responses that don't
+// match the OpenAPI spec.
+var ErrInvalidResponse = errors.New("invalid response")
+
+// OpenAPIOption configures the OpenAPI validation middleware.
+type OpenAPIOption func(*openAPIOptions)
+
+type openAPIOptions struct {
+	validateResponse bool
+	errorHandler     func(http.ResponseWriter, *http.Request, error)
+}
+
+// WithResponseValidation enables the validation of the response bodies.
+//
+// The responses are buffered in memory until validated,
+// so prefer to enable this only in development.
+func WithResponseValidation(enabled bool) OpenAPIOption {
+	return func(o *openAPIOptions) {
+		o.validateResponse = enabled
+	}
+}
+
+// WithSchemaErrorHandler sets the handler called when a request, or a
+// response, does not match the spec.
+//
+// By default the violations are sent as a JSON error with the status 400 Bad
+// Request, 404 Not Found or 405 Method Not Allowed for requests and 500
+// Internal Server Error for responses.
+func WithSchemaErrorHandler(f func(w http.ResponseWriter, r *http.Request, err error)) OpenAPIOption {
+	return func(o *openAPIOptions) {
+		o.errorHandler = f
+	}
+}
+
+// NewOpenAPIValidationMiddleware returns a middleware that validates the
+// path parameters, query parameters and bodies of the requests against
+// the OpenAPI 3 spec.
+//
+// It returns an error if the spec can't be loaded or is invalid.
+func NewOpenAPIValidationMiddleware(
+	spec []byte,
+	opts ...OpenAPIOption,
+) (func(http.Handler) http.Handler, error) {
+	o := openAPIOptions{
+		errorHandler: defaultSchemaErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validate spec: %w", err)
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("new router: %w", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				o.errorHandler(w, r, err)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:         true,
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+
+			// the body is restored by the validator.
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				o.errorHandler(w, r, err)
+				return
+			}
+
+			if !o.validateResponse {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			bw := &bufferedWriter{
+				header: make(http.Header),
+				status: http.StatusOK,
+			}
+
+			next.ServeHTTP(bw, r)
+
+			if err := openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 bw.status,
+				Header:                 bw.header,
+				Body:                   io.NopCloser(bytes.NewReader(bw.body.Bytes())),
+				Options:                input.Options,
+			}); err != nil {
+				o.errorHandler(w, r, fmt.Errorf("%w: %w", ErrInvalidResponse, err))
+				return
+			}
+
+			for k, v := range bw.header {
+				w.Header()[k] = v
+			}
+
+			w.WriteHeader(bw.status)
+
+			// nolint: errcheck // the client can't be notified of a failed write.
+			_, _ = w.Write(bw.body.Bytes())
+		})
+	}, nil
+}
+
+type schemaError struct {
+	Code    int      `json:"code"`
+	Message string   `json:"msg"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+func defaultSchemaErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	e := schemaError{
+		Code:    http.StatusBadRequest,
+		Message: "request does not match the api spec",
+	}
+
+	switch {
+	case errors.Is(err, ErrInvalidResponse):
+		// hide the details of invalid responses from the client.
+		e.Code = http.StatusInternalServerError
+		e.Message = http.StatusText(http.StatusInternalServerError)
+
+	case errors.Is(err, routers.ErrPathNotFound):
+		e.Code = http.StatusNotFound
+		e.Message = http.StatusText(http.StatusNotFound)
+
+	case errors.Is(err, routers.ErrMethodNotAllowed):
+		e.Code = http.StatusMethodNotAllowed
+		e.Message = http.StatusText(http.StatusMethodNotAllowed)
+
+	default:
+		var multiErr openapi3.MultiError
+
+		if errors.As(err, &multiErr) {
+			for _, fieldErr := range multiErr {
+				e.Errors = append(e.Errors, fieldErr.Error())
+			}
+		} else {
+			e.Errors = []string{err.Error()}
+		}
+	}
+
+	// nolint: errcheck // the client can't be notified of a failed write.
+	_ = render.SendJSON(w, e.Code, e)
+}
+
+// bufferedWriter holds the response until it's validated.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
@@ -0,0 +1,133 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+const openAPISpec = `
+openapi: 3.0.0
+info:
+  title: users
+  version: 1.0.0
+paths:
+  /users/{id}:
+    post:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: verbose
+          in: query
+          schema:
+            type: boolean
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+`
+
+func TestOpenAPIValidationMiddleware(t *testing.T) {
+	t.Parallel()
+
+	validation, err := middleware.NewOpenAPIValidationMiddleware(
+		[]byte(openAPISpec),
+		middleware.WithResponseValidation(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := validation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Query().Get("verbose") == "true" {
+			_, _ = w.Write([]byte(`{"name":"invalid response"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"id":1}`))
+	}))
+
+	tests := map[string]struct {
+		path         string
+		body         string
+		expectedCode int
+	}{
+		"Valid": {
+			path:         "/users/1",
+			body:         `{"name":"john"}`,
+			expectedCode: http.StatusOK,
+		},
+		"InvalidPathParam": {
+			path:         "/users/john",
+			body:         `{"name":"john"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		"InvalidQueryParam": {
+			path:         "/users/1?verbose=maybe",
+			body:         `{"name":"john"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		"InvalidBody": {
+			path:         "/users/1",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		"UnknownPath": {
+			path:         "/posts",
+			body:         `{}`,
+			expectedCode: http.StatusNotFound,
+		},
+		"InvalidResponse": {
+			path:         "/users/1?verbose=true",
+			body:         `{"name":"john"}`,
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, test.path, strings.NewReader(test.body))
+			r.Header.Set("Content-Type", "application/json")
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if rr.Code != test.expectedCode {
+				t.Errorf(
+					"invalid status code, expected: %d, received: %d, body: %s",
+					test.expectedCode, rr.Code, rr.Body.String(),
+				)
+			}
+		})
+	}
+}
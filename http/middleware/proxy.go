@@ -0,0 +1,179 @@
+// Package middleware provides reusable http middlewares that can be
+// plugged into a chi router or any other http.Handler chain.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// errUpstreamNotFound signals that the upstream responded with 404
+// and the request should be passed to the next handler.
+var errUpstreamNotFound = errors.New("upstream not found")
+
+// ctxProxyInRequestKey holds the incoming request, passed to the
+// next handler instead of the outgoing one on fallthrough.
+type ctxProxyInRequestKey struct{}
+
+// ProxyOption configures the reverse proxy middleware.
+type ProxyOption func(*proxyOptions)
+
+type proxyOptions struct {
+	rewrite               func(*httputil.ProxyRequest)
+	transport             http.RoundTripper
+	errorHandler          func(http.ResponseWriter, *http.Request, error)
+	pathPrefix            string
+	fallthroughOnNotFound bool
+}
+
+// WithRewriteFunc adds a custom rewrite of the outgoing request.
+// It is called after the default rewrites were applied.
+func WithRewriteFunc(f func(*httputil.ProxyRequest)) ProxyOption {
+	return func(o *proxyOptions) {
+		o.rewrite = f
+	}
+}
+
+// WithTransport sets the round tripper used to reach the upstream.
+func WithTransport(t http.RoundTripper) ProxyOption {
+	return func(o *proxyOptions) {
+		o.transport = t
+	}
+}
+
+// WithErrorHandler sets the handler called when the upstream
+// can't be reached.
+func WithErrorHandler(f func(w http.ResponseWriter, r *http.Request, err error)) ProxyOption {
+	return func(o *proxyOptions) {
+		o.errorHandler = f
+	}
+}
+
+// WithPathStrip removes the given prefix from the request path
+// before forwarding it. The prefix is only removed when followed by
+// a "/" or the end of the path, e.g. "/api" is removed from "/api/users"
+// but not from "/apiary".
+func WithPathStrip(prefix string) ProxyOption {
+	return func(o *proxyOptions) {
+		o.pathPrefix = prefix
+	}
+}
+
+// WithFallthrough passes the request to the next handler when
+// the upstream responds with 404 Not Found.
+//
+// The request body is already consumed by the upstream at that
+// point, so this is meant for requests without a body.
+func WithFallthrough() ProxyOption {
+	return func(o *proxyOptions) {
+		o.fallthroughOnNotFound = true
+	}
+}
+
+// NewReverseProxyMiddleware returns a middleware that forwards requests
+// to the upstream URL.
+//
+// The forwarded request carries the X-Forwarded-* headers and the
+// OpenTelemetry trace context of the incoming request.
+func NewReverseProxyMiddleware(
+	upstream *url.URL,
+	opts ...ProxyOption,
+) func(http.Handler) http.Handler {
+	o := proxyOptions{
+		errorHandler: func(w http.ResponseWriter, _ *http.Request, _ error) {
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				// stripped before joining the path of the upstream.
+				if o.pathPrefix != "" {
+					stripPathPrefix(pr.Out.URL, o.pathPrefix)
+				}
+
+				pr.SetURL(upstream)
+				pr.SetXForwarded()
+
+				otel.GetTextMapPropagator().Inject(
+					pr.In.Context(),
+					propagation.HeaderCarrier(pr.Out.Header),
+				)
+
+				if o.rewrite != nil {
+					o.rewrite(pr)
+				}
+			},
+			Transport: o.transport,
+			ModifyResponse: func(resp *http.Response) error {
+				if o.fallthroughOnNotFound && resp.StatusCode == http.StatusNotFound {
+					return errUpstreamNotFound
+				}
+
+				return nil
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				if errors.Is(err, errUpstreamNotFound) {
+					if in, ok := r.Context().Value(ctxProxyInRequestKey{}).(*http.Request); ok {
+						next.ServeHTTP(w, in)
+
+						return
+					}
+				}
+
+				o.errorHandler(w, r, err)
+			},
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxy.ServeHTTP(w, r.WithContext(
+				context.WithValue(r.Context(), ctxProxyInRequestKey{}, r),
+			))
+		})
+	}
+}
+
+// stripPathPrefix removes prefix from the path of u when it is a whole
+// path segment. The escaped path, if any, is dropped when it can't be
+// stripped the same way, falling back to the unescaped one.
+func stripPathPrefix(u *url.URL, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	path, ok := trimPathPrefix(u.Path, prefix)
+	if !ok {
+		return
+	}
+
+	u.Path = path
+
+	if rawPath, ok := trimPathPrefix(u.RawPath, prefix); ok {
+		u.RawPath = rawPath
+	} else {
+		u.RawPath = ""
+	}
+}
+
+func trimPathPrefix(path, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok || (rest != "" && rest[0] != '/') {
+		return path, false
+	}
+
+	if rest == "" {
+		return "/", true
+	}
+
+	return rest, true
+}
@@ -0,0 +1,157 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestReverseProxyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.Header().Set("X-Host", r.Host)
+		w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	handler := middleware.NewReverseProxyMiddleware(
+		upstreamURL,
+		middleware.WithPathStrip("/api"),
+		middleware.WithFallthrough(),
+	)(next)
+
+	t.Run("Forward", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("invalid status code, expected: 200, received: %d", rr.Code)
+		}
+
+		if p := rr.Header().Get("X-Path"); p != "/users" {
+			t.Errorf("invalid upstream path, expected: /users, received: %s", p)
+		}
+
+		if xff := rr.Header().Get("X-Forwarded-For"); xff == "" {
+			t.Error("expected X-Forwarded-For header")
+		}
+	})
+
+	t.Run("Fallthrough", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodGet, "/api/missing", nil)
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, r)
+
+		if rr.Code != http.StatusTeapot {
+			t.Fatalf("invalid status code, expected: 418, received: %d", rr.Code)
+		}
+
+		// the next handler receives the incoming request, not the proxied one.
+		if p := rr.Header().Get("X-Path"); p != "/api/missing" {
+			t.Errorf("invalid path, expected: /api/missing, received: %s", p)
+		}
+
+		if h := rr.Header().Get("X-Host"); h != r.Host {
+			t.Errorf("invalid host, expected: %s, received: %s", r.Host, h)
+		}
+
+		if xff := rr.Header().Get("X-Forwarded-For"); xff != "" {
+			t.Errorf("unexpected X-Forwarded-For header: %s", xff)
+		}
+	})
+}
+
+func TestReverseProxyMiddlewarePathStrip(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Path", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(upstream.Close)
+
+	tests := map[string]struct {
+		upstreamPath string
+		path         string
+		expectedPath string
+	}{
+		"Strip": {
+			path:         "/api/users",
+			expectedPath: "/users",
+		},
+		"UpstreamPath": {
+			upstreamPath: "/base",
+			path:         "/api/users",
+			expectedPath: "/base/users",
+		},
+		"WholePath": {
+			upstreamPath: "/base",
+			path:         "/api",
+			expectedPath: "/base/",
+		},
+		"NotSegment": {
+			path:         "/apiary",
+			expectedPath: "/apiary",
+		},
+		"NotSegmentUpstreamPath": {
+			upstreamPath: "/base",
+			path:         "/apiary",
+			expectedPath: "/base/apiary",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			upstreamURL, err := url.Parse(upstream.URL + test.upstreamPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			handler := middleware.NewReverseProxyMiddleware(
+				upstreamURL,
+				middleware.WithPathStrip("/api"),
+			)(http.NotFoundHandler())
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if p := rr.Header().Get("X-Path"); p != test.expectedPath {
+				t.Errorf("invalid upstream path, expected: %s, received: %s", test.expectedPath, p)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/render"
+	"golang.org/x/time/rate"
+)
+
+// defaultCleanupInterval is the default duration after which the limiters
+// of the inactive keys are pruned.
+const defaultCleanupInterval = 10 * time.Minute
+
+// RateLimitOption configures the ip rate limiter middleware.
+type RateLimitOption func(*rateLimitOptions)
+
+type rateLimitOptions struct {
+	keyExtractor    func(*http.Request) string
+	exceededHandler http.Handler
+	cleanupInterval time.Duration
+}
+
+// WithKeyExtractor sets the function returning the key the requests
+// are limited by, e.g. the X-Forwarded-For header.
+// Defaults to the host of the request remote address.
+func WithKeyExtractor(f func(*http.Request) string) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.keyExtractor = f
+	}
+}
+
+// WithExceededHandler sets the handler called for the requests exceeding
+// the limit. Defaults to a 429 JSON response.
+func WithExceededHandler(h http.Handler) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.exceededHandler = h
+	}
+}
+
+// WithCleanupInterval sets the duration after which the limiter of a key
+// that made no requests is pruned. Defaults to 10 minutes.
+func WithCleanupInterval(d time.Duration) RateLimitOption {
+	return func(o *rateLimitOptions) {
+		o.cleanupInterval = d
+	}
+}
+
+// NewIPRateLimiter returns a middleware that allows, for each remote ip,
+// rps requests per second with bursts of at most burst requests.
+func NewIPRateLimiter(rps float64, burst int, opts ...RateLimitOption) func(http.Handler) http.Handler {
+	o := rateLimitOptions{
+		keyExtractor:    remoteIP,
+		exceededHandler: http.HandlerFunc(writeTooManyRequests),
+		cleanupInterval: defaultCleanupInterval,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l := &ipLimiters{
+		limit:           rate.Limit(rps),
+		burst:           burst,
+		cleanupInterval: o.cleanupInterval,
+	}
+
+	l.lastCleanup.Store(time.Now().UnixNano())
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.allow(o.keyExtractor(r), time.Now()) {
+				o.exceededHandler.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ipLimiters holds the limiters of each key.
+type ipLimiters struct {
+	limit           rate.Limit
+	burst           int
+	cleanupInterval time.Duration
+
+	limiters    sync.Map
+	lastCleanup atomic.Int64
+}
+
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+func (l *ipLimiters) allow(key string, now time.Time) bool {
+	l.cleanup(now)
+
+	v, ok := l.limiters.Load(key)
+	if !ok {
+		v, _ = l.limiters.LoadOrStore(key, &ipLimiter{
+			limiter: rate.NewLimiter(l.limit, l.burst),
+		})
+	}
+
+	limiter, _ := v.(*ipLimiter)
+
+	limiter.lastSeen.Store(now.UnixNano())
+
+	return limiter.limiter.AllowN(now, 1)
+}
+
+// cleanup prunes, at most once per cleanup interval, the limiters
+// not used during the last interval.
+func (l *ipLimiters) cleanup(now time.Time) {
+	last := l.lastCleanup.Load()
+
+	if now.UnixNano()-last < int64(l.cleanupInterval) ||
+		!l.lastCleanup.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	l.limiters.Range(func(key, v any) bool {
+		limiter, _ := v.(*ipLimiter)
+
+		if now.UnixNano()-limiter.lastSeen.Load() >= int64(l.cleanupInterval) {
+			l.limiters.Delete(key)
+		}
+
+		return true
+	})
+}
+
+func writeTooManyRequests(w http.ResponseWriter, _ *http.Request) {
+	_ = render.SendJSON(w, http.StatusTooManyRequests, map[string]string{
+		"error": "too many requests",
+	})
+}
@@ -0,0 +1,93 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestIPRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts     []middleware.RateLimitOption
+		requests []*http.Request
+
+		expectedStatuses []int
+	}{
+		"PerRemoteIP": {
+			requests: []*http.Request{
+				newRemoteRequest("10.0.0.1:1234", ""),
+				newRemoteRequest("10.0.0.1:4321", ""),
+				newRemoteRequest("10.0.0.2:1234", ""),
+			},
+			expectedStatuses: []int{http.StatusNoContent, http.StatusTooManyRequests, http.StatusNoContent},
+		},
+		"KeyExtractor": {
+			opts: []middleware.RateLimitOption{
+				middleware.WithKeyExtractor(func(r *http.Request) string {
+					return r.Header.Get("X-Forwarded-For")
+				}),
+			},
+			requests: []*http.Request{
+				newRemoteRequest("10.0.0.1:1234", "1.1.1.1"),
+				newRemoteRequest("10.0.0.1:1234", "2.2.2.2"),
+				newRemoteRequest("10.0.0.2:1234", "1.1.1.1"),
+			},
+			expectedStatuses: []int{http.StatusNoContent, http.StatusNoContent, http.StatusTooManyRequests},
+		},
+		"ExceededHandler": {
+			opts: []middleware.RateLimitOption{
+				middleware.WithExceededHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				})),
+			},
+			requests: []*http.Request{
+				newRemoteRequest("10.0.0.1:1234", ""),
+				newRemoteRequest("10.0.0.1:1234", ""),
+			},
+			expectedStatuses: []int{http.StatusNoContent, http.StatusServiceUnavailable},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := middleware.NewIPRateLimiter(0.001, 1, test.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+			))
+
+			for i, r := range test.requests {
+				rr := httptest.NewRecorder()
+
+				handler.ServeHTTP(rr, r)
+
+				if rr.Code != test.expectedStatuses[i] {
+					t.Errorf(
+						"invalid status code of request %d, expected: %d, received: %d",
+						i, test.expectedStatuses[i], rr.Code,
+					)
+				}
+			}
+		})
+	}
+}
+
+func newRemoteRequest(remoteAddr, forwardedFor string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	r.RemoteAddr = remoteAddr
+
+	if forwardedFor != "" {
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	return r
+}
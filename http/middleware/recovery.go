@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// PanicHandler handles the panics recovered by the recovery middleware.
+//
+// It has the methods of the grpc PanicHandler, so the same
+// implementation can be used for both the grpc and the http servers.
+type PanicHandler interface {
+	ReportPanic(context.Context, any) error
+	LogPanic(any)
+	LogError(error)
+}
+
+// Panic is the value passed to the PanicHandler, holding the recovered
+// value and the stack trace of the goroutine that panicked.
+type Panic struct {
+	Value any
+	Stack []byte
+}
+
+// String returns the recovered value followed by the stack trace.
+func (p Panic) String() string {
+	return fmt.Sprintf("%v\n\n%s", p.Value, p.Stack)
+}
+
+// NewRecoveryMiddleware returns a middleware that recovers from the
+// panics of the next handlers, including the ones raised while writing
+// the response, logs and reports them with handler and responds with a
+// 500 Internal Server Error.
+//
+// Panics with http.ErrAbortHandler are propagated,
+// aborting the response as intended.
+func NewRecoveryMiddleware(handler PanicHandler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rvr := recover()
+				if rvr == nil {
+					return
+				}
+
+				// nolint: goerr113, errorlint // compared like net/http does.
+				if rvr == http.ErrAbortHandler {
+					panic(rvr)
+				}
+
+				handlePanic(r.Context(), handler, Panic{Value: rvr, Stack: debug.Stack()})
+
+				writeInternalError(w)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// handlePanic logs and reports the panic under a one-second timeout,
+// to avoid increasing the response time.
+func handlePanic(ctx context.Context, handler PanicHandler, p Panic) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+	defer cancel()
+
+	handler.LogPanic(p)
+
+	if err := handler.ReportPanic(ctx, p); err != nil {
+		handler.LogError(fmt.Errorf("error while reporting panic %q: %w", p.Value, err))
+	}
+}
+
+// writeInternalError writes the error response, ignoring the panics of
+// a response writer that is no longer usable.
+func writeInternalError(w http.ResponseWriter) {
+	defer func() {
+		_ = recover()
+	}()
+
+	_ = render.SendJSON(w, http.StatusInternalServerError, map[string]string{
+		"error": "internal error",
+	})
+}
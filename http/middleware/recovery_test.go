@@ -0,0 +1,90 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Handler", func(t *testing.T) {
+		t.Parallel()
+
+		panicHandler := &recordingPanicHandler{}
+
+		handler := middleware.NewRecoveryMiddleware(panicHandler)(http.HandlerFunc(
+			func(http.ResponseWriter, *http.Request) {
+				panic("boom")
+			},
+		))
+
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rr.Code != http.StatusInternalServerError {
+			t.Errorf("invalid status code, expected: 500, received: %d", rr.Code)
+		}
+
+		if body := rr.Body.String(); body != `{"error":"internal error"}` {
+			t.Errorf("invalid body: %s", body)
+		}
+
+		if len(panicHandler.logged) != 1 || panicHandler.reported != 1 {
+			t.Fatalf("panic not handled, logged: %d, reported: %d", len(panicHandler.logged), panicHandler.reported)
+		}
+
+		p, ok := panicHandler.logged[0].(middleware.Panic)
+		if !ok || p.Value != "boom" || len(p.Stack) == 0 {
+			t.Errorf("invalid panic: %+v", panicHandler.logged[0])
+		}
+	})
+
+	t.Run("ResponseWriter", func(t *testing.T) {
+		t.Parallel()
+
+		panicHandler := &recordingPanicHandler{}
+
+		handler := middleware.NewRecoveryMiddleware(panicHandler)(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("hello"))
+			},
+		))
+
+		handler.ServeHTTP(panickingResponseWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if len(panicHandler.logged) != 1 {
+			t.Errorf("panic not handled, logged: %d", len(panicHandler.logged))
+		}
+	})
+}
+
+type recordingPanicHandler struct {
+	logged   []any
+	reported int
+}
+
+func (h *recordingPanicHandler) ReportPanic(context.Context, any) error {
+	h.reported++
+
+	return nil
+}
+
+func (h *recordingPanicHandler) LogPanic(p any) {
+	h.logged = append(h.logged, p)
+}
+
+func (*recordingPanicHandler) LogError(error) {}
+
+type panickingResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (panickingResponseWriter) Write([]byte) (int, error) {
+	panic("broken connection")
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader is the header carrying the request id.
+const RequestIDHeader = "X-Request-Id"
+
+// NewRequestIDPropagator returns a middleware that reads the request id
+// stored in the context under ctxKey, e.g. by the grpc request id
+// interceptor, and sets it in the X-Request-Id header of both the
+// request and the response.
+//
+// Requests without a request id in the context are left untouched.
+func NewRequestIDPropagator(ctxKey any) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requestID, ok := r.Context().Value(ctxKey).(string); ok && requestID != "" {
+				r.Header.Set(RequestIDHeader, requestID)
+				w.Header().Set(RequestIDHeader, requestID)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestIDOption configures the request id middleware.
+type RequestIDOption func(*requestIDOptions)
+
+type requestIDOptions struct {
+	headerName string
+	generator  func() string
+}
+
+// WithHeaderName reads and writes the request id from the given header
+// instead of X-Request-Id.
+func WithHeaderName(name string) RequestIDOption {
+	return func(o *requestIDOptions) {
+		o.headerName = name
+	}
+}
+
+// WithGenerator generates the missing request ids using f
+// instead of random UUIDs.
+func WithGenerator(f func() string) RequestIDOption {
+	return func(o *requestIDOptions) {
+		o.generator = f
+	}
+}
+
+// NewRequestIDMiddleware returns a middleware that reads the request id
+// from the X-Request-Id header, generating a random UUID when it's
+// missing, and stores it in the request context, retrieved using
+// RequestIDFromContext, as well as in the request and response headers.
+//
+// The request id is stored under the chi request id key so it's also
+// understood by the chi middlewares. The request header lets the grpc
+// gateway forward the request id in the x-request-id metadata, where it's
+// found by grpcutils.GetRequestIDFromCtx.
+func NewRequestIDMiddleware(opts ...RequestIDOption) func(http.Handler) http.Handler {
+	o := requestIDOptions{
+		headerName: RequestIDHeader,
+		generator:  uuid.NewString,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(o.headerName)
+			if requestID == "" {
+				requestID = o.generator()
+			}
+
+			r.Header.Set(o.headerName, requestID)
+			w.Header().Set(o.headerName, requestID)
+
+			ctx := context.WithValue(r.Context(), chimiddleware.RequestIDKey, requestID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestIDFromContext returns the request id stored in the context by
+// the request id middleware.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID := chimiddleware.GetReqID(ctx)
+
+	return requestID, requestID != ""
+}
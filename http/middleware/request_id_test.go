@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestRequestIDPropagator(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	var receivedID string
+
+	handler := middleware.NewRequestIDPropagator(ctxKey{})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		receivedID = r.Header.Get(middleware.RequestIDHeader)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = r.WithContext(context.WithValue(r.Context(), ctxKey{}, "req-1"))
+
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, r)
+
+	if receivedID != "req-1" {
+		t.Errorf("invalid request header, expected: req-1, received: %s", receivedID)
+	}
+
+	if id := rr.Header().Get(middleware.RequestIDHeader); id != "req-1" {
+		t.Errorf("invalid response header, expected: req-1, received: %s", id)
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts       []middleware.RequestIDOption
+		headerName string
+		requestID  string
+		expectedID string
+	}{
+		"Generated": {
+			opts:       []middleware.RequestIDOption{middleware.WithGenerator(func() string { return "generated" })},
+			headerName: middleware.RequestIDHeader,
+			expectedID: "generated",
+		},
+		"Propagated": {
+			headerName: middleware.RequestIDHeader,
+			requestID:  "req-1",
+			expectedID: "req-1",
+		},
+		"CustomHeader": {
+			opts:       []middleware.RequestIDOption{middleware.WithHeaderName("X-Correlation-Id")},
+			headerName: "X-Correlation-Id",
+			requestID:  "req-2",
+			expectedID: "req-2",
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var ctxID string
+
+			handler := middleware.NewRequestIDMiddleware(test.opts...)(http.HandlerFunc(
+				func(_ http.ResponseWriter, r *http.Request) {
+					ctxID, _ = middleware.RequestIDFromContext(r.Context())
+				},
+			))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if test.requestID != "" {
+				r.Header.Set(test.headerName, test.requestID)
+			}
+
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, r)
+
+			if ctxID != test.expectedID {
+				t.Errorf("invalid context request id, expected: %s, received: %s", test.expectedID, ctxID)
+			}
+
+			if id := rr.Header().Get(test.headerName); id != test.expectedID {
+				t.Errorf("invalid response header, expected: %s, received: %s", test.expectedID, id)
+			}
+		})
+	}
+
+	t.Run("UUID", func(t *testing.T) {
+		t.Parallel()
+
+		rr := httptest.NewRecorder()
+
+		middleware.NewRequestIDMiddleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})).
+			ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if _, err := uuid.Parse(rr.Header().Get(middleware.RequestIDHeader)); err != nil {
+			t.Errorf("invalid generated request id: %s", err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/purposeinplay/go-commons/http/servertiming"
+)
+
+// NewServerTimingMiddleware returns a middleware that sets the
+// Server-Timing response header from the metrics recorded by the
+// handler using the servertiming package.
+//
+// Metrics recorded after the handler starts writing the response
+// are not included.
+func NewServerTimingMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, timings := servertiming.NewContext(r.Context())
+
+			tw := &timingWriter{
+				ResponseWriter: w,
+				timings:        timings,
+			}
+
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			tw.setHeader()
+		})
+	}
+}
+
+// timingWriter sets the Server-Timing header before the response
+// headers are sent.
+type timingWriter struct {
+	http.ResponseWriter
+
+	timings *servertiming.Timings
+	once    sync.Once
+}
+
+func (w *timingWriter) WriteHeader(code int) {
+	w.setHeader()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	w.setHeader()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *timingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *timingWriter) setHeader() {
+	w.once.Do(func() {
+		if v := w.timings.String(); v != "" {
+			w.Header().Set("Server-Timing", v)
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+	"github.com/purposeinplay/go-commons/http/servertiming"
+)
+
+func TestServerTimingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := middleware.NewServerTimingMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		servertiming.MeasureFrom(r.Context(), "db", time.Now().Add(-50*time.Millisecond))
+		servertiming.Mark(r.Context(), "cache")
+
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	header := rr.Header().Get("Server-Timing")
+
+	if !regexp.MustCompile(`^db;dur=\d+\.\d, cache$`).MatchString(header) {
+		t.Errorf("invalid Server-Timing header: %s", header)
+	}
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/render"
+)
+
+// TimeoutOption configures the timeout middleware.
+type TimeoutOption func(*timeoutOptions)
+
+type timeoutOptions struct {
+	statusCode int
+}
+
+// WithStatusCode sets the status code of the response written when
+// the handler times out. Defaults to 503 Service Unavailable.
+func WithStatusCode(code int) TimeoutOption {
+	return func(o *timeoutOptions) {
+		o.statusCode = code
+	}
+}
+
+// NewTimeoutMiddleware returns a middleware that cancels the context
+// of the requests after d.
+//
+// If the next handler hasn't written the response by then, a JSON error
+// response is written in its place and the writes of the handler fail
+// with http.ErrHandlerTimeout. Otherwise, the response of the handler
+// is left untouched and the middleware waits for it to return.
+//
+// The next handler runs in its own goroutine, its panics are
+// propagated to the goroutine serving the request.
+func NewTimeoutMiddleware(d time.Duration, opts ...TimeoutOption) func(http.Handler) http.Handler {
+	o := timeoutOptions{
+		statusCode: http.StatusServiceUnavailable,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{
+				w:      w,
+				header: make(http.Header),
+			}
+
+			done := make(chan struct{})
+			panicCh := make(chan any, 1)
+
+			go func() {
+				defer func() {
+					if rvr := recover(); rvr != nil {
+						panicCh <- rvr
+					}
+				}()
+
+				next.ServeHTTP(tw, r.WithContext(ctx))
+
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return
+
+			case rvr := <-panicCh:
+				panic(rvr)
+
+			case <-ctx.Done():
+			}
+
+			tw.mu.Lock()
+
+			if !tw.wroteHeader {
+				tw.timedOut = true
+
+				tw.mu.Unlock()
+
+				_ = render.SendJSON(w, o.statusCode, map[string]string{
+					"error": http.StatusText(o.statusCode),
+				})
+
+				return
+			}
+
+			tw.mu.Unlock()
+
+			// the response is already being written by the handler.
+			select {
+			case <-done:
+			case rvr := <-panicCh:
+				panic(rvr)
+			}
+		})
+	}
+}
+
+// timeoutWriter delays the writes of the headers to the underlying writer
+// until WriteHeader is called, so that they don't race with the timeout
+// response, and discards the writes made after the timeout.
+//
+// It doesn't implement Unwrap, reaching the underlying writer
+// would bypass the timeout.
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut || w.wroteHeader {
+		return
+	}
+
+	w.writeHeader(status)
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	if !w.wroteHeader {
+		w.writeHeader(http.StatusOK)
+	}
+
+	return w.w.Write(b)
+}
+
+// Flush flushes the underlying writer, if it supports it.
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+
+	if !w.wroteHeader {
+		w.writeHeader(http.StatusOK)
+	}
+
+	if f, ok := w.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// writeHeader must be called with mu held.
+func (w *timeoutWriter) writeHeader(status int) {
+	dst := w.w.Header()
+
+	for k, v := range w.header {
+		dst[k] = v
+	}
+
+	w.wroteHeader = true
+	w.w.WriteHeader(status)
+}
@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/middleware"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WithinDeadline", func(t *testing.T) {
+		t.Parallel()
+
+		handler := middleware.NewTimeoutMiddleware(time.Second)(http.HandlerFunc(
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Test", "a")
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, "created")
+			},
+		))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d", http.StatusCreated, rec.Code)
+		}
+
+		if got := rec.Header().Get("X-Test"); got != "a" {
+			t.Errorf("expected header %q, got %q", "a", got)
+		}
+
+		if got := rec.Body.String(); got != "created" {
+			t.Errorf("expected body %q, got %q", "created", got)
+		}
+	})
+
+	t.Run("Exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		writeErr := make(chan error, 1)
+
+		handler := middleware.NewTimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+
+				// give the middleware the time to write the timeout response.
+				time.Sleep(10 * time.Millisecond)
+
+				_, err := io.WriteString(w, "late")
+				writeErr <- err
+			},
+		))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rec.Code)
+		}
+
+		expectedBody := `{"error":"Service Unavailable"}`
+
+		if got := rec.Body.String(); got != expectedBody {
+			t.Errorf("expected body %q, got %q", expectedBody, got)
+		}
+
+		if err := <-writeErr; !errors.Is(err, http.ErrHandlerTimeout) {
+			t.Errorf("expected error %v, got %v", http.ErrHandlerTimeout, err)
+		}
+	})
+
+	t.Run("StatusCode", func(t *testing.T) {
+		t.Parallel()
+
+		handler := middleware.NewTimeoutMiddleware(
+			10*time.Millisecond,
+			middleware.WithStatusCode(http.StatusGatewayTimeout),
+		)(http.HandlerFunc(
+			func(_ http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+		))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusGatewayTimeout {
+			t.Errorf("expected status %d, got %d", http.StatusGatewayTimeout, rec.Code)
+		}
+	})
+
+	t.Run("AlreadyWritten", func(t *testing.T) {
+		t.Parallel()
+
+		handler := middleware.NewTimeoutMiddleware(10 * time.Millisecond)(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "partial")
+
+				<-r.Context().Done()
+
+				_, _ = io.WriteString(w, " response")
+			},
+		))
+
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		if got := rec.Body.String(); got != "partial response" {
+			t.Errorf("expected body %q, got %q", "partial response", got)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		t.Parallel()
+
+		handler := middleware.NewTimeoutMiddleware(time.Second)(http.HandlerFunc(
+			func(http.ResponseWriter, *http.Request) {
+				panic("boom")
+			},
+		))
+
+		defer func() {
+			if rvr := recover(); rvr != "boom" {
+				t.Errorf("expected panic %q, got %v", "boom", rvr)
+			}
+		}()
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
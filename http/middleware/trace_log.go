@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/purposeinplay/go-commons/logs"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// GetLogEntryWithTrace returns the request-scoped logger, like
+// logs.GetLogEntry, annotated with the trace_id and span_id of the
+// OpenTelemetry span in the request context, if any, so that the logs
+// can be correlated with the traces.
+//
+// A no-op logger is returned if no logger can be created.
+func GetLogEntryWithTrace(r *http.Request) *zap.Logger {
+	logger, err := logs.GetLogEntry(r)
+	if err != nil {
+		logger = zap.NewNop()
+	}
+
+	spanCtx := trace.SpanFromContext(r.Context()).SpanContext()
+	if !spanCtx.IsValid() {
+		return logger
+	}
+
+	return logger.With(
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+	)
+}
@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cmiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/purposeinplay/go-commons/http/middleware"
+	"github.com/purposeinplay/go-commons/logs"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGetLogEntryWithTrace(t *testing.T) {
+	t.Parallel()
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	tests := map[string]struct {
+		spanCtx trace.SpanContext
+
+		expectedFields map[string]interface{}
+	}{
+		"Span": {
+			spanCtx: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+			}),
+			expectedFields: map[string]interface{}{
+				"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id":  "00f067aa0ba902b7",
+			},
+		},
+		"NoSpan": {
+			expectedFields: map[string]interface{}{},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			core, observed := observer.New(zapcore.InfoLevel)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			r = r.WithContext(trace.ContextWithSpanContext(r.Context(), test.spanCtx))
+			r = cmiddleware.WithLogEntry(r, &logs.StructuredLoggerEntry{Logger: zap.New(core)})
+
+			middleware.GetLogEntryWithTrace(r).Info("test")
+
+			entries := observed.All()
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 entry, got %d", len(entries))
+			}
+
+			fields := entries[0].ContextMap()
+
+			if len(fields) != len(test.expectedFields) {
+				t.Errorf("expected fields %v, got %v", test.expectedFields, fields)
+			}
+
+			for k, v := range test.expectedFields {
+				if fields[k] != v {
+					t.Errorf("expected %s %v, got %v", k, v, fields[k])
+				}
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	cmiddleware "github.com/go-chi/chi/v5/middleware"
 	commonshttp "github.com/purposeinplay/go-commons/http"
+	"github.com/purposeinplay/go-commons/http/middleware"
 	"github.com/purposeinplay/go-commons/logs"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -55,20 +56,120 @@ func Recoverer() Middleware {
 	})
 }
 
-func NewLoggerMiddleware(logger *zap.Logger) func(next http.Handler) http.Handler {
-	return cmiddleware.RequestLogger(&structuredLogger{logger})
+// LoggerOption configures the logger middleware.
+type LoggerOption func(*loggerOptions)
+
+type loggerOptions struct {
+	levelFunc func(status int) zapcore.Level
+	sampler   func(zapcore.Core) zapcore.Core
+	skipPaths map[string]struct{}
+}
+
+// WithLevelFunc sets the function mapping the status of the responses
+// to the level the completed requests are logged at.
+// Defaults to DefaultLevelFunc.
+func WithLevelFunc(f func(status int) zapcore.Level) LoggerOption {
+	return func(o *loggerOptions) {
+		o.levelFunc = f
+	}
+}
+
+// WithSampler samples the logs of the middleware, and the ones written
+// with the request-scoped logger, see zapcore.NewSamplerWithOptions:
+// for each message and level, the first entries logged during tick are
+// kept, then every thereafter-th entry.
+func WithSampler(tick time.Duration, first, thereafter int, opts ...zapcore.SamplerOption) LoggerOption {
+	return func(o *loggerOptions) {
+		o.sampler = func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, tick, first, thereafter, opts...)
+		}
+	}
+}
+
+// WithSkipPaths disables the logging of the requests to the given url
+// paths, e.g. the health checks.
+func WithSkipPaths(paths ...string) LoggerOption {
+	return func(o *loggerOptions) {
+		if o.skipPaths == nil {
+			o.skipPaths = make(map[string]struct{}, len(paths))
+		}
+
+		for _, p := range paths {
+			o.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// DefaultLevelFunc logs the 5xx responses at error level, the 4xx
+// responses at warn level and the others at info level.
+func DefaultLevelFunc(status int) zapcore.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// NewLoggerMiddleware returns a middleware that logs the start and
+// the completion of the requests with logger.
+//
+// The request-scoped entry is stored in the request context,
+// see logs.GetLogEntry.
+func NewLoggerMiddleware(logger *zap.Logger, opts ...LoggerOption) func(next http.Handler) http.Handler {
+	o := loggerOptions{
+		levelFunc: DefaultLevelFunc,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.sampler != nil {
+		logger = logger.WithOptions(zap.WrapCore(o.sampler))
+	}
+
+	l := &structuredLogger{
+		Logger:    logger,
+		levelFunc: o.levelFunc,
+	}
+
+	// like cmiddleware.RequestLogger, except that the context holds the
+	// *logs.StructuredLoggerEntry expected by logs.GetLogEntry.
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := o.skipPaths[r.URL.Path]; ok {
+				next.ServeHTTP(w, cmiddleware.WithLogEntry(r, &logs.StructuredLoggerEntry{Logger: logger}))
+				return
+			}
+
+			entry := l.newLogEntry(r)
+			ww := cmiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			t1 := time.Now()
+
+			defer func() {
+				entry.Write(ww.Status(), ww.BytesWritten(), ww.Header(), time.Since(t1), nil)
+			}()
+
+			next.ServeHTTP(ww, cmiddleware.WithLogEntry(r, entry.StructuredLoggerEntry))
+		})
+	}
 }
 
 type structuredLogger struct {
-	Logger *zap.Logger
+	Logger    *zap.Logger
+	levelFunc func(status int) zapcore.Level
 }
 
-func (l *structuredLogger) NewLogEntry(r *http.Request) cmiddleware.LogEntry {
+func (l *structuredLogger) newLogEntry(r *http.Request) *structuredLoggerEntry {
 	entry := &logs.StructuredLoggerEntry{Logger: l.Logger}
 
 	fields := []zapcore.Field{zap.String("ts", time.Now().UTC().Format(time.RFC1123))}
 
-	if reqID := cmiddleware.GetReqID(r.Context()); reqID != "" {
+	if reqID, ok := middleware.RequestIDFromContext(r.Context()); ok {
 		fields = append(fields, zap.String("req.id", reqID))
 	}
 
@@ -90,5 +191,32 @@ func (l *structuredLogger) NewLogEntry(r *http.Request) cmiddleware.LogEntry {
 
 	entry.Logger.Info("request started")
 
-	return entry
+	return &structuredLoggerEntry{
+		StructuredLoggerEntry: entry,
+		levelFunc:             l.levelFunc,
+	}
+}
+
+// structuredLoggerEntry logs the completed requests at the level
+// of their status.
+type structuredLoggerEntry struct {
+	*logs.StructuredLoggerEntry
+	levelFunc func(status int) zapcore.Level
+}
+
+func (l *structuredLoggerEntry) Write(
+	status, bytes int,
+	_ http.Header,
+	elapsed time.Duration,
+	_ interface{},
+) {
+	l.Logger = l.Logger.With(
+		zap.Int("status", status),
+		zap.Int("bytes_length", bytes),
+		zap.Float64("duration_ms", float64(elapsed.Nanoseconds())/1000000.0),
+	)
+
+	if ce := l.Logger.Check(l.levelFunc(status), "request complete"); ce != nil {
+		ce.Write()
+	}
 }
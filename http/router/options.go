@@ -53,8 +53,8 @@ func WithRealIP() Option {
 	}
 }
 
-func WithLogger(logger *zap.Logger) Option {
+func WithLogger(logger *zap.Logger, opts ...LoggerOption) Option {
 	return func(r *chiRouter) {
-		r.Use(NewLoggerMiddleware(logger))
+		r.Use(NewLoggerMiddleware(logger, opts...))
 	}
 }
@@ -4,10 +4,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5/middleware"
 	commonhttp "github.com/purposeinplay/go-commons/http"
 	"github.com/purposeinplay/go-commons/http/router"
+	commonslogs "github.com/purposeinplay/go-commons/logs"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestHandlerErrorFunc(t *testing.T) {
@@ -52,3 +57,121 @@ func TestHandlerErrorFunc(t *testing.T) {
 		}
 	})
 }
+
+func TestLoggerMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts   []router.LoggerOption
+		status int
+
+		expectedLevel zapcore.Level
+	}{
+		"OK": {
+			status:        http.StatusOK,
+			expectedLevel: zapcore.InfoLevel,
+		},
+		"Redirect": {
+			status:        http.StatusFound,
+			expectedLevel: zapcore.InfoLevel,
+		},
+		"ClientError": {
+			status:        http.StatusNotFound,
+			expectedLevel: zapcore.WarnLevel,
+		},
+		"ServerError": {
+			status:        http.StatusBadGateway,
+			expectedLevel: zapcore.ErrorLevel,
+		},
+		"LevelFunc": {
+			opts: []router.LoggerOption{router.WithLevelFunc(func(int) zapcore.Level {
+				return zapcore.DebugLevel
+			})},
+			status:        http.StatusInternalServerError,
+			expectedLevel: zapcore.DebugLevel,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			handler := router.NewLoggerMiddleware(zap.New(core), test.opts...)(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					// the request-scoped logger is reachable by the handlers.
+					if _, ok := middleware.GetLogEntry(r).(*commonslogs.StructuredLoggerEntry); !ok {
+						t.Error("expected the log entry in the request context")
+					}
+
+					w.WriteHeader(test.status)
+				},
+			))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			entries := logs.FilterMessage("request complete").All()
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 request complete entry, got %d", len(entries))
+			}
+
+			if entries[0].Level != test.expectedLevel {
+				t.Errorf("expected level %s, got %s", test.expectedLevel, entries[0].Level)
+			}
+
+			if got := entries[0].ContextMap()["status"]; got != int64(test.status) {
+				t.Errorf("expected status %d, got %v", test.status, got)
+			}
+		})
+	}
+}
+
+func TestLoggerMiddlewareVolume(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SkipPaths", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		handler := router.NewLoggerMiddleware(
+			zap.New(core),
+			router.WithSkipPaths("/healthz", "/readyz"),
+		)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		for _, path := range []string{"/healthz", "/readyz", "/users"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		entries := logs.FilterMessage("request complete").All()
+		if len(entries) != 1 {
+			t.Fatalf("expected 1 request complete entry, got %d", len(entries))
+		}
+
+		if got := entries[0].ContextMap()["uri"]; got != "http://example.com/users" {
+			t.Errorf("expected uri %q, got %v", "http://example.com/users", got)
+		}
+	})
+
+	t.Run("Sampler", func(t *testing.T) {
+		t.Parallel()
+
+		core, logs := observer.New(zapcore.DebugLevel)
+
+		handler := router.NewLoggerMiddleware(
+			zap.New(core),
+			router.WithSampler(time.Hour, 2, 0),
+		)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+		for n := 0; n < 5; n++ {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		}
+
+		if got := logs.FilterMessage("request complete").Len(); got != 2 {
+			t.Errorf("expected 2 request complete entries, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+// Package servertiming records timing metrics of a request,
+// serialised in the W3C Server-Timing header format.
+//
+// The helpers are no-ops for contexts that were not prepared with
+// NewContext, so they can be called from any nested function.
+package servertiming
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type metric struct {
+	name     string
+	duration time.Duration
+	hasDur   bool
+}
+
+// Timings holds the metrics recorded for a request.
+type Timings struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+type ctxTimingsKey struct{}
+
+// NewContext returns a copy of ctx holding an empty Timings
+// where the metrics are recorded.
+func NewContext(ctx context.Context) (context.Context, *Timings) {
+	t := new(Timings)
+
+	return context.WithValue(ctx, ctxTimingsKey{}, t), t
+}
+
+func fromContext(ctx context.Context) *Timings {
+	t, _ := ctx.Value(ctxTimingsKey{}).(*Timings)
+
+	return t
+}
+
+// Mark records a metric without a duration.
+func Mark(ctx context.Context, name string) {
+	if t := fromContext(ctx); t != nil {
+		t.add(metric{name: name})
+	}
+}
+
+// MeasureFrom records a metric lasting from start until now.
+func MeasureFrom(ctx context.Context, name string, start time.Time) {
+	if t := fromContext(ctx); t != nil {
+		t.add(metric{name: name, duration: time.Since(start), hasDur: true})
+	}
+}
+
+func (t *Timings) add(m metric) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.metrics = append(t.metrics, m)
+}
+
+// String serialises the metrics in the Server-Timing header format,
+// e.g. "db;dur=53.2, cache".
+func (t *Timings) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	values := make([]string, len(t.metrics))
+
+	for i, m := range t.metrics {
+		values[i] = m.name
+
+		if m.hasDur {
+			ms := float64(m.duration) / float64(time.Millisecond)
+			values[i] += ";dur=" + strconv.FormatFloat(ms, 'f', 1, 64)
+		}
+	}
+
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var _ SessionStore = (*cookieStore)(nil)
+
+// cookieStore keeps the whole session in the cookie value,
+// encrypted with AES-GCM and signed with HMAC-SHA256.
+type cookieStore struct {
+	encryptionKey []byte
+	signingKey    []byte
+}
+
+// NewCookieStore returns a SessionStore that keeps the session client side,
+// in the session cookie. The encryption and signing keys are derived from
+// the secret key.
+//
+// The cookie store works only together with the session middleware.
+func NewCookieStore(secretKey []byte) SessionStore {
+	return &cookieStore{
+		encryptionKey: deriveKey(secretKey, "encryption"),
+		signingKey:    deriveKey(secretKey, "signing"),
+	}
+}
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+
+	return mac.Sum(nil)
+}
+
+func (s *cookieStore) Get(_ context.Context, sessionID string) (Session, error) {
+	encoded, signature, ok := strings.Cut(sessionID, ".")
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+
+	expectedSignature, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(s.sign(encoded), expectedSignature) {
+		return Session{}, ErrSessionNotFound
+	}
+
+	ciphertext, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Session{}, ErrSessionNotFound
+	}
+
+	gcm, err := s.gcm()
+	if err != nil {
+		return Session{}, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return Session{}, ErrSessionNotFound
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return Session{}, ErrSessionNotFound
+	}
+
+	var sess Session
+
+	if err := json.Unmarshal(plaintext, &sess); err != nil {
+		return Session{}, fmt.Errorf("unmarshal session: %w", err)
+	}
+
+	return sess, nil
+}
+
+// Save encrypts the session and hands the cookie value to
+// the session middleware.
+func (s *cookieStore) Save(ctx context.Context, sess Session) error {
+	plaintext, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+
+	gcm, err := s.gcm()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("read nonce: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil))
+
+	setCookieValue(ctx, encoded+"."+base64.RawURLEncoding.EncodeToString(s.sign(encoded)))
+
+	return nil
+}
+
+// Delete is a no-op as the session lives in the client cookie,
+// clear its Values instead.
+func (*cookieStore) Delete(context.Context, string) error {
+	return nil
+}
+
+func (s *cookieStore) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(encoded))
+
+	return mac.Sum(nil)
+}
+
+func (s *cookieStore) gcm() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(s.encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("new cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return gcm, nil
+}
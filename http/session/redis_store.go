@@ -0,0 +1,69 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "session:"
+
+var _ SessionStore = (*redisStore)(nil)
+
+type redisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore returns a SessionStore that keeps the sessions in Redis.
+// The sessions expire after ttl without being saved.
+func NewRedisStore(client *redis.Client, ttl time.Duration) SessionStore {
+	return &redisStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (s *redisStore) Get(ctx context.Context, sessionID string) (Session, error) {
+	b, err := s.client.Get(ctx, redisKeyPrefix+sessionID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return Session{}, ErrSessionNotFound
+		}
+
+		return Session{}, fmt.Errorf("get session: %w", err)
+	}
+
+	var sess Session
+
+	if err := json.Unmarshal(b, &sess); err != nil {
+		return Session{}, fmt.Errorf("unmarshal session: %w", err)
+	}
+
+	return sess, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, sess Session) error {
+	b, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+
+	if err := s.client.Set(ctx, redisKeyPrefix+sess.ID, b, s.ttl).Err(); err != nil {
+		return fmt.Errorf("set session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *redisStore) Delete(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, redisKeyPrefix+sessionID).Err(); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	return nil
+}
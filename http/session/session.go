@@ -0,0 +1,277 @@
+// Package session provides an http middleware that loads a session
+// for every request and persists it using a SessionStore.
+package session
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrSessionNotFound is returned by a SessionStore when the session
+// does not exist or has expired.
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session holds the values stored for a client between requests.
+type Session struct {
+	ID     string            `json:"id"`
+	Values map[string]string `json:"values"`
+
+	// SavedAt is when the middleware last saved the session.
+	SavedAt time.Time `json:"saved_at"`
+
+	// shared by the copies of the session handed to the request.
+	state *sessionState
+}
+
+type sessionState struct {
+	dirty bool
+}
+
+// Set stores value under key.
+func (s Session) Set(key, value string) {
+	s.Values[key] = value
+	s.markDirty()
+}
+
+// Delete removes the value stored under key.
+func (s Session) Delete(key string) {
+	delete(s.Values, key)
+	s.markDirty()
+}
+
+// Clear removes all the values.
+func (s Session) Clear() {
+	clear(s.Values)
+	s.markDirty()
+}
+
+func (s Session) markDirty() {
+	if s.state != nil {
+		s.state.dirty = true
+	}
+}
+
+func (s Session) isDirty() bool {
+	return s.state != nil && s.state.dirty
+}
+
+// SessionStore persists sessions.
+type SessionStore interface {
+	Get(ctx context.Context, sessionID string) (Session, error)
+	Save(ctx context.Context, s Session) error
+	Delete(ctx context.Context, sessionID string) error
+}
+
+type ctxSessionKey struct{}
+
+// FromContext returns the session of the request.
+// Changes made with Set, Delete and Clear are persisted by the middleware.
+func FromContext(ctx context.Context) Session {
+	s, _ := ctx.Value(ctxSessionKey{}).(Session)
+
+	return s
+}
+
+// SessionOption configures the session middleware.
+type SessionOption func(*options)
+
+type options struct {
+	cookieName      string
+	maxAge          time.Duration
+	refreshInterval time.Duration
+	secure          bool
+}
+
+// WithCookieName sets the name of the cookie holding the session id,
+// "session" by default.
+func WithCookieName(name string) SessionOption {
+	return func(o *options) {
+		o.cookieName = name
+	}
+}
+
+// WithMaxAge sets the lifetime of the session cookie.
+// By default the cookie expires when the browser is closed.
+func WithMaxAge(d time.Duration) SessionOption {
+	return func(o *options) {
+		o.maxAge = d
+	}
+}
+
+// WithRefreshInterval sets how long after their last save the unchanged
+// sessions are saved again, extending their lifetime in the store and
+// in the cookie. By default they are saved again on every request.
+func WithRefreshInterval(d time.Duration) SessionOption {
+	return func(o *options) {
+		o.refreshInterval = d
+	}
+}
+
+// WithInsecureCookie allows the session cookie to be sent over plain http.
+// ! Prefer to use this only in local development.
+func WithInsecureCookie() SessionOption {
+	return func(o *options) {
+		o.secure = false
+	}
+}
+
+// NewMiddleware returns a middleware that loads the session of the request
+// from the store, or creates a new one, and stores it in the request context.
+//
+// The session is saved right before the response headers are written,
+// so changes made after the handler starts writing the body are lost.
+// A new session is saved, and its cookie set, only once it's changed.
+// An unchanged session loaded from the store is saved again following
+// WithRefreshInterval.
+func NewMiddleware(
+	store SessionStore,
+	opts ...SessionOption,
+) func(http.Handler) http.Handler {
+	o := options{
+		cookieName: "session",
+		secure:     true,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			s, existing, err := loadSession(ctx, store, r, o.cookieName)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			flusher := &sessionWriter{
+				ResponseWriter: w,
+				store:          store,
+				session:        s,
+				existing:       existing,
+				opts:           o,
+			}
+
+			ctx = context.WithValue(ctx, ctxSessionKey{}, s)
+			ctx = context.WithValue(ctx, ctxCookieValueKey{}, &flusher.cookieValue)
+
+			flusher.ctx = ctx
+
+			next.ServeHTTP(flusher, r.WithContext(ctx))
+
+			flusher.flush()
+		})
+	}
+}
+
+func loadSession(
+	ctx context.Context,
+	store SessionStore,
+	r *http.Request,
+	cookieName string,
+) (Session, bool, error) {
+	if cookie, err := r.Cookie(cookieName); err == nil {
+		s, err := store.Get(ctx, cookie.Value)
+
+		switch {
+		case err == nil:
+			if s.Values == nil {
+				s.Values = make(map[string]string)
+			}
+
+			s.state = new(sessionState)
+
+			return s, true, nil
+
+		case !errors.Is(err, ErrSessionNotFound):
+			return Session{}, false, err
+		}
+	}
+
+	return Session{
+		ID:     uuid.NewString(),
+		Values: make(map[string]string),
+		state:  new(sessionState),
+	}, false, nil
+}
+
+// ctxCookieValueKey holds a *string where stores that keep the whole
+// session client side, like the cookie store, put the cookie value.
+type ctxCookieValueKey struct{}
+
+func setCookieValue(ctx context.Context, value string) {
+	if v, ok := ctx.Value(ctxCookieValueKey{}).(*string); ok {
+		*v = value
+	}
+}
+
+// sessionWriter saves the session before the response headers are sent.
+type sessionWriter struct {
+	http.ResponseWriter
+
+	ctx         context.Context
+	store       SessionStore
+	session     Session
+	existing    bool
+	opts        options
+	cookieValue string
+
+	once sync.Once
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	w.flush()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.flush()
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *sessionWriter) flush() {
+	w.once.Do(func() {
+		// the time since the last save is always past the default interval.
+		refresh := w.existing && time.Since(w.session.SavedAt) >= w.opts.refreshInterval
+
+		if !w.session.isDirty() && !refresh {
+			return
+		}
+
+		w.cookieValue = w.session.ID
+		w.session.SavedAt = time.Now()
+
+		if err := w.store.Save(w.ctx, w.session); err != nil {
+			// the request is served without a session cookie rather than failed.
+			return
+		}
+
+		cookie := &http.Cookie{
+			Name:     w.opts.cookieName,
+			Value:    w.cookieValue,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   w.opts.secure,
+			SameSite: http.SameSiteLaxMode,
+		}
+
+		if w.opts.maxAge > 0 {
+			cookie.MaxAge = int(w.opts.maxAge.Seconds())
+		}
+
+		http.SetCookie(w.ResponseWriter, cookie)
+	})
+}
@@ -0,0 +1,107 @@
+package session_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/purposeinplay/go-commons/http/session"
+)
+
+func TestCookieStoreMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := session.NewMiddleware(
+		session.NewCookieStore([]byte("secret")),
+		session.WithInsecureCookie(),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := session.FromContext(r.Context())
+
+		if r.URL.Path == "/login" {
+			s.Set("user", "john")
+		}
+
+		_, _ = w.Write([]byte(s.Values["user"]))
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected one session cookie, received: %d", len(cookies))
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+
+	if body := rr.Body.String(); body != "john" {
+		t.Errorf("invalid session value, expected: john, received: %s", body)
+	}
+
+	tampered := *cookies[0]
+	tampered.Value = "x" + tampered.Value
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&tampered)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+
+	if body := rr.Body.String(); body != "" {
+		t.Errorf("expected a new session for a tampered cookie, received: %s", body)
+	}
+}
+
+func TestMiddlewareUnchangedSession(t *testing.T) {
+	t.Parallel()
+
+	handler := session.NewMiddleware(
+		session.NewCookieStore([]byte("secret")),
+		session.WithInsecureCookie(),
+		session.WithRefreshInterval(time.Hour),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := session.FromContext(r.Context())
+
+		if r.URL.Path == "/login" {
+			s.Set("user", "john")
+		}
+
+		_, _ = w.Write([]byte(s.Values["user"]))
+	}))
+
+	// a new session not changed by the request is not saved.
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if cookies := rr.Result().Cookies(); len(cookies) != 0 {
+		t.Fatalf("expected no session cookie, received: %d", len(cookies))
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/login", nil))
+
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected one session cookie, received: %d", len(cookies))
+	}
+
+	// an existing session saved within the refresh interval is not saved again.
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(cookies[0])
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, r)
+
+	if body := rr.Body.String(); body != "john" {
+		t.Errorf("invalid session value, expected: john, received: %s", body)
+	}
+
+	if cookies := rr.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected no session cookie, received: %d", len(cookies))
+	}
+}
@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net"
@@ -87,6 +88,90 @@ func WithServerTimeouts(
 	}
 }
 
+type readTimeoutOption time.Duration
+
+func (o readTimeoutOption) apply(s *Server) {
+	s.httpServer.ReadTimeout = time.Duration(o)
+}
+
+func (o readTimeoutOption) String() string {
+	return fmt.Sprintf("server.ReadTimeout: %s", time.Duration(o))
+}
+
+// WithReadTimeout sets the maximum duration for reading
+// the entire request, including the body.
+func WithReadTimeout(d time.Duration) Option {
+	return readTimeoutOption(d)
+}
+
+type writeTimeoutOption time.Duration
+
+func (o writeTimeoutOption) apply(s *Server) {
+	s.httpServer.WriteTimeout = time.Duration(o)
+}
+
+func (o writeTimeoutOption) String() string {
+	return fmt.Sprintf("server.WriteTimeout: %s", time.Duration(o))
+}
+
+// WithWriteTimeout sets the maximum duration before
+// timing out writes of the response.
+func WithWriteTimeout(d time.Duration) Option {
+	return writeTimeoutOption(d)
+}
+
+type idleTimeoutOption time.Duration
+
+func (o idleTimeoutOption) apply(s *Server) {
+	s.httpServer.IdleTimeout = time.Duration(o)
+}
+
+func (o idleTimeoutOption) String() string {
+	return fmt.Sprintf("server.IdleTimeout: %s", time.Duration(o))
+}
+
+// WithIdleTimeout sets the maximum amount of time to wait for the
+// next request when keep-alive is enabled.
+func WithIdleTimeout(d time.Duration) Option {
+	return idleTimeoutOption(d)
+}
+
+type shutdownTimeoutOption time.Duration
+
+func (o shutdownTimeoutOption) apply(s *Server) {
+	s.shutdownTimeout = time.Duration(o)
+}
+
+func (o shutdownTimeoutOption) String() string {
+	return fmt.Sprintf("server.ShutdownTimeout: %s", time.Duration(o))
+}
+
+// WithShutdownTimeout sets the timeout of the shutdowns triggered by
+// the shutdown signals, or by Close with a context without deadline.
+// Defaults to 30s.
+func WithShutdownTimeout(d time.Duration) Option {
+	return shutdownTimeoutOption(d)
+}
+
+type tlsConfigOption struct {
+	cfg *tls.Config
+}
+
+func (o tlsConfigOption) apply(s *Server) {
+	s.httpServer.TLSConfig = o.cfg
+}
+
+func (o tlsConfigOption) String() string {
+	return fmt.Sprintf("server.TLSConfig: %t", o.cfg != nil)
+}
+
+// WithTLSConfig serves the connections over TLS using cfg, which must
+// hold the server certificate, either in Certificates or
+// through GetCertificate.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return tlsConfigOption{cfg: cfg}
+}
+
 // nolint: containedctx // allow struct containing ctx as it's an option.
 type baseContextOption struct {
 	ctx                     context.Context
@@ -156,7 +241,7 @@ func (o shutdownSignalsOption) String() string {
 	return fmt.Sprintf("server.ShutdownSignals: %s", signals)
 }
 
-const shutdownTimeout = 30 * time.Second
+const defaultShutdownTimeout = 30 * time.Second
 
 func (o shutdownSignalsOption) apply(server *Server) {
 	if len(o) == 0 {
@@ -180,7 +265,7 @@ func (o shutdownSignalsOption) apply(server *Server) {
 			slog.String("signal", sig.String()),
 		)
 
-		err := server.Shutdown(shutdownTimeout)
+		err := server.Shutdown(server.shutdownTimeout)
 		if err != nil {
 			server.log.Error(
 				"failed to shutdown server in time",
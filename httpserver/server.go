@@ -3,6 +3,7 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
@@ -37,6 +38,10 @@ type Server struct {
 	// holds extra information about the service
 	info Info
 
+	// the timeout of the shutdowns triggered by signals or
+	// by Close without a deadline.
+	shutdownTimeout time.Duration
+
 	// once function to only close the done channel once.
 	closeDoneOnce sync.Once
 }
@@ -70,8 +75,9 @@ func New(logger *slog.Logger, handler http.Handler, options ...Option) *Server {
 			WriteTimeout:      writeTimeout,
 			IdleTimeout:       idleTimeout,
 		},
-		log:  logger,
-		done: make(chan struct{}),
+		log:             logger,
+		done:            make(chan struct{}),
+		shutdownTimeout: defaultShutdownTimeout,
 	}
 
 	for _, o := range options {
@@ -87,6 +93,44 @@ func (s *Server) Shutdown(timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	return s.Close(ctx)
+}
+
+// Start listens on the server address and serves the incoming
+// connections in the background until the server is closed.
+//
+// Unlike ListenAndServe it returns as soon as the server is listening,
+// so failing to listen is reported to the caller.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	s.info.Addr = ln.Addr().String()
+
+	s.log.Info("starting server", slog.String("address", s.info.Addr))
+
+	go func() {
+		if err := s.Serve(ln); err != nil {
+			s.log.Error("serve", slog.String("error", err.Error()))
+		}
+	}()
+
+	return nil
+}
+
+// Close gracefully shuts down the server, waiting for the active
+// connections to finish until ctx is done. If ctx has no deadline,
+// the shutdown timeout is used.
+func (s *Server) Close(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+		defer cancel()
+	}
+
 	defer s.closeDoneOnce.Do(func() {
 		close(s.done)
 	})
@@ -102,7 +146,13 @@ func (s *Server) Shutdown(timeout time.Duration) error {
 // Serve is a wrapper over http.Server.Serve(), and accepts incoming connections
 // on the provided listener.
 func (s *Server) Serve(ln net.Listener) error {
-	err := s.httpServer.Serve(ln)
+	var err error
+
+	if s.httpServer.TLSConfig != nil {
+		err = s.httpServer.ServeTLS(ln, "", "")
+	} else {
+		err = s.httpServer.Serve(ln)
+	}
 
 	err = s.handleShutdown(err)
 	if err != nil {
@@ -118,7 +168,13 @@ func (s *Server) Serve(ln net.Listener) error {
 func (s *Server) ListenAndServe() error {
 	s.log.Info("starting server", slog.String("address", s.httpServer.Addr))
 
-	err := s.httpServer.ListenAndServe()
+	var err error
+
+	if s.httpServer.TLSConfig != nil {
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
 
 	err = s.handleShutdown(err)
 	if err != nil {
@@ -2,10 +2,12 @@ package httpserver_test
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -289,3 +291,39 @@ func sendSignals(signals ...os.Signal) error {
 
 	return nil
 }
+
+func TestServer_StartClose(t *testing.T) {
+	req := require.New(t)
+
+	// borrow the certificate, and the client trusting it, of a test server.
+	tlsServer := httptest.NewTLSServer(nil)
+	t.Cleanup(tlsServer.Close)
+
+	s := httpserver.New(
+		slog.Default(),
+		http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+		httpserver.WithAddress("127.0.0.1:0"),
+		httpserver.WithTLSConfig(&tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: tlsServer.TLS.Certificates,
+		}),
+		httpserver.WithReadTimeout(time.Second),
+		httpserver.WithWriteTimeout(time.Second),
+		httpserver.WithIdleTimeout(time.Second),
+		httpserver.WithShutdownTimeout(time.Second),
+	)
+
+	req.NoError(s.Start())
+
+	resp, err := tlsServer.Client().Get("https://" + s.Info().Addr)
+	req.NoError(err)
+	req.Equal(http.StatusNoContent, resp.StatusCode)
+	req.NoError(resp.Body.Close())
+
+	req.NoError(s.Close(context.Background()))
+
+	_, err = tlsServer.Client().Get("https://" + s.Info().Addr)
+	req.Error(err)
+}
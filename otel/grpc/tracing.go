@@ -0,0 +1,37 @@
+// Package grpc traces the grpc servers with OpenTelemetry.
+package grpc
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+// NewUnaryServerInterceptor returns an interceptor that starts a server
+// span for every unary call, the child of the span context extracted
+// from the incoming metadata by mp.
+func NewUnaryServerInterceptor(
+	tp trace.TracerProvider,
+	mp propagation.TextMapPropagator,
+) grpc.UnaryServerInterceptor {
+	//nolint:staticcheck // the interceptors are kept by otelgrpc until the stats handler is stable.
+	return otelgrpc.UnaryServerInterceptor(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPropagators(mp),
+	)
+}
+
+// NewStreamServerInterceptor returns an interceptor that starts a server
+// span for every stream, the child of the span context extracted
+// from the incoming metadata by mp.
+func NewStreamServerInterceptor(
+	tp trace.TracerProvider,
+	mp propagation.TextMapPropagator,
+) grpc.StreamServerInterceptor {
+	//nolint:staticcheck // the interceptors are kept by otelgrpc until the stats handler is stable.
+	return otelgrpc.StreamServerInterceptor(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPropagators(mp),
+	)
+}
@@ -0,0 +1,59 @@
+package grpc_test
+
+import (
+	"context"
+	"testing"
+
+	otelgrpc "github.com/purposeinplay/go-commons/otel/grpc"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	recorder := tracetest.NewSpanRecorder()
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	interceptor := otelgrpc.NewUnaryServerInterceptor(tp, propagation.TraceContext{})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-"+traceID+"-"+parentSpanID+"-01",
+	))
+
+	var handlerSpan trace.SpanContext
+
+	_, err := interceptor(
+		ctx,
+		nil,
+		&grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"},
+		func(ctx context.Context, _ any) (any, error) {
+			handlerSpan = trace.SpanContextFromContext(ctx)
+
+			return nil, nil
+		},
+	)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	span := spans[0]
+
+	require.Equal(t, "users.Users/Get", span.Name())
+	require.Equal(t, trace.SpanKindServer, span.SpanKind())
+	require.Equal(t, traceID, span.SpanContext().TraceID().String())
+	require.Equal(t, parentSpanID, span.Parent().SpanID().String())
+	require.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+}
@@ -0,0 +1,109 @@
+// Package http traces the http handlers with OpenTelemetry.
+package http
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/purposeinplay/go-commons/otel/http"
+
+// TracingOption configures the tracing middleware.
+type TracingOption func(*tracingOptions)
+
+type tracingOptions struct {
+	spanNameFormatter func(*http.Request) string
+}
+
+// WithSpanNameFormatter sets the function returning the name of the
+// span of a request. Defaults to the request method.
+func WithSpanNameFormatter(f func(*http.Request) string) TracingOption {
+	return func(o *tracingOptions) {
+		o.spanNameFormatter = f
+	}
+}
+
+// NewTracingMiddleware returns a middleware that starts a server span for
+// every request, stored in the request context for the next handlers.
+//
+// The span is the child of the span context extracted from the
+// W3C traceparent and tracestate headers, if any. Once the request is
+// handled, the response status code is recorded on the span, and the span
+// status is set to an error for the 5xx responses.
+func NewTracingMiddleware(tp trace.TracerProvider, opts ...TracingOption) func(http.Handler) http.Handler {
+	o := tracingOptions{
+		spanNameFormatter: func(r *http.Request) string {
+			return r.Method
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tracer := tp.Tracer(tracerName)
+
+	var propagator propagation.TraceContext
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+			ctx, span := tracer.Start(
+				ctx,
+				o.spanNameFormatter(r),
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			sw := &statusWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(sw, r.WithContext(ctx))
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(sw.status))
+
+			if sw.status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(sw.status))
+			}
+		})
+	}
+}
+
+// statusWriter records the status code of the response.
+type statusWriter struct {
+	http.ResponseWriter
+
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,92 @@
+package http_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	otelhttp "github.com/purposeinplay/go-commons/otel/http"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	const (
+		traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentSpanID = "00f067aa0ba902b7"
+	)
+
+	tests := map[string]struct {
+		status int
+
+		expectedStatus codes.Code
+	}{
+		"OK": {
+			status:         http.StatusOK,
+			expectedStatus: codes.Unset,
+		},
+		"ClientError": {
+			status:         http.StatusNotFound,
+			expectedStatus: codes.Unset,
+		},
+		"ServerError": {
+			status:         http.StatusBadGateway,
+			expectedStatus: codes.Error,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := tracetest.NewSpanRecorder()
+
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			var handlerSpan trace.SpanContext
+
+			handler := otelhttp.NewTracingMiddleware(
+				tp,
+				otelhttp.WithSpanNameFormatter(func(r *http.Request) string {
+					return r.Method + " " + r.URL.Path
+				}),
+			)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerSpan = trace.SpanContextFromContext(r.Context())
+
+				w.WriteHeader(test.status)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+			r.Header.Set("traceparent", "00-"+traceID+"-"+parentSpanID+"-01")
+
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+
+			span := spans[0]
+
+			require.Equal(t, "GET /users", span.Name())
+			require.Equal(t, trace.SpanKindServer, span.SpanKind())
+			require.Equal(t, traceID, span.SpanContext().TraceID().String())
+			require.Equal(t, parentSpanID, span.Parent().SpanID().String())
+			require.True(t, span.Parent().IsRemote())
+			require.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+			require.Contains(
+				t,
+				span.Attributes(),
+				attribute.Int("http.response.status_code", test.status),
+			)
+			require.Equal(t, test.expectedStatus, span.Status().Code)
+		})
+	}
+}
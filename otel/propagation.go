@@ -0,0 +1,81 @@
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ErrUnknownPropagator is returned when OTEL_PROPAGATORS
+// contains a propagator name that is not supported.
+var ErrUnknownPropagator = errors.New("unknown propagator")
+
+const (
+	propagatorsEnvKey  = "OTEL_PROPAGATORS"
+	defaultPropagators = "tracecontext,baggage"
+)
+
+// InstallGlobalPropagator sets the global TextMapPropagator to a composite
+// of the W3C TraceContext and Baggage propagators, followed by the
+// given extra propagators.
+func InstallGlobalPropagator(extraPropagators ...propagation.TextMapPropagator) {
+	propagators := append(
+		[]propagation.TextMapPropagator{
+			propagation.TraceContext{}, propagation.Baggage{},
+		},
+		extraPropagators...,
+	)
+
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(propagators...),
+	)
+}
+
+// InstallGlobalPropagatorFromEnv sets the global TextMapPropagator to the
+// propagators named in the OTEL_PROPAGATORS environment variable.
+//
+// The variable holds a comma-separated list of names, "tracecontext,baggage"
+// being used when it is not set. The supported names are "tracecontext",
+// "baggage" and "none", the latter disabling the propagation whatever
+// the other names. All the names are validated before installing any.
+func InstallGlobalPropagatorFromEnv() error {
+	env, ok := os.LookupEnv(propagatorsEnvKey)
+	if !ok || strings.TrimSpace(env) == "" {
+		env = defaultPropagators
+	}
+
+	var (
+		propagators []propagation.TextMapPropagator
+		none        bool
+	)
+
+	for _, name := range strings.Split(env, ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+
+		case "none":
+			none = true
+
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownPropagator, name)
+		}
+	}
+
+	if none {
+		propagators = nil
+	}
+
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(propagators...),
+	)
+
+	return nil
+}
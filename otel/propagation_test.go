@@ -0,0 +1,51 @@
+package otel_test
+
+import (
+	"errors"
+	"testing"
+
+	commonsotel "github.com/purposeinplay/go-commons/otel"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestInstallGlobalPropagatorFromEnv(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "")
+
+		require.NoError(t, commonsotel.InstallGlobalPropagatorFromEnv())
+		require.ElementsMatch(
+			t,
+			[]string{"traceparent", "tracestate", "baggage"},
+			otel.GetTextMapPropagator().Fields(),
+		)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "none")
+
+		require.NoError(t, commonsotel.InstallGlobalPropagatorFromEnv())
+		require.Empty(t, otel.GetTextMapPropagator().Fields())
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "tracecontext,unknown")
+
+		err := commonsotel.InstallGlobalPropagatorFromEnv()
+		require.True(t, errors.Is(err, commonsotel.ErrUnknownPropagator))
+	})
+
+	t.Run("NoneWithUnknown", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "none,unknown")
+
+		err := commonsotel.InstallGlobalPropagatorFromEnv()
+		require.True(t, errors.Is(err, commonsotel.ErrUnknownPropagator))
+	})
+
+	t.Run("NoneWithOthers", func(t *testing.T) {
+		t.Setenv("OTEL_PROPAGATORS", "tracecontext,none")
+
+		require.NoError(t, commonsotel.InstallGlobalPropagatorFromEnv())
+		require.Empty(t, otel.GetTextMapPropagator().Fields())
+	})
+}
@@ -0,0 +1,40 @@
+package pubsub
+
+import (
+	"math"
+	"time"
+)
+
+// BackoffOptions configures the exponential backoff between the
+// attempts of processing an event.
+type BackoffOptions struct {
+	// The delay before the second attempt.
+	InitialInterval time.Duration
+
+	// The maximum delay between two attempts, zero means no limit.
+	MaxInterval time.Duration
+
+	// The factor by which the delay grows after each attempt,
+	// values lower than 1 are treated as 2.
+	Multiplier float64
+
+	// The number of attempts after which the processing is given up.
+	MaxAttempts int
+}
+
+// Next returns the delay to wait after the given failed attempt,
+// counted from 1.
+func (o BackoffOptions) Next(attempt int) time.Duration {
+	multiplier := o.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(o.InitialInterval) * math.Pow(multiplier, float64(max(attempt-1, 0)))
+
+	if o.MaxInterval > 0 && delay > float64(o.MaxInterval) {
+		return o.MaxInterval
+	}
+
+	return time.Duration(delay)
+}
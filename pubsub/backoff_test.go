@@ -0,0 +1,27 @@
+package pubsub_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+func TestBackoffOptions(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	backoff := pubsub.BackoffOptions{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     time.Second,
+		MaxAttempts:     5,
+	}
+
+	i.Equal(100*time.Millisecond, backoff.Next(1))
+	i.Equal(200*time.Millisecond, backoff.Next(2))
+	i.Equal(400*time.Millisecond, backoff.Next(3))
+	i.Equal(800*time.Millisecond, backoff.Next(4))
+	i.Equal(time.Second, backoff.Next(5))
+}
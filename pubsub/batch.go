@@ -0,0 +1,129 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Batch is a group of events settled together.
+type Batch[T, P any] []Event[T, P]
+
+// Ack acknowledges all the events of the batch.
+func (b Batch[T, P]) Ack() {
+	for _, e := range b {
+		e.Ack()
+	}
+}
+
+// Nack negatively acknowledges all the events of the batch,
+// none of them is acked.
+func (b Batch[T, P]) Nack() {
+	for _, e := range b {
+		e.Nack()
+	}
+}
+
+// BatchSubscription delivers the events of a subscription in batches,
+// e.g. to insert them in bulk.
+type BatchSubscription[T, P any] interface {
+	// ReceiveBatch returns the next batch of events.
+	ReceiveBatch(ctx context.Context) (Batch[T, P], error)
+
+	// Close closes the underlying subscription.
+	Close() error
+}
+
+var _ BatchSubscription[string, any] = (*batchSubscription[string, any])(nil)
+
+type batchSubscription[T, P any] struct {
+	sub     Subscription[T, P]
+	size    int
+	timeout time.Duration
+
+	mu sync.Mutex
+
+	// an error event received while collecting a batch,
+	// returned by the next call.
+	pendingErr error
+}
+
+// NewBatchSubscription returns a BatchSubscription that groups the events
+// of sub in batches of at most size events.
+//
+// ReceiveBatch collects the events until the batch is full or timeout
+// elapses, whichever comes first. The batch may be empty if no event is
+// received in time. The events carrying an error are not batched, their
+// error is returned once the events received before are.
+func NewBatchSubscription[T, P any](
+	sub Subscription[T, P],
+	size int,
+	timeout time.Duration,
+) BatchSubscription[T, P] {
+	return &batchSubscription[T, P]{
+		sub:     sub,
+		size:    max(size, 1),
+		timeout: timeout,
+	}
+}
+
+// ReceiveBatch returns the next batch of events.
+//
+// If ctx is done before the batch is complete, the collected events are
+// nacked and ctx's error is returned. ErrSubscriptionClosed is returned
+// once the event stream of the subscription is closed and drained.
+func (s *batchSubscription[T, P]) ReceiveBatch(ctx context.Context) (Batch[T, P], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.pendingErr; err != nil {
+		s.pendingErr = nil
+
+		return nil, err
+	}
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	batch := make(Batch[T, P], 0, s.size)
+
+	for len(batch) < s.size {
+		select {
+		case <-ctx.Done():
+			batch.Nack()
+
+			return nil, ctx.Err()
+
+		case <-timer.C:
+			return batch, nil
+
+		case e, ok := <-s.sub.C():
+			if !ok {
+				if len(batch) == 0 {
+					return nil, ErrSubscriptionClosed
+				}
+
+				return batch, nil
+			}
+
+			if e.Error != nil {
+				if len(batch) == 0 {
+					return nil, e.Error
+				}
+
+				s.pendingErr = e.Error
+
+				return batch, nil
+			}
+
+			batch = append(batch, e)
+		}
+	}
+
+	return batch, nil
+}
+
+// Close closes the underlying subscription.
+func (s *batchSubscription[T, P]) Close() error {
+	return s.sub.Close()
+}
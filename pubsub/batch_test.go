@@ -0,0 +1,113 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestBatchSubscription(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Size", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		batchSub := pubsub.NewBatchSubscription(sub, 3, time.Hour)
+		t.Cleanup(func() { i.NoErr(batchSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		for n := range 5 {
+			i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: n, Acknowledger: ack}, "a"))
+		}
+
+		batch, err := batchSub.ReceiveBatch(context.Background())
+		i.NoErr(err)
+		i.Equal(3, len(batch))
+
+		for n, e := range batch {
+			i.Equal(n, e.Payload)
+		}
+
+		batch.Ack()
+		i.Equal(int32(3), ack.acks.Load())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// the incomplete batch is nacked.
+		_, err = batchSub.ReceiveBatch(ctx)
+		i.True(errors.Is(err, context.DeadlineExceeded))
+		i.Equal(int32(3), ack.acks.Load())
+		i.Equal(int32(2), ack.nacks.Load())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		batchSub := pubsub.NewBatchSubscription(sub, 3, 50*time.Millisecond)
+		t.Cleanup(func() { i.NoErr(batchSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 1, Acknowledger: ack}, "a"))
+
+		batch, err := batchSub.ReceiveBatch(context.Background())
+		i.NoErr(err)
+		i.Equal(1, len(batch))
+
+		batch.Nack()
+		i.Equal(int32(0), ack.acks.Load())
+		i.Equal(int32(1), ack.nacks.Load())
+
+		batch, err = batchSub.ReceiveBatch(context.Background())
+		i.NoErr(err)
+		i.Equal(0, len(batch))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		batchSub := pubsub.NewBatchSubscription(sub, 3, time.Hour)
+		t.Cleanup(func() { i.NoErr(batchSub.Close()) })
+
+		errEvent := errors.New("event")
+
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 1}, "a"))
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Error: errEvent}, "a"))
+
+		// the batch ends before the error event.
+		batch, err := batchSub.ReceiveBatch(context.Background())
+		i.NoErr(err)
+		i.Equal(1, len(batch))
+
+		_, err = batchSub.ReceiveBatch(context.Background())
+		i.Equal(errEvent, err)
+	})
+}
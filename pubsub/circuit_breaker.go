@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreakerPublisher stops publishing the events for a while
+// once the publishing fails repeatedly.
+type CircuitBreakerPublisher[T any] struct {
+	pub       Publisher[T, []byte]
+	threshold int
+	timeout   time.Duration
+
+	mu       sync.Mutex
+	state    circuitState
+	failures []time.Time
+	openedAt time.Time
+}
+
+// NewCircuitBreakerPublisher returns a Publisher that publishes the
+// events with pub until threshold publishing failures happen within
+// timeout.
+//
+// The circuit then opens: the events are rejected with ErrCircuitOpen,
+// without calling pub. After timeout, a single probe event is published
+// with pub, the circuit closes if it succeeds and opens again otherwise.
+func NewCircuitBreakerPublisher[T any](
+	pub Publisher[T, []byte],
+	threshold int,
+	timeout time.Duration,
+) *CircuitBreakerPublisher[T] {
+	return &CircuitBreakerPublisher[T]{
+		pub:       pub,
+		threshold: max(threshold, 1),
+		timeout:   timeout,
+	}
+}
+
+// Publish publishes the event to the specified channels,
+// unless the circuit is open.
+func (p *CircuitBreakerPublisher[T]) Publish(event Event[T, []byte], channels ...string) error {
+	probe, err := p.acquire(time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = p.pub.Publish(event, channels...)
+
+	p.record(probe, err, time.Now())
+
+	return err
+}
+
+// acquire reports whether the event can be published,
+// and whether it's the probe of a half-open circuit.
+func (p *CircuitBreakerPublisher[T]) acquire(now time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch p.state {
+	case circuitOpen:
+		if now.Sub(p.openedAt) < p.timeout {
+			return false, ErrCircuitOpen
+		}
+
+		p.state = circuitHalfOpen
+
+		return true, nil
+
+	case circuitHalfOpen:
+		// the probe is in flight.
+		return false, ErrCircuitOpen
+
+	default:
+		return false, nil
+	}
+}
+
+func (p *CircuitBreakerPublisher[T]) record(probe bool, err error, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if probe {
+		if err == nil {
+			p.state = circuitClosed
+		} else {
+			p.open(now)
+		}
+
+		return
+	}
+
+	// the circuit opened while the event was being published.
+	if err == nil || p.state != circuitClosed {
+		return
+	}
+
+	// keep only the failures of the rolling window.
+	windowStart := now.Add(-p.timeout)
+
+	for len(p.failures) > 0 && !p.failures[0].After(windowStart) {
+		p.failures = p.failures[1:]
+	}
+
+	p.failures = append(p.failures, now)
+
+	if len(p.failures) >= p.threshold {
+		p.open(now)
+	}
+}
+
+func (p *CircuitBreakerPublisher[T]) open(now time.Time) {
+	p.state = circuitOpen
+	p.openedAt = now
+	p.failures = nil
+}
@@ -0,0 +1,91 @@
+package pubsub_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+var errPublish = errors.New("publish")
+
+var _ pubsub.Publisher[string, []byte] = (*mockPublisher)(nil)
+
+// mockPublisher fails the first failures calls.
+type mockPublisher struct {
+	failures atomic.Int32
+	calls    atomic.Int32
+}
+
+func (p *mockPublisher) Publish(pubsub.Event[string, []byte], ...string) error {
+	if p.calls.Add(1) <= p.failures.Load() {
+		return errPublish
+	}
+
+	return nil
+}
+
+func TestCircuitBreakerPublisher(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	mock := new(mockPublisher)
+	mock.failures.Store(3)
+
+	const timeout = 50 * time.Millisecond
+
+	pub := pubsub.NewCircuitBreakerPublisher[string](mock, 2, timeout)
+
+	publish := func() error {
+		return pub.Publish(pubsub.Event[string, []byte]{Type: "test"}, "a")
+	}
+
+	// the circuit opens after the second failure.
+	i.True(errors.Is(publish(), errPublish))
+	i.True(errors.Is(publish(), errPublish))
+	i.True(errors.Is(publish(), pubsub.ErrCircuitOpen))
+	i.Equal(int32(2), mock.calls.Load())
+
+	// the failed probe opens the circuit again.
+	time.Sleep(timeout)
+
+	i.True(errors.Is(publish(), errPublish))
+	i.True(errors.Is(publish(), pubsub.ErrCircuitOpen))
+	i.Equal(int32(3), mock.calls.Load())
+
+	// the successful probe closes the circuit.
+	time.Sleep(timeout)
+
+	i.NoErr(publish())
+	i.NoErr(publish())
+	i.Equal(int32(5), mock.calls.Load())
+}
+
+func TestCircuitBreakerPublisherRollingWindow(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	mock := new(mockPublisher)
+	mock.failures.Store(2)
+
+	const timeout = 50 * time.Millisecond
+
+	pub := pubsub.NewCircuitBreakerPublisher[string](mock, 2, timeout)
+
+	publish := func() error {
+		return pub.Publish(pubsub.Event[string, []byte]{Type: "test"}, "a")
+	}
+
+	// the failures are farther apart than the window.
+	i.True(errors.Is(publish(), errPublish))
+
+	time.Sleep(timeout)
+
+	i.True(errors.Is(publish(), errPublish))
+	i.NoErr(publish())
+}
@@ -0,0 +1,58 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// NewJSONSubscription returns a Subscription that decodes the JSON
+// payload of every event received from sub into a P.
+//
+// Events that fail to decode are nacked and replaced by an event carrying
+// the error, as described by NewTransformSubscription.
+func NewJSONSubscription[T, P any](sub Subscription[T, []byte]) Subscription[T, P] {
+	return NewTransformSubscription(sub, JSONTransform[P]())
+}
+
+// JSONTransform returns a transform that decodes JSON payloads into a P,
+// e.g. to migrate the events of sub to a new schema:
+//
+//	NewTransformSubscription(sub, JSONTransform[OrderV2]())
+func JSONTransform[P any]() func(context.Context, []byte) (P, error) {
+	return func(_ context.Context, payload []byte) (P, error) {
+		var v P
+
+		if err := json.Unmarshal(payload, &v); err != nil {
+			return v, fmt.Errorf("unmarshal json: %w", err)
+		}
+
+		return v, nil
+	}
+}
+
+// NewProtobufSubscription returns a Subscription that decodes the protobuf
+// payload of every event received from sub into a new P.
+//
+// Events that fail to decode are nacked and replaced by an event carrying
+// the error, as described by NewTransformSubscription.
+func NewProtobufSubscription[T any, P proto.Message](sub Subscription[T, []byte]) Subscription[T, P] {
+	return NewTransformSubscription(
+		sub,
+		func(_ context.Context, payload []byte) (P, error) {
+			var zero P
+
+			// generated messages build their descriptor from a nil pointer.
+			// nolint: forcetypeassert // New returns a message of the same type.
+			v := zero.ProtoReflect().New().Interface().(P)
+
+			if err := proto.Unmarshal(payload, v); err != nil {
+				return zero, fmt.Errorf("unmarshal proto: %w", err)
+			}
+
+			return v, nil
+		},
+	)
+}
@@ -0,0 +1,65 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestJSONSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	ps := inmem.NewPubSub[string, []byte](2)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	jsonSub := pubsub.NewJSONSubscription[string, user](sub)
+	t.Cleanup(func() { i.NoErr(jsonSub.Close()) })
+
+	ack := new(testAcknowledger)
+
+	i.NoErr(ps.Publish(pubsub.Event[string, []byte]{Payload: []byte(`{"name":"john"}`)}, "a"))
+	i.NoErr(ps.Publish(pubsub.Event[string, []byte]{Payload: []byte(`{`), Acknowledger: ack}, "a"))
+
+	e := <-jsonSub.C()
+	i.NoErr(e.Error)
+	i.Equal("john", e.Payload.Name)
+
+	e = <-jsonSub.C()
+	i.True(e.Error != nil)
+	i.Equal(int32(1), ack.nacks.Load())
+}
+
+func TestProtobufSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	ps := inmem.NewPubSub[string, []byte](1)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	protoSub := pubsub.NewProtobufSubscription[string, *wrapperspb.StringValue](sub)
+	t.Cleanup(func() { i.NoErr(protoSub.Close()) })
+
+	payload, err := proto.Marshal(wrapperspb.String("john"))
+	i.NoErr(err)
+
+	i.NoErr(ps.Publish(pubsub.Event[string, []byte]{Payload: payload}, "a"))
+
+	e := <-protoSub.C()
+	i.NoErr(e.Error)
+	i.Equal("john", e.Payload.GetValue())
+}
@@ -0,0 +1,180 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HeaderRetryCount is the event header holding the number of times
+// the event was nacked before the current delivery.
+const HeaderRetryCount = "x-retry-count"
+
+// DeadLetter is the JSON envelope published to the dead letter queue
+// in place of an event that failed to be processed.
+type DeadLetter struct {
+	// The payload of the failed event.
+	Payload []byte `json:"payload"`
+
+	// The headers of the failed event.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// The number of times the event was nacked.
+	RetryCount int `json:"retry_count"`
+
+	// The time the event was moved to the dead letter queue.
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// RetryCount returns the number of times the event was nacked
+// before its current delivery, as set in the HeaderRetryCount header.
+func RetryCount[T, P any](e Event[T, P]) int {
+	count, _ := strconv.Atoi(e.Headers[HeaderRetryCount])
+
+	return count
+}
+
+// NewDLQSubscription returns a Subscription that redelivers the events of
+// sub nacked by the consumer, incrementing their HeaderRetryCount header,
+// until they are nacked maxRetries times. The event is then published to
+// dlqTopic with dlqPublisher, wrapped in a DeadLetter, and acked on sub
+// so the consumer is not stuck on it.
+//
+// The events are redelivered before receiving the next event from sub,
+// preserving their order, so every event must be either acked or nacked
+// before the next one is delivered.
+func NewDLQSubscription[T any](
+	sub Subscription[T, []byte],
+	dlqPublisher Publisher[T, []byte],
+	dlqTopic string,
+	maxRetries int,
+) Subscription[T, []byte] {
+	return newForwardSubscription(
+		sub,
+		func(ctx context.Context, e Event[T, []byte], send func(Event[T, []byte]) bool) {
+			if e.Error != nil {
+				send(e)
+
+				return
+			}
+
+			for retryCount := RetryCount(e); ; retryCount++ {
+				if retryCount >= maxRetries {
+					if err := publishDeadLetter(e, retryCount, dlqPublisher, dlqTopic); err != nil {
+						e.Nack()
+
+						send(Event[T, []byte]{
+							Type:  e.Type,
+							Error: err,
+						})
+
+						return
+					}
+
+					e.Ack()
+
+					return
+				}
+
+				headers := make(map[string]string, len(e.Headers)+1)
+
+				maps.Copy(headers, e.Headers)
+
+				headers[HeaderRetryCount] = strconv.Itoa(retryCount)
+
+				ack := newSettlement()
+
+				if !send(Event[T, []byte]{
+					Type:         e.Type,
+					Payload:      e.Payload,
+					Headers:      headers,
+					ID:           e.ID,
+					ReceivedAt:   e.ReceivedAt,
+					Acknowledger: ack,
+				}) {
+					e.Nack()
+
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					e.Nack()
+
+					return
+
+				case acked := <-ack.result:
+					if acked {
+						e.Ack()
+
+						return
+					}
+				}
+			}
+		},
+	)
+}
+
+func publishDeadLetter[T any](
+	e Event[T, []byte],
+	retryCount int,
+	pub Publisher[T, []byte],
+	topic string,
+) error {
+	payload, err := json.Marshal(DeadLetter{
+		Payload:    e.Payload,
+		Headers:    e.Headers,
+		RetryCount: retryCount,
+		FailedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal dead letter: %w", err)
+	}
+
+	if err := pub.Publish(Event[T, []byte]{
+		Type:    e.Type,
+		Payload: payload,
+		Headers: e.Headers,
+	}, topic); err != nil {
+		return fmt.Errorf("publish dead letter: %w", err)
+	}
+
+	return nil
+}
+
+var _ Acknowledger = (*settlement)(nil)
+
+// settlement reports the first ack or nack of a delivery.
+type settlement struct {
+	once   sync.Once
+	result chan bool
+}
+
+func newSettlement() *settlement {
+	return &settlement{result: make(chan bool, 1)}
+}
+
+// Ack reports false if the delivery was already settled.
+func (s *settlement) Ack() bool {
+	return s.settle(true)
+}
+
+// Nack reports false if the delivery was already settled.
+func (s *settlement) Nack() bool {
+	return s.settle(false)
+}
+
+func (s *settlement) settle(acked bool) bool {
+	settled := false
+
+	s.once.Do(func() {
+		s.result <- acked
+		settled = true
+	})
+
+	return settled
+}
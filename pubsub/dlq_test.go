@@ -0,0 +1,104 @@
+package pubsub_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestDLQSubscription(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DeadLetter", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](1)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		dlqSub, err := ps.Subscribe("dlq")
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(dlqSub.Close()) })
+
+		retryingSub := pubsub.NewDLQSubscription[string](sub, ps, "dlq", 3)
+		t.Cleanup(func() { i.NoErr(retryingSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		err = ps.Publish(pubsub.Event[string, []byte]{
+			Type:         "test",
+			Payload:      []byte("test"),
+			Headers:      map[string]string{"trace": "1"},
+			Acknowledger: ack,
+		}, "a")
+		i.NoErr(err)
+
+		for retry := 0; retry < 3; retry++ {
+			e := <-retryingSub.C()
+			i.Equal("test", string(e.Payload))
+			i.Equal(retry, pubsub.RetryCount(e))
+			i.True(e.Nack())
+		}
+
+		e := <-dlqSub.C()
+		i.Equal("test", e.Type)
+
+		var deadLetter pubsub.DeadLetter
+
+		i.NoErr(json.Unmarshal(e.Payload, &deadLetter))
+		i.Equal("test", string(deadLetter.Payload))
+		i.Equal("1", deadLetter.Headers["trace"])
+		i.Equal(3, deadLetter.RetryCount)
+
+		i.Equal(int32(1), ack.acks.Load())
+		i.Equal(int32(0), ack.nacks.Load())
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](2)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		retryingSub := pubsub.NewDLQSubscription[string](sub, ps, "dlq", 3)
+		t.Cleanup(func() { i.NoErr(retryingSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		for _, payload := range []string{"first", "second"} {
+			err = ps.Publish(pubsub.Event[string, []byte]{
+				Type:         "test",
+				Payload:      []byte(payload),
+				Acknowledger: ack,
+			}, "a")
+			i.NoErr(err)
+		}
+
+		e := <-retryingSub.C()
+		i.Equal("first", string(e.Payload))
+		i.True(e.Nack())
+		i.True(!e.Ack())
+
+		// the nacked event is redelivered before the next one.
+		e = <-retryingSub.C()
+		i.Equal("first", string(e.Payload))
+		i.Equal(1, pubsub.RetryCount(e))
+		i.True(e.Ack())
+
+		e = <-retryingSub.C()
+		i.Equal("second", string(e.Payload))
+		i.Equal(0, pubsub.RetryCount(e))
+		i.True(e.Ack())
+	})
+}
@@ -6,3 +6,23 @@ import (
 
 // ErrExactlyOneChannelAllowed is returned a pubsub implementation supports only one channel.
 var ErrExactlyOneChannelAllowed = errors.New("exactly one channel allowed")
+
+// ErrAtLeastOneChannelRequired is returned when a pubsub implementation
+// is called without any channel.
+var ErrAtLeastOneChannelRequired = errors.New("at least one channel required")
+
+// ErrScheduledMessageNotFound is returned when a scheduled message
+// does not exist or was already sent.
+var ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+// ErrInvalidPollInterval is returned when a Scheduler is created
+// with a poll interval that is not positive.
+var ErrInvalidPollInterval = errors.New("invalid poll interval")
+
+// ErrCircuitOpen is returned by the circuit breaker publisher while
+// the publishing is suspended after too many failures.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// ErrSubscriptionClosed is returned when receiving from a subscription
+// whose event stream is closed.
+var ErrSubscriptionClosed = errors.New("subscription closed")
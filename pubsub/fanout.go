@@ -0,0 +1,277 @@
+package pubsub
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// defaultFanOutBufferSize is the default capacity of the event stream
+// of each fan-out subscription.
+const defaultFanOutBufferSize = 16
+
+var _ Subscription[string, any] = (*fanOutSubscription[string, any])(nil)
+
+// FanOutOption configures the fan-out subscriptions.
+type FanOutOption func(*fanOutOptions)
+
+type fanOutOptions struct {
+	bufferSize int
+	onDrop     func(consumer int, dropped int64)
+}
+
+// WithFanOutBufferSize sets the capacity of the event stream of each
+// fan-out subscription. Defaults to 16.
+func WithFanOutBufferSize(n int) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.bufferSize = n
+	}
+}
+
+// WithOnDrop sets the callback called with the index of the consumer
+// and the number of events dropped for it so far, whenever an event is
+// dropped because the consumer's event stream is full.
+func WithOnDrop(f func(consumer int, dropped int64)) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.onDrop = f
+	}
+}
+
+// NewFanOutSubscription returns n subscriptions that all deliver a copy
+// of every event of sub.
+//
+// The events are not waited for: an event is dropped for the consumers
+// whose event stream is full. An event of sub is acked once all the
+// consumers it was delivered to settled it, or nacked if one of them
+// nacked it or if it couldn't be delivered to any consumer.
+//
+// Closing a subscription stops the delivery to its consumer only,
+// sub is closed once all the subscriptions are closed.
+func NewFanOutSubscription[T, P any](
+	sub Subscription[T, P],
+	n int,
+	opts ...FanOutOption,
+) []Subscription[T, P] {
+	o := fanOutOptions{
+		bufferSize: defaultFanOutBufferSize,
+		onDrop:     func(int, int64) {},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := &fanOutMultiplexer[T, P]{
+		inner: sub,
+		opts:  &o,
+		subs:  make([]*fanOutSubscription[T, P], n),
+	}
+
+	m.open.Store(int32(n))
+
+	subs := make([]Subscription[T, P], n)
+
+	for i := range m.subs {
+		m.subs[i] = &fanOutSubscription[T, P]{
+			m:       m,
+			eventCh: make(chan Event[T, P], o.bufferSize),
+		}
+
+		subs[i] = m.subs[i]
+	}
+
+	go m.run()
+
+	return subs
+}
+
+// fanOutMultiplexer copies the events of the inner subscription
+// to the fan-out subscriptions.
+type fanOutMultiplexer[T, P any] struct {
+	inner Subscription[T, P]
+	opts  *fanOutOptions
+	subs  []*fanOutSubscription[T, P]
+
+	// the number of subscriptions not closed yet.
+	open atomic.Int32
+}
+
+func (m *fanOutMultiplexer[T, P]) run() {
+	defer func() {
+		for _, s := range m.subs {
+			s.closeEventCh()
+		}
+	}()
+
+	for e := range m.inner.C() {
+		if e.Error != nil || e.Acknowledger == nil {
+			for i, s := range m.subs {
+				m.send(i, s, e)
+			}
+
+			continue
+		}
+
+		// the delivery is held open until all the copies are sent.
+		d := &fanOutDelivery[T, P]{event: e, pending: 1}
+
+		var delivered bool
+
+		for i, s := range m.subs {
+			d.hold()
+
+			c := e
+			c.Acknowledger = &fanOutAcknowledger[T, P]{delivery: d}
+
+			if m.send(i, s, c) {
+				delivered = true
+			} else {
+				d.settle(false)
+			}
+		}
+
+		if !delivered {
+			e.Nack()
+
+			continue
+		}
+
+		d.settle(false)
+	}
+}
+
+// send delivers the event to the subscription, unless its event stream
+// is full or it is closed.
+func (m *fanOutMultiplexer[T, P]) send(i int, s *fanOutSubscription[T, P], e Event[T, P]) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	select {
+	case s.eventCh <- e:
+		return true
+
+	default:
+		m.opts.onDrop(i, s.dropped.Add(1))
+
+		return false
+	}
+}
+
+// fanOutDelivery settles an event of the inner subscription
+// once all its copies are settled.
+type fanOutDelivery[T, P any] struct {
+	event Event[T, P]
+
+	mu      sync.Mutex
+	pending int
+	nacked  bool
+}
+
+func (d *fanOutDelivery[T, P]) hold() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.pending++
+}
+
+func (d *fanOutDelivery[T, P]) settle(nack bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.nacked = d.nacked || nack
+
+	if d.pending--; d.pending > 0 {
+		return
+	}
+
+	if d.nacked {
+		d.event.Nack()
+	} else {
+		d.event.Ack()
+	}
+}
+
+// fanOutAcknowledger settles a copy of an event.
+type fanOutAcknowledger[T, P any] struct {
+	delivery *fanOutDelivery[T, P]
+	once     sync.Once
+}
+
+func (a *fanOutAcknowledger[T, P]) Ack() bool {
+	return a.settle(false)
+}
+
+func (a *fanOutAcknowledger[T, P]) Nack() bool {
+	return a.settle(true)
+}
+
+func (a *fanOutAcknowledger[T, P]) settle(nack bool) bool {
+	var settled bool
+
+	a.once.Do(func() {
+		a.delivery.settle(nack)
+
+		settled = true
+	})
+
+	return settled
+}
+
+// fanOutSubscription is the subscription of a single consumer.
+type fanOutSubscription[T, P any] struct {
+	m *fanOutMultiplexer[T, P]
+
+	mu      sync.Mutex
+	eventCh chan Event[T, P]
+	closed  bool
+	dropped atomic.Int64
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// C returns a receive-only go channel of the events delivered
+// to the consumer.
+func (s *fanOutSubscription[T, P]) C() <-chan Event[T, P] {
+	return s.eventCh
+}
+
+// Close stops the delivery of the events to the consumer,
+// and closes the inner subscription if it's the last one open.
+// Safe to call multiple times.
+func (s *fanOutSubscription[T, P]) Close() error {
+	s.closeOnce.Do(func() {
+		s.closeEventCh()
+
+		// the buffered events won't be read, they don't hold their
+		// delivery open anymore.
+		for e := range s.eventCh {
+			if a, ok := e.Acknowledger.(*fanOutAcknowledger[T, P]); ok {
+				a.settle(false)
+			}
+		}
+
+		if s.m.open.Add(-1) == 0 {
+			s.closeErr = s.m.inner.Close()
+		}
+	})
+
+	return s.closeErr
+}
+
+// closeEventCh closes the event stream, unless it's already closed.
+func (s *fanOutSubscription[T, P]) closeEventCh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+
+	close(s.eventCh)
+}
@@ -0,0 +1,141 @@
+package pubsub_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestFanOutSubscription(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Delivered", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		subs := pubsub.NewFanOutSubscription(sub, 2)
+
+		ack := new(testAcknowledger)
+
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 1, Acknowledger: ack}, "a"))
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 2, Acknowledger: ack}, "a"))
+
+		for _, s := range subs {
+			e := <-s.C()
+			i.Equal(1, e.Payload)
+			i.True(e.Ack())
+			i.True(!e.Ack())
+		}
+
+		// acked once all the consumers acked.
+		i.Equal(int32(1), ack.acks.Load())
+
+		e := <-subs[0].C()
+		i.Equal(2, e.Payload)
+		i.True(e.Nack())
+
+		e = <-subs[1].C()
+		i.True(e.Ack())
+
+		// nacked as soon as one of the consumers nacked.
+		i.Equal(int32(1), ack.acks.Load())
+		i.Equal(int32(1), ack.nacks.Load())
+
+		for _, s := range subs {
+			i.NoErr(s.Close())
+		}
+	})
+
+	t.Run("Dropped", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		var dropped atomic.Int64
+
+		subs := pubsub.NewFanOutSubscription(
+			sub,
+			2,
+			pubsub.WithFanOutBufferSize(1),
+			pubsub.WithOnDrop(func(consumer int, n int64) {
+				i.Equal(1, consumer)
+				dropped.Store(n)
+			}),
+		)
+
+		ack := new(testAcknowledger)
+
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 1, Acknowledger: ack}, "a"))
+
+		e := <-subs[0].C()
+		i.True(e.Ack())
+
+		// the second consumer doesn't read its first event.
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 2, Acknowledger: ack}, "a"))
+
+		e = <-subs[0].C()
+		i.Equal(2, e.Payload)
+		i.True(e.Ack())
+
+		i.Equal(int64(1), dropped.Load())
+
+		// the second event was delivered to the first consumer only.
+		i.Equal(int32(1), ack.acks.Load())
+
+		// the buffered event is released on close.
+		i.NoErr(subs[1].Close())
+		i.Equal(int32(2), ack.acks.Load())
+
+		i.NoErr(subs[0].Close())
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, int](10)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		subs := pubsub.NewFanOutSubscription(sub, 2)
+
+		i.NoErr(subs[0].Close())
+		i.NoErr(subs[0].Close())
+
+		_, ok := <-subs[0].C()
+		i.True(!ok)
+
+		// the other consumer still receives the events.
+		i.NoErr(ps.Publish(pubsub.Event[string, int]{Payload: 1}, "a"))
+
+		e := <-subs[1].C()
+		i.Equal(1, e.Payload)
+
+		// closing the last subscription closes the inner one.
+		i.NoErr(subs[1].Close())
+
+		select {
+		case _, ok := <-sub.C():
+			i.True(!ok)
+		case <-time.After(time.Second):
+			t.Fatal("inner subscription not closed")
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package pubsub
+
+import (
+	"context"
+)
+
+// NewFilterSubscription returns a Subscription that delivers only the
+// events of sub for which predicate returns true.
+//
+// The dropped events are acked, so they are not redelivered.
+// Events carrying an error are always delivered.
+func NewFilterSubscription[T, P any](
+	sub Subscription[T, P],
+	predicate func(Event[T, P]) bool,
+) Subscription[T, P] {
+	return newForwardSubscription(
+		sub,
+		func(_ context.Context, e Event[T, P], send func(Event[T, P]) bool) {
+			if e.Error == nil && !predicate(e) {
+				e.Ack()
+
+				return
+			}
+
+			send(e)
+		},
+	)
+}
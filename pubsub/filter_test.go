@@ -0,0 +1,61 @@
+package pubsub_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestFilterSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	ps := inmem.NewPubSub[string, int](10)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	filterSub := pubsub.NewFilterSubscription(sub, func(e pubsub.Event[string, int]) bool {
+		return e.Payload%2 == 0
+	})
+	t.Cleanup(func() { i.NoErr(filterSub.Close()) })
+
+	filteredAck, deliveredAck := new(testAcknowledger), new(testAcknowledger)
+
+	for n := range 10 {
+		ack := deliveredAck
+		if n%2 != 0 {
+			ack = filteredAck
+		}
+
+		err := ps.Publish(pubsub.Event[string, int]{
+			Type:         strconv.Itoa(n),
+			Payload:      n,
+			Acknowledger: ack,
+		}, "a")
+		i.NoErr(err)
+	}
+
+	for n := 0; n < 10; n += 2 {
+		e := <-filterSub.C()
+		i.Equal(n, e.Payload)
+
+		// the delivered events are left to the consumer to settle.
+		i.Equal(int32(0), deliveredAck.acks.Load()+deliveredAck.nacks.Load())
+	}
+
+	select {
+	case e := <-filterSub.C():
+		t.Fatalf("unexpected event: %d", e.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the odd events are acked as they are dropped.
+	i.Equal(int32(5), filteredAck.acks.Load())
+	i.Equal(int32(0), filteredAck.nacks.Load())
+}
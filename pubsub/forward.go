@@ -0,0 +1,99 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+var _ Subscription[string, any] = (*forwardSubscription[string, any])(nil)
+
+// forwardSubscription is a Subscription that reads the events of an
+// inner subscription and forwards the outcome of processing them
+// to its own event stream.
+type forwardSubscription[T, P any] struct {
+	inner interface{ Close() error }
+
+	eventCh chan Event[T, P]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newForwardSubscription starts processing the events from inner in the
+// background.
+//
+// The forward func is called for every event of the inner subscription,
+// it should deliver the resulting events via send. Send reports false
+// if the subscription was closed in the meantime.
+// The context passed to forward is cancelled when the subscription is
+// closed.
+func newForwardSubscription[T, A, B any](
+	inner Subscription[T, A],
+	forward func(
+		ctx context.Context,
+		event Event[T, A],
+		send func(Event[T, B]) bool,
+	),
+) *forwardSubscription[T, B] {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &forwardSubscription[T, B]{
+		inner:   inner,
+		eventCh: make(chan Event[T, B]),
+		cancel:  cancel,
+	}
+
+	send := func(e Event[T, B]) bool {
+		select {
+		case s.eventCh <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		defer close(s.eventCh)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case e, ok := <-inner.C():
+				if !ok {
+					return
+				}
+
+				forward(ctx, e, send)
+			}
+		}
+	}()
+
+	return s
+}
+
+// C returns a receive-only go channel of the forwarded events.
+func (s *forwardSubscription[T, P]) C() <-chan Event[T, P] {
+	return s.eventCh
+}
+
+// Close stops forwarding events and closes the inner subscription.
+// Safe to call multiple times.
+func (s *forwardSubscription[T, P]) Close() error {
+	s.closeOnce.Do(func() {
+		s.cancel()
+
+		s.closeErr = s.inner.Close()
+
+		s.wg.Wait()
+	})
+
+	return s.closeErr
+}
@@ -0,0 +1,200 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// GroupOption configures a SubscriptionGroup.
+type GroupOption func(*groupOptions)
+
+type groupOptions struct {
+	errorHandler func(error)
+	metrics      *GroupMetrics
+}
+
+// WithErrorHandler configures a function called with the errors
+// of the handler and with the error events of the subscription.
+// These errors don't stop the group.
+func WithErrorHandler(f func(error)) GroupOption {
+	return func(o *groupOptions) {
+		o.errorHandler = f
+	}
+}
+
+// WithMetrics configures the metrics updated by the group workers.
+func WithMetrics(m *GroupMetrics) GroupOption {
+	return func(o *groupOptions) {
+		o.metrics = m
+	}
+}
+
+// GroupMetrics holds the per worker throughput and the lag
+// of a SubscriptionGroup.
+type GroupMetrics struct {
+	mu        sync.Mutex
+	processed map[int]uint64
+	failed    map[int]uint64
+
+	// the events received by the workers and not handled yet.
+	lag atomic.Int64
+}
+
+// NewGroupMetrics creates an empty GroupMetrics.
+func NewGroupMetrics() *GroupMetrics {
+	return &GroupMetrics{
+		processed: make(map[int]uint64),
+		failed:    make(map[int]uint64),
+	}
+}
+
+// Processed returns the number of events the worker handled successfully.
+func (m *GroupMetrics) Processed(worker int) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.processed[worker]
+}
+
+// Failed returns the number of events the worker failed to handle.
+func (m *GroupMetrics) Failed(worker int) uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.failed[worker]
+}
+
+// Lag returns the number of events the workers received from the
+// subscription and didn't finish handling yet.
+func (m *GroupMetrics) Lag() int64 {
+	return m.lag.Load()
+}
+
+func (m *GroupMetrics) received() {
+	m.lag.Add(1)
+}
+
+func (m *GroupMetrics) observe(worker int, failed bool) {
+	m.lag.Add(-1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if failed {
+		m.failed[worker]++
+	} else {
+		m.processed[worker]++
+	}
+}
+
+// SubscriptionGroup consumes a subscription with a pool of workers,
+// handling several events concurrently.
+type SubscriptionGroup[T, P any] struct {
+	sub     Subscription[T, P]
+	workers int
+	handler func(context.Context, Event[T, P]) error
+	opts    groupOptions
+}
+
+// NewSubscriptionGroup creates a SubscriptionGroup handling the events
+// of sub with the given number of workers.
+func NewSubscriptionGroup[T, P any](
+	sub Subscription[T, P],
+	workers int,
+	handler func(context.Context, Event[T, P]) error,
+	opts ...GroupOption,
+) *SubscriptionGroup[T, P] {
+	var o groupOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &SubscriptionGroup[T, P]{
+		sub:     sub,
+		workers: workers,
+		handler: handler,
+		opts:    o,
+	}
+}
+
+// Run starts the workers and blocks until the context is canceled
+// or the subscription is closed.
+//
+// An event is acked when the handler succeeds and nacked otherwise.
+func (g *SubscriptionGroup[T, P]) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	wg.Add(g.workers)
+
+	for worker := 0; worker < g.workers; worker++ {
+		go func(worker int) {
+			defer wg.Done()
+
+			g.work(ctx, worker)
+		}(worker)
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (g *SubscriptionGroup[T, P]) work(ctx context.Context, worker int) {
+	events := g.sub.C()
+
+	for {
+		var (
+			event Event[T, P]
+			ok    bool
+		)
+
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok = <-events:
+			if !ok {
+				return
+			}
+		}
+
+		if event.Error != nil {
+			g.reportError(fmt.Errorf("subscription: %w", event.Error))
+
+			continue
+		}
+
+		if g.opts.metrics != nil {
+			g.opts.metrics.received()
+		}
+
+		err := g.handler(ctx, event)
+
+		if g.opts.metrics != nil {
+			g.opts.metrics.observe(worker, err != nil)
+		}
+
+		if err != nil {
+			event.Nack()
+
+			g.reportError(fmt.Errorf("handle event: %w", err))
+
+			continue
+		}
+
+		event.Ack()
+	}
+}
+
+func (g *SubscriptionGroup[T, P]) reportError(err error) {
+	if g.opts.errorHandler != nil {
+		g.opts.errorHandler(err)
+	}
+}
@@ -0,0 +1,163 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestSubscriptionGroup(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const events = 10
+
+	ps := inmem.NewPubSub[string, string](events)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	ack := new(testAcknowledger)
+
+	for j := 0; j < events; j++ {
+		payload := "ok"
+		if j%2 == 0 {
+			payload = "fail"
+		}
+
+		err := ps.Publish(pubsub.Event[string, string]{Payload: payload, Acknowledger: ack}, "a")
+		i.NoErr(err)
+	}
+
+	var (
+		handled  atomic.Int32
+		reported atomic.Int32
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metrics := pubsub.NewGroupMetrics()
+
+	group := pubsub.NewSubscriptionGroup(
+		sub,
+		3,
+		func(_ context.Context, e pubsub.Event[string, string]) error {
+			if handled.Add(1) == events {
+				cancel()
+			}
+
+			if e.Payload == "fail" {
+				// nolint: goerr113 // allow dynamic error.
+				return errors.New("fail")
+			}
+
+			return nil
+		},
+		pubsub.WithErrorHandler(func(error) { reported.Add(1) }),
+		pubsub.WithMetrics(metrics),
+	)
+
+	done := make(chan error)
+
+	go func() { done <- group.Run(ctx) }()
+
+	select {
+	case err := <-done:
+		i.NoErr(err)
+
+	case <-time.After(time.Second):
+		t.Fatal("group did not stop")
+	}
+
+	i.Equal(int32(events/2), ack.acks.Load())
+	i.Equal(int32(events/2), ack.nacks.Load())
+	i.Equal(int32(events/2), reported.Load())
+
+	var processed, failed uint64
+
+	for worker := 0; worker < 3; worker++ {
+		processed += metrics.Processed(worker)
+		failed += metrics.Failed(worker)
+	}
+
+	i.Equal(uint64(events/2), processed)
+	i.Equal(uint64(events/2), failed)
+}
+
+func TestSubscriptionGroupLag(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	const workers = 2
+
+	ps := inmem.NewPubSub[string, string](workers)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	ack := new(testAcknowledger)
+
+	for j := 0; j < workers; j++ {
+		err := ps.Publish(pubsub.Event[string, string]{Payload: "ok", Acknowledger: ack}, "a")
+		i.NoErr(err)
+	}
+
+	var (
+		started = make(chan struct{}, workers)
+		release = make(chan struct{})
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	metrics := pubsub.NewGroupMetrics()
+
+	group := pubsub.NewSubscriptionGroup(
+		sub,
+		workers,
+		func(context.Context, pubsub.Event[string, string]) error {
+			started <- struct{}{}
+
+			<-release
+
+			return nil
+		},
+		pubsub.WithMetrics(metrics),
+	)
+
+	done := make(chan error)
+
+	go func() { done <- group.Run(ctx) }()
+
+	for j := 0; j < workers; j++ {
+		<-started
+	}
+
+	// both events are being handled.
+	i.Equal(int64(workers), metrics.Lag())
+
+	close(release)
+
+	for ack.acks.Load() != workers {
+		time.Sleep(time.Millisecond)
+	}
+
+	i.Equal(int64(0), metrics.Lag())
+
+	cancel()
+
+	i.NoErr(<-done)
+}
@@ -0,0 +1,65 @@
+package inmem
+
+import (
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+// defaultChannelBuffer is the buffer size of the subscriptions
+// of a Broker, unless WithChannelBuffer is used.
+const defaultChannelBuffer = 100
+
+// BrokerOption configures the Broker.
+type BrokerOption func(*brokerOptions)
+
+type brokerOptions struct {
+	channelBuffer int
+}
+
+// WithChannelBuffer sets the buffer size of the event channel of each
+// subscription. The subscriptions that fall behind by more than n events
+// are closed. Defaults to 100.
+func WithChannelBuffer(n int) BrokerOption {
+	return func(o *brokerOptions) {
+		o.channelBuffer = n
+	}
+}
+
+// Broker exchanges, in process, the same events as the broker backed
+// publishers and subscribers, e.g. of the kafka or nats packages.
+// It is meant for tests and local development.
+type Broker struct {
+	ps *PubSub[string, []byte]
+}
+
+// NewBroker returns a Broker without any channels.
+func NewBroker(opts ...BrokerOption) *Broker {
+	o := brokerOptions{
+		channelBuffer: defaultChannelBuffer,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Broker{
+		ps: NewPubSub[string, []byte](o.channelBuffer),
+	}
+}
+
+// Publisher returns a Publisher delivering the events
+// to the subscriptions of the broker.
+func (b *Broker) Publisher() pubsub.Publisher[string, []byte] {
+	return b.ps
+}
+
+// Subscriber returns a Subscriber of the events
+// published to the broker.
+func (b *Broker) Subscriber() pubsub.Subscriber[string, []byte] {
+	return b.ps
+}
+
+// Reset closes all the subscriptions of the broker
+// and clears its channels.
+func (b *Broker) Reset() {
+	b.ps.Reset()
+}
@@ -0,0 +1,48 @@
+package inmem
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+func TestBroker(t *testing.T) {
+	i := is.New(t)
+
+	broker := NewBroker(WithChannelBuffer(2))
+
+	sub, err := broker.Subscriber().Subscribe("a")
+	i.NoErr(err)
+
+	for _, payload := range []string{"1", "2"} {
+		err = broker.Publisher().Publish(pubsub.Event[string, []byte]{
+			Type:    "test",
+			Payload: []byte(payload),
+		}, "a")
+		i.NoErr(err)
+	}
+
+	i.Equal(2, len(sub.C()))
+	i.Equal("1", string((<-sub.C()).Payload))
+	i.Equal("2", string((<-sub.C()).Payload))
+
+	broker.Reset()
+
+	// Ensure the subscription channel is closed.
+	_, open := <-sub.C()
+	i.True(!open)
+
+	// Ensure closing a reset subscription is ok.
+	i.NoErr(sub.Close())
+
+	// Ensure the broker is usable after a reset.
+	sub, err = broker.Subscriber().Subscribe("a")
+	i.NoErr(err)
+
+	err = broker.Publisher().Publish(pubsub.Event[string, []byte]{Type: "test"}, "a")
+	i.NoErr(err)
+
+	e := <-sub.C()
+	i.Equal("test", e.Type)
+}
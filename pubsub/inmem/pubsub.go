@@ -122,6 +122,23 @@ func (ps *PubSub[T, P]) Unsubscribe(sub *Subscription[T, P]) {
 	ps.removeSubscription(sub)
 }
 
+// Reset closes all the subscriptions and removes them,
+// along with their channels, from the service.
+func (ps *PubSub[T, P]) Reset() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, subs := range ps.channelsSubs {
+		for sub := range subs {
+			sub.once.Do(func() {
+				close(sub.c)
+			})
+		}
+	}
+
+	ps.channelsSubs = make(map[string]map[*Subscription[T, P]]struct{})
+}
+
 // removeSubscription closes the subscriptions go channel and
 // removes it from the pubsubs storage.
 func (ps *PubSub[T, P]) removeSubscription(sub *Subscription[T, P]) {
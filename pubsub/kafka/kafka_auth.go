@@ -0,0 +1,114 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/xdg-go/scram"
+)
+
+// ErrUnsupportedSASLMechanism is returned when a SCRAM option
+// is configured with a mechanism other than SCRAM-SHA-256 or SCRAM-SHA-512.
+var ErrUnsupportedSASLMechanism = errors.New("unsupported sasl mechanism")
+
+// configOption modifies the sarama config of a subscriber or a publisher.
+type configOption func(*sarama.Config) error
+
+// WithSASLSCRAM authenticates the subscriber to the brokers with
+// SASL/SCRAM, algorithm being either sarama.SASLTypeSCRAMSHA256 or
+// sarama.SASLTypeSCRAMSHA512.
+func WithSASLSCRAM(username, password string, algorithm sarama.SASLMechanism) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.configOptions = append(o.configOptions, saslSCRAM(username, password, algorithm))
+	}
+}
+
+// WithTLS connects the subscriber to the brokers over TLS using cfg.
+func WithTLS(cfg *tls.Config) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.configOptions = append(o.configOptions, brokerTLS(cfg))
+	}
+}
+
+// WithPublisherSASLSCRAM authenticates the publisher to the brokers with
+// SASL/SCRAM, algorithm being either sarama.SASLTypeSCRAMSHA256 or
+// sarama.SASLTypeSCRAMSHA512.
+func WithPublisherSASLSCRAM(username, password string, algorithm sarama.SASLMechanism) PublisherOption {
+	return func(o *publisherOptions) {
+		o.configOptions = append(o.configOptions, saslSCRAM(username, password, algorithm))
+	}
+}
+
+// WithPublisherTLS connects the publisher to the brokers over TLS using cfg.
+func WithPublisherTLS(cfg *tls.Config) PublisherOption {
+	return func(o *publisherOptions) {
+		o.configOptions = append(o.configOptions, brokerTLS(cfg))
+	}
+}
+
+func saslSCRAM(username, password string, algorithm sarama.SASLMechanism) configOption {
+	return func(cfg *sarama.Config) error {
+		var hash scram.HashGeneratorFcn
+
+		switch algorithm {
+		case sarama.SASLTypeSCRAMSHA256:
+			hash = scram.SHA256
+
+		case sarama.SASLTypeSCRAMSHA512:
+			hash = scram.SHA512
+
+		default:
+			return fmt.Errorf("%w: %s", ErrUnsupportedSASLMechanism, algorithm)
+		}
+
+		cfg.Net.SASL.Enable = true
+		cfg.Net.SASL.Handshake = true
+		cfg.Net.SASL.Mechanism = algorithm
+		cfg.Net.SASL.User = username
+		cfg.Net.SASL.Password = password
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &xdgScramClient{
+				HashGeneratorFcn: hash,
+			}
+		}
+
+		return nil
+	}
+}
+
+func brokerTLS(tlsConfig *tls.Config) configOption {
+	return func(cfg *sarama.Config) error {
+		cfg.Net.TLS.Enable = true
+		cfg.Net.TLS.Config = tlsConfig
+
+		return nil
+	}
+}
+
+// applyConfigOptions returns a copy of cfg, or of defaultCfg if cfg is
+// nil, modified by opts. The caller's config is left untouched.
+func applyConfigOptions(
+	cfg *sarama.Config,
+	defaultCfg func() *sarama.Config,
+	opts []configOption,
+) (*sarama.Config, error) {
+	if len(opts) == 0 {
+		return cfg, nil
+	}
+
+	if cfg == nil {
+		cfg = defaultCfg()
+	}
+
+	c := *cfg
+
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, err
+		}
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,130 @@
+package kafka_test
+
+import (
+	"crypto/tls"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+	"go.uber.org/zap"
+)
+
+func TestSASLSCRAMOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("UnsupportedMechanism", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := kafka.NewSubscriber(
+			zap.NewNop(),
+			nil,
+			[]string{"localhost:9092"},
+			"",
+			kafka.WithSASLSCRAM("user", "pass", sarama.SASLTypePlaintext),
+		)
+		i.True(errors.Is(err, kafka.ErrUnsupportedSASLMechanism))
+
+		_, err = kafka.NewPublisher(
+			zap.NewNop(),
+			nil,
+			[]string{"localhost:9092"},
+			kafka.WithPublisherSASLSCRAM("user", "pass", sarama.SASLTypeOAuth),
+		)
+		i.True(errors.Is(err, kafka.ErrUnsupportedSASLMechanism))
+	})
+
+	t.Run("ConfigNotModified", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		cfg := kafka.NewSASLPlainSubscriberConfig("user", "pass")
+
+		suber, err := kafka.NewSubscriber(
+			zap.NewNop(),
+			cfg,
+			[]string{"localhost:9092"},
+			"",
+			kafka.WithSASLSCRAM("user", "pass", sarama.SASLTypeSCRAMSHA512),
+			kafka.WithTLS(&tls.Config{MinVersion: tls.VersionTLS12}),
+		)
+		i.NoErr(err)
+		i.NoErr(suber.Close())
+
+		i.Equal(cfg.Net.SASL.Mechanism, sarama.SASLMechanism(sarama.SASLTypePlaintext))
+		i.True(!cfg.Net.TLS.Enable)
+	})
+}
+
+// TestSASLSCRAM requires a broker accepting SCRAM credentials, the test
+// is skipped unless KAFKA_SCRAM_BROKER_URL is set.
+func TestSASLSCRAM(t *testing.T) {
+	var (
+		username  = os.Getenv("KAFKA_SCRAM_USERNAME")
+		password  = os.Getenv("KAFKA_SCRAM_PASSWORD")
+		brokerURL = os.Getenv("KAFKA_SCRAM_BROKER_URL")
+		topic     = os.Getenv("KAFKA_SCRAM_TEST_TOPIC")
+	)
+
+	if brokerURL == "" {
+		t.Skip("KAFKA_SCRAM_BROKER_URL is not set")
+	}
+
+	i := is.New(t)
+
+	mechanism := sarama.SASLMechanism(sarama.SASLTypeSCRAMSHA512)
+	if m := os.Getenv("KAFKA_SCRAM_MECHANISM"); m != "" {
+		mechanism = sarama.SASLMechanism(m)
+	}
+
+	var (
+		subOpts = []kafka.SubscriberOption{kafka.WithSASLSCRAM(username, password, mechanism)}
+		pubOpts = []kafka.PublisherOption{kafka.WithPublisherSASLSCRAM(username, password, mechanism)}
+	)
+
+	if os.Getenv("KAFKA_SCRAM_TLS") != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+		subOpts = append(subOpts, kafka.WithTLS(tlsConfig))
+		pubOpts = append(pubOpts, kafka.WithPublisherTLS(tlsConfig))
+	}
+
+	suber, err := kafka.NewSubscriber(zap.NewNop(), nil, []string{brokerURL}, "", subOpts...)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(suber.Close()) })
+
+	pub, err := kafka.NewPublisher(zap.NewNop(), nil, []string{brokerURL}, pubOpts...)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(pub.Close()) })
+
+	sub, err := suber.Subscribe(topic)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	mes := pubsub.Event[string, []byte]{
+		Type:    "test",
+		Payload: []byte("test"),
+	}
+
+	i.NoErr(pub.Publish(mes, topic))
+
+	select {
+	case received := <-sub.C():
+		i.NoErr(received.Error)
+		i.Equal(received.Payload, mes.Payload)
+		i.True(received.Ack())
+
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout")
+	}
+}
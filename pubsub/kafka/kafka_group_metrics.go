@@ -0,0 +1,176 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ClusterAdmin is the subset of the kafka cluster operations used to
+// compute the lag of a consumer group.
+//
+// sarama.ClusterAdmin can't query the newest offsets of a partition,
+// use NewClusterAdmin to complement it with a sarama.Client.
+type ClusterAdmin interface {
+	DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error)
+	ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error)
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+}
+
+type clusterAdmin struct {
+	sarama.ClusterAdmin
+	sarama.Client
+}
+
+// NewClusterAdmin returns a ClusterAdmin backed by client.
+// The admin shares the client, close the client once done.
+func NewClusterAdmin(client sarama.Client) (ClusterAdmin, error) {
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("new cluster admin: %w", err)
+	}
+
+	return clusterAdmin{ClusterAdmin: admin, Client: client}, nil
+}
+
+// ConsumerGroupMetricsExporter periodically exports the committed
+// offsets and the lag of a consumer group as prometheus gauges.
+type ConsumerGroupMetricsExporter struct {
+	admin    ClusterAdmin
+	group    string
+	topic    string
+	interval time.Duration
+
+	lag      *prometheus.GaugeVec
+	offset   *prometheus.GaugeVec
+	totalLag atomic.Int64
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewConsumerGroupMetricsExporter returns an exporter of the
+// kafka_consumer_group_lag and kafka_consumer_group_offset gauges,
+// labeled by topic, partition and consumer_group, registered with reg.
+//
+// The gauges are shared with the other exporters and lag collectors
+// registered with reg. It panics if they can't be registered.
+func NewConsumerGroupMetricsExporter(
+	admin ClusterAdmin,
+	group, topic string,
+	reg prometheus.Registerer,
+	interval time.Duration,
+) *ConsumerGroupMetricsExporter {
+	e := &ConsumerGroupMetricsExporter{
+		admin:    admin,
+		group:    group,
+		topic:    topic,
+		interval: interval,
+	}
+
+	var err error
+
+	if e.lag, err = registerGaugeVec(reg, newConsumerGroupLagGauge()); err != nil {
+		panic(fmt.Errorf("register lag gauge: %w", err))
+	}
+
+	if e.offset, err = registerGaugeVec(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_group_offset",
+		Help: "Offset committed by the consumer group.",
+	}, []string{"topic", "partition", "consumer_group"})); err != nil {
+		panic(fmt.Errorf("register offset gauge: %w", err))
+	}
+
+	return e
+}
+
+// Start exports the metrics every interval, in the background,
+// until ctx is cancelled or Stop is called.
+func (e *ConsumerGroupMetricsExporter) Start(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.cancel != nil {
+		return
+	}
+
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	e.wg.Add(1)
+
+	go func() {
+		defer e.wg.Done()
+
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := e.export(); err != nil {
+				slog.Error(
+					"export consumer group metrics",
+					slog.String("group", e.group),
+					slog.String("topic", e.topic),
+					slog.String("error", err.Error()),
+				)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops exporting the metrics and waits for the running
+// export to finish.
+func (e *ConsumerGroupMetricsExporter) Stop() {
+	e.mu.Lock()
+	cancel := e.cancel
+	e.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+
+	e.wg.Wait()
+}
+
+// TotalLag returns the lag summed over all the partitions,
+// as of the last export.
+func (e *ConsumerGroupMetricsExporter) TotalLag() int64 {
+	return e.totalLag.Load()
+}
+
+func (e *ConsumerGroupMetricsExporter) export() error {
+	lags, err := consumerGroupLag(e.admin, e.group, e.topic)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range lags {
+		labels := prometheus.Labels{
+			"topic":          e.topic,
+			"partition":      strconv.Itoa(int(l.partition)),
+			"consumer_group": e.group,
+		}
+
+		e.lag.With(labels).Set(float64(l.lag))
+		e.offset.With(labels).Set(float64(l.committed))
+	}
+
+	e.totalLag.Store(totalLag(lags))
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package kafka_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+)
+
+func TestConsumerGroupMetricsExporter(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	admin := &mockClusterAdmin{
+		newest:    map[int32]int64{0: 10, 1: 5},
+		committed: map[int32]int64{0: 7, 1: -1},
+	}
+
+	reg := prometheus.NewRegistry()
+
+	exporter := kafka.NewConsumerGroupMetricsExporter(admin, "group", "topic", reg, time.Hour)
+
+	exporter.Start(context.Background())
+	exporter.Stop()
+
+	i.Equal(int64(8), exporter.TotalLag())
+
+	i.NoErr(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP kafka_consumer_group_lag Number of messages the consumer group is behind the newest offset.
+# TYPE kafka_consumer_group_lag gauge
+kafka_consumer_group_lag{consumer_group="group",partition="0",topic="topic"} 3
+kafka_consumer_group_lag{consumer_group="group",partition="1",topic="topic"} 5
+# HELP kafka_consumer_group_offset Offset committed by the consumer group.
+# TYPE kafka_consumer_group_offset gauge
+kafka_consumer_group_offset{consumer_group="group",partition="0",topic="topic"} 7
+kafka_consumer_group_offset{consumer_group="group",partition="1",topic="topic"} 0
+`)))
+}
+
+var _ kafka.ClusterAdmin = (*mockClusterAdmin)(nil)
+
+type mockClusterAdmin struct {
+	newest, committed map[int32]int64
+}
+
+func (a *mockClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	metadata := &sarama.TopicMetadata{Name: topics[0]}
+
+	for id := range a.newest {
+		metadata.Partitions = append(metadata.Partitions, &sarama.PartitionMetadata{ID: id})
+	}
+
+	return []*sarama.TopicMetadata{metadata}, nil
+}
+
+func (a *mockClusterAdmin) ListConsumerGroupOffsets(
+	_ string,
+	topicPartitions map[string][]int32,
+) (*sarama.OffsetFetchResponse, error) {
+	resp := new(sarama.OffsetFetchResponse)
+
+	for topic, partitions := range topicPartitions {
+		for _, p := range partitions {
+			resp.AddBlock(topic, p, &sarama.OffsetFetchResponseBlock{Offset: a.committed[p]})
+		}
+	}
+
+	return resp, nil
+}
+
+func (a *mockClusterAdmin) GetOffset(_ string, partitionID int32, _ int64) (int64, error) {
+	return a.newest[partitionID], nil
+}
@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newConsumerGroupLagGauge returns the kafka_consumer_group_lag gauge,
+// shared by the lag collectors and the metrics exporters.
+func newConsumerGroupLagGauge() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_group_lag",
+		Help: "Number of messages the consumer group is behind the newest offset.",
+	}, []string{"topic", "partition", "consumer_group"})
+}
+
+// registerGaugeVec registers gauge with registerer, returning the gauge
+// already registered under the same name and labels, if any, so that
+// several collectors, e.g. of different consumer groups, share it.
+func registerGaugeVec(
+	registerer prometheus.Registerer,
+	gauge *prometheus.GaugeVec,
+) (*prometheus.GaugeVec, error) {
+	err := registerer.Register(gauge)
+	if err == nil {
+		return gauge, nil
+	}
+
+	var registeredErr prometheus.AlreadyRegisteredError
+
+	if errors.As(err, &registeredErr) {
+		if existing, ok := registeredErr.ExistingCollector.(*prometheus.GaugeVec); ok {
+			return existing, nil
+		}
+	}
+
+	return nil, err
+}
+
+// partitionLag is the lag of a consumer group on a partition.
+type partitionLag struct {
+	partition int32
+
+	// the offset committed by the group, 0 if none.
+	committed int64
+
+	// the number of messages the group is behind the newest offset.
+	lag int64
+}
+
+// consumerGroupLag returns the lag of group on each partition of topic.
+//
+// Partitions without a committed offset count all of their messages.
+// The lag is never negative, e.g. when the newest offset is fetched
+// before a concurrent commit.
+func consumerGroupLag(admin ClusterAdmin, group, topic string) ([]partitionLag, error) {
+	topics, err := admin.DescribeTopics([]string{topic})
+	if err != nil {
+		return nil, fmt.Errorf("describe topic: %w", err)
+	}
+
+	if len(topics) != 1 {
+		return nil, fmt.Errorf("describe topic: %w", sarama.ErrUnknownTopicOrPartition)
+	}
+
+	if topics[0].Err != sarama.ErrNoError {
+		return nil, fmt.Errorf("describe topic: %w", topics[0].Err)
+	}
+
+	partitions := make([]int32, len(topics[0].Partitions))
+
+	for i, p := range topics[0].Partitions {
+		partitions[i] = p.ID
+	}
+
+	offsets, err := admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return nil, fmt.Errorf("list consumer group offsets: %w", err)
+	}
+
+	lags := make([]partitionLag, len(partitions))
+
+	for i, partition := range partitions {
+		newest, err := admin.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return nil, fmt.Errorf("get newest offset: %w", err)
+		}
+
+		committed := int64(0)
+
+		if block := offsets.GetBlock(topic, partition); block != nil {
+			if block.Err != sarama.ErrNoError {
+				return nil, fmt.Errorf("list consumer group offsets: %w", block.Err)
+			}
+
+			if block.Offset >= 0 {
+				committed = block.Offset
+			}
+		}
+
+		lags[i] = partitionLag{
+			partition: partition,
+			committed: committed,
+			lag:       max(newest-committed, 0),
+		}
+	}
+
+	return lags, nil
+}
+
+// totalLag sums the lag of the partitions.
+func totalLag(lags []partitionLag) int64 {
+	var total int64
+
+	for _, l := range lags {
+		total += l.lag
+	}
+
+	return total
+}
@@ -0,0 +1,193 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrLagCollectorRegistered is returned when the lag collector of
+// a subscriber is registered more than once.
+var ErrLagCollectorRegistered = errors.New("lag collector already registered")
+
+// defaultLagCollectionInterval is the default interval between two
+// collections of the consumer group lag.
+const defaultLagCollectionInterval = 30 * time.Second
+
+// WithLagCollectionInterval sets the interval between two collections
+// of the consumer group lag, see Subscriber.RegisterLagCollector.
+// Defaults to 30s.
+func WithLagCollectionInterval(d time.Duration) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.lagInterval = d
+	}
+}
+
+// WithClusterAdmin sets the admin the consumer group lag is collected
+// with. Defaults to an admin connected to the brokers of the subscriber,
+// closed along with it.
+func WithClusterAdmin(admin ClusterAdmin) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.admin = admin
+	}
+}
+
+// RegisterLagCollector registers with registerer the kafka_consumer_group_lag
+// gauge, labeled by topic, partition and consumer_group, and updates it
+// periodically, in the background, until the subscriber is closed.
+//
+// The lag is collected for all the topics the consumer group
+// committed offsets to. The gauge is shared with the other lag
+// collectors and metrics exporters registered with registerer.
+func (s *Subscriber) RegisterLagCollector(registerer prometheus.Registerer) error {
+	if s.lag != nil {
+		return ErrLagCollectorRegistered
+	}
+
+	admin := s.opts.admin
+
+	var client sarama.Client
+
+	if admin == nil {
+		var err error
+
+		client, err = sarama.NewClient(s.brokers, s.saramaConfig)
+		if err != nil {
+			return fmt.Errorf("new kafka client: %w", err)
+		}
+
+		admin, err = NewClusterAdmin(client)
+		if err != nil {
+			return errors.Join(err, client.Close())
+		}
+	}
+
+	lag, err := registerGaugeVec(registerer, newConsumerGroupLagGauge())
+	if err != nil {
+		err = fmt.Errorf("register lag gauge: %w", err)
+
+		if client != nil {
+			err = errors.Join(err, client.Close())
+		}
+
+		return err
+	}
+
+	c := &lagCollector{
+		admin:  admin,
+		client: client,
+		group:  s.consumerGroup,
+		lag:    lag,
+	}
+
+	interval := s.opts.lagInterval
+	if interval <= 0 {
+		interval = defaultLagCollectionInterval
+	}
+
+	c.start(interval)
+
+	s.lag = c
+
+	return nil
+}
+
+// lagCollector periodically collects the lag of a consumer group.
+type lagCollector struct {
+	admin ClusterAdmin
+
+	// the client of the admin, if owned by the collector.
+	client sarama.Client
+
+	group string
+	lag   *prometheus.GaugeVec
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (c *lagCollector) start(interval time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c.cancel = cancel
+
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.collect(); err != nil {
+				slog.Error(
+					"collect consumer group lag",
+					slog.String("group", c.group),
+					slog.String("error", err.Error()),
+				)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// close stops the collection and closes the owned client.
+// Safe to call multiple times.
+func (c *lagCollector) close() error {
+	c.closeOnce.Do(func() {
+		c.cancel()
+
+		c.wg.Wait()
+
+		if c.client == nil {
+			return
+		}
+
+		if err := c.client.Close(); err != nil {
+			c.closeErr = fmt.Errorf("close kafka client: %w", err)
+		}
+	})
+
+	return c.closeErr
+}
+
+func (c *lagCollector) collect() error {
+	// nil lists the offsets of all the partitions.
+	offsets, err := c.admin.ListConsumerGroupOffsets(c.group, nil)
+	if err != nil {
+		return fmt.Errorf("list consumer group offsets: %w", err)
+	}
+
+	for topic := range offsets.Blocks {
+		lags, err := consumerGroupLag(c.admin, c.group, topic)
+		if err != nil {
+			return fmt.Errorf("lag for topic %s: %w", topic, err)
+		}
+
+		for _, l := range lags {
+			c.lag.With(prometheus.Labels{
+				"topic":          topic,
+				"partition":      strconv.Itoa(int(l.partition)),
+				"consumer_group": c.group,
+			}).Set(float64(l.lag))
+		}
+	}
+
+	return nil
+}
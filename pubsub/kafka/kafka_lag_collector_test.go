@@ -0,0 +1,134 @@
+package kafka_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/matryer/is"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+	"go.uber.org/zap"
+)
+
+func TestRegisterLagCollector(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	admin := &groupClusterAdmin{
+		newest: map[string]map[int32]int64{
+			"a": {0: 10, 1: 5},
+			"b": {0: 3},
+		},
+		committed: map[string]map[int32]int64{
+			"a": {0: 7, 1: -1},
+			"b": {0: 3},
+		},
+	}
+
+	suber, err := kafka.NewSubscriber(
+		zap.NewNop(),
+		nil,
+		[]string{"localhost:9092"},
+		"group",
+		kafka.WithClusterAdmin(admin),
+		kafka.WithLagCollectionInterval(time.Hour),
+	)
+	i.NoErr(err)
+
+	reg := prometheus.NewRegistry()
+
+	i.NoErr(suber.RegisterLagCollector(reg))
+	i.True(errors.Is(suber.RegisterLagCollector(reg), kafka.ErrLagCollectorRegistered))
+
+	// the first collection runs right away.
+	i.NoErr(suber.Close())
+
+	i.NoErr(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP kafka_consumer_group_lag Number of messages the consumer group is behind the newest offset.
+# TYPE kafka_consumer_group_lag gauge
+kafka_consumer_group_lag{consumer_group="group",partition="0",topic="a"} 3
+kafka_consumer_group_lag{consumer_group="group",partition="1",topic="a"} 5
+kafka_consumer_group_lag{consumer_group="group",partition="0",topic="b"} 0
+`)))
+}
+
+func TestRegisterLagCollectorSharedGauge(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	admin := &groupClusterAdmin{
+		newest:    map[string]map[int32]int64{"a": {0: 10}},
+		committed: map[string]map[int32]int64{"a": {0: 7}},
+	}
+
+	reg := prometheus.NewRegistry()
+
+	// the exporter registers the lag gauge first.
+	kafka.NewConsumerGroupMetricsExporter(admin, "exporter", "a", reg, time.Hour)
+
+	suber, err := kafka.NewSubscriber(
+		zap.NewNop(),
+		nil,
+		[]string{"localhost:9092"},
+		"group",
+		kafka.WithClusterAdmin(admin),
+		kafka.WithLagCollectionInterval(time.Hour),
+	)
+	i.NoErr(err)
+
+	i.NoErr(suber.RegisterLagCollector(reg))
+	i.NoErr(suber.Close())
+
+	i.NoErr(testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP kafka_consumer_group_lag Number of messages the consumer group is behind the newest offset.
+# TYPE kafka_consumer_group_lag gauge
+kafka_consumer_group_lag{consumer_group="group",partition="0",topic="a"} 3
+`), "kafka_consumer_group_lag"))
+}
+
+var _ kafka.ClusterAdmin = (*groupClusterAdmin)(nil)
+
+// groupClusterAdmin lists the offsets committed by the group
+// to all the topics, whichever partitions are asked for.
+type groupClusterAdmin struct {
+	newest, committed map[string]map[int32]int64
+}
+
+func (a *groupClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	metadata := make([]*sarama.TopicMetadata, len(topics))
+
+	for n, topic := range topics {
+		metadata[n] = &sarama.TopicMetadata{Name: topic}
+
+		for p := range a.newest[topic] {
+			metadata[n].Partitions = append(metadata[n].Partitions, &sarama.PartitionMetadata{ID: p})
+		}
+	}
+
+	return metadata, nil
+}
+
+func (a *groupClusterAdmin) ListConsumerGroupOffsets(
+	string,
+	map[string][]int32,
+) (*sarama.OffsetFetchResponse, error) {
+	resp := new(sarama.OffsetFetchResponse)
+
+	for topic, partitions := range a.committed {
+		for p, offset := range partitions {
+			resp.AddBlock(topic, p, &sarama.OffsetFetchResponseBlock{Offset: offset})
+		}
+	}
+
+	return resp, nil
+}
+
+func (a *groupClusterAdmin) GetOffset(topic string, partitionID int32, _ int64) (int64, error) {
+	return a.newest[topic][partitionID], nil
+}
@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/purposeinplay/go-commons/pubsub"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// The names of the instruments recorded with the meter set by
+// WithMeter and WithPublisherMeter.
+const (
+	MessagesPublishedMetric = "pubsub.messages.published"
+	PublishDurationMetric   = "pubsub.publish.duration"
+	MessagesReceivedMetric  = "pubsub.messages.received"
+	ProcessDurationMetric   = "pubsub.process.duration"
+)
+
+// WithMeter records the pubsub.messages.received counter and the
+// pubsub.process.duration histogram, measured from the receipt of an
+// event until it is acked or nacked, with meter.
+//
+// The measurements carry the topic and consumer_group attributes.
+func WithMeter(meter metric.Meter) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.meter = meter
+	}
+}
+
+// WithPublisherMeter records the pubsub.messages.published counter and
+// the pubsub.publish.duration histogram with meter.
+//
+// The measurements carry the topic attribute.
+func WithPublisherMeter(meter metric.Meter) PublisherOption {
+	return func(o *publisherOptions) {
+		o.meter = meter
+	}
+}
+
+type publisherInstruments struct {
+	published metric.Int64Counter
+	duration  metric.Float64Histogram
+}
+
+func newPublisherInstruments(meter metric.Meter) (*publisherInstruments, error) {
+	published, err := meter.Int64Counter(
+		MessagesPublishedMetric,
+		metric.WithDescription("Number of messages published."),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new %s counter: %w", MessagesPublishedMetric, err)
+	}
+
+	duration, err := meter.Float64Histogram(
+		PublishDurationMetric,
+		metric.WithDescription("Duration of publishing a message."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new %s histogram: %w", PublishDurationMetric, err)
+	}
+
+	return &publisherInstruments{
+		published: published,
+		duration:  duration,
+	}, nil
+}
+
+func (i *publisherInstruments) record(topic string, start time.Time) {
+	ctx := context.Background()
+	attrs := metric.WithAttributes(attribute.String("topic", topic))
+
+	i.published.Add(ctx, 1, attrs)
+	i.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
+
+type subscriberInstruments struct {
+	received metric.Int64Counter
+	duration metric.Float64Histogram
+
+	consumerGroup string
+}
+
+func newSubscriberInstruments(meter metric.Meter, consumerGroup string) (*subscriberInstruments, error) {
+	received, err := meter.Int64Counter(
+		MessagesReceivedMetric,
+		metric.WithDescription("Number of messages received."),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new %s counter: %w", MessagesReceivedMetric, err)
+	}
+
+	duration, err := meter.Float64Histogram(
+		ProcessDurationMetric,
+		metric.WithDescription("Duration from receiving a message until it is acked or nacked."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new %s histogram: %w", ProcessDurationMetric, err)
+	}
+
+	return &subscriberInstruments{
+		received:      received,
+		duration:      duration,
+		consumerGroup: consumerGroup,
+	}, nil
+}
+
+// observe counts the received event and returns an acknowledger
+// recording its process duration once ack settles it.
+func (i *subscriberInstruments) observe(topic string, ack pubsub.Acknowledger) pubsub.Acknowledger {
+	attrs := metric.WithAttributes(
+		attribute.String("topic", topic),
+		attribute.String("consumer_group", i.consumerGroup),
+	)
+
+	i.received.Add(context.Background(), 1, attrs)
+
+	return &meterAcknowledger{
+		ack:   ack,
+		start: time.Now(),
+		record: func(d time.Duration) {
+			i.duration.Record(context.Background(), d.Seconds(), attrs)
+		},
+	}
+}
+
+// meterAcknowledger records the time it took to settle an event.
+type meterAcknowledger struct {
+	ack    pubsub.Acknowledger
+	start  time.Time
+	record func(time.Duration)
+	once   sync.Once
+}
+
+func (a *meterAcknowledger) Ack() bool {
+	a.once.Do(func() { a.record(time.Since(a.start)) })
+
+	return a.ack.Ack()
+}
+
+func (a *meterAcknowledger) Nack() bool {
+	a.once.Do(func() { a.record(time.Since(a.start)) })
+
+	return a.ack.Nack()
+}
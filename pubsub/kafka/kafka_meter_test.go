@@ -0,0 +1,191 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+// recordingMeter records the measurements of its counters and
+// histograms by instrument name.
+type recordingMeter struct {
+	noop.Meter
+
+	err error
+
+	mu           sync.Mutex
+	counters     map[string]int64
+	measurements map[string]int
+}
+
+func newRecordingMeter() *recordingMeter {
+	return &recordingMeter{
+		counters:     make(map[string]int64),
+		measurements: make(map[string]int),
+	}
+}
+
+func (m *recordingMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	return recordingCounter{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Float64Histogram(
+	name string,
+	_ ...metric.Float64HistogramOption,
+) (metric.Float64Histogram, error) {
+	return recordingHistogram{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) counter(name string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[name]
+}
+
+func (m *recordingMeter) histogram(name string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.measurements[name]
+}
+
+type recordingCounter struct {
+	noop.Int64Counter
+
+	meter *recordingMeter
+	name  string
+}
+
+func (c recordingCounter) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	c.meter.mu.Lock()
+	defer c.meter.mu.Unlock()
+
+	c.meter.counters[c.name] += incr
+}
+
+type recordingHistogram struct {
+	noop.Float64Histogram
+
+	meter *recordingMeter
+	name  string
+}
+
+func (h recordingHistogram) Record(context.Context, float64, ...metric.RecordOption) {
+	h.meter.mu.Lock()
+	defer h.meter.mu.Unlock()
+
+	h.meter.measurements[h.name]++
+}
+
+func TestMeter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("InstrumentError", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		// nolint: goerr113 // allow dynamic error.
+		errInstrument := errors.New("instrument error")
+
+		meter := newRecordingMeter()
+		meter.err = errInstrument
+
+		_, err := kafka.NewSubscriber(
+			zap.NewNop(),
+			nil,
+			[]string{"localhost:9092"},
+			"",
+			kafka.WithMeter(meter),
+		)
+		i.True(errors.Is(err, errInstrument))
+
+		_, err = kafka.NewPublisher(
+			zap.NewNop(),
+			nil,
+			[]string{"localhost:9092"},
+			kafka.WithPublisherMeter(meter),
+		)
+		i.True(errors.Is(err, errInstrument))
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			username  = os.Getenv("KAFKA_USERNAME")
+			password  = os.Getenv("KAFKA_PASSWORD")
+			brokerURL = os.Getenv("KAFKA_BROKER_URL")
+			topic     = os.Getenv("KAFKA_TEST_TOPIC")
+		)
+
+		if brokerURL == "" {
+			t.Skip("KAFKA_BROKER_URL is not set")
+		}
+
+		i := is.New(t)
+
+		meter := newRecordingMeter()
+
+		suber, err := kafka.NewSubscriber(
+			zap.NewNop(),
+			kafka.NewSASLSubscriberConfig(username, password),
+			[]string{brokerURL},
+			"",
+			kafka.WithMeter(meter),
+		)
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(suber.Close()) })
+
+		pub, err := kafka.NewPublisher(
+			zap.NewNop(),
+			kafka.NewSASLPublisherConfig(username, password),
+			[]string{brokerURL},
+			kafka.WithPublisherMeter(meter),
+		)
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(pub.Close()) })
+
+		sub, err := suber.Subscribe(topic)
+		i.NoErr(err)
+
+		t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+		i.NoErr(pub.Publish(pubsub.Event[string, []byte]{
+			Type:    "test",
+			Payload: []byte("test"),
+		}, topic))
+
+		i.Equal(int64(1), meter.counter(kafka.MessagesPublishedMetric))
+		i.Equal(1, meter.histogram(kafka.PublishDurationMetric))
+
+		select {
+		case e := <-sub.C():
+			i.Equal(int64(1), meter.counter(kafka.MessagesReceivedMetric))
+			i.Equal(0, meter.histogram(kafka.ProcessDurationMetric))
+
+			i.True(e.Ack())
+			i.Equal(1, meter.histogram(kafka.ProcessDurationMetric))
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	})
+}
@@ -1,13 +1,16 @@
 package kafka
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/google/uuid"
 	"github.com/purposeinplay/go-commons/pubsub"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -16,6 +19,30 @@ var _ pubsub.Publisher[string, []byte] = (*Publisher)(nil)
 // Publisher represents a kafka publisher.
 type Publisher struct {
 	kafkaPublisher *kafka.Publisher
+
+	brokers      []string
+	saramaConfig *sarama.Config
+	instruments  *publisherInstruments
+	keyExtractor func(payload []byte) string
+}
+
+// PublisherOption configures the kafka publisher.
+type PublisherOption func(*publisherOptions)
+
+type publisherOptions struct {
+	configOptions []configOption
+	meter         metric.Meter
+	keyExtractor  func(payload []byte) string
+	marshaler     kafka.Marshaler
+}
+
+// WithDefaultKeyExtractor sets the func deriving the partition key
+// of the events published with Publish from their payload.
+// The events with an empty key are spread over the partitions.
+func WithDefaultKeyExtractor(f func(payload []byte) string) PublisherOption {
+	return func(o *publisherOptions) {
+		o.keyExtractor = f
+	}
 }
 
 // NewPublisher creates a new kafka publisher.
@@ -23,11 +50,33 @@ func NewPublisher(
 	logger *zap.Logger,
 	saramaConfig *sarama.Config,
 	brokers []string,
+	opts ...PublisherOption,
 ) (*Publisher, error) {
+	o := publisherOptions{
+		marshaler: kafka.DefaultMarshaler{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	saramaConfig, err := applyConfigOptions(saramaConfig, kafka.DefaultSaramaSyncPublisherConfig, o.configOptions)
+	if err != nil {
+		return nil, fmt.Errorf("configure kafka publisher: %w", err)
+	}
+
+	var instruments *publisherInstruments
+
+	if o.meter != nil {
+		if instruments, err = newPublisherInstruments(o.meter); err != nil {
+			return nil, err
+		}
+	}
+
 	pub, err := kafka.NewPublisher(
 		kafka.PublisherConfig{
 			Brokers:               brokers,
-			Marshaler:             kafka.DefaultMarshaler{},
+			Marshaler:             partitionKeyMarshaler{marshaler: o.marshaler},
 			OverwriteSaramaConfig: saramaConfig,
 		},
 		newLoggerAdapter(logger),
@@ -38,26 +87,80 @@ func NewPublisher(
 
 	return &Publisher{
 		kafkaPublisher: pub,
+		brokers:        brokers,
+		saramaConfig:   saramaConfig,
+		instruments:    instruments,
+		keyExtractor:   o.keyExtractor,
 	}, nil
 }
 
 // Publish publishes an event to a kafka topic.
+//
+// The payload is wrapped in a watermill message, carrying the type and
+// the headers of the event as metadata, and published synchronously.
+// The partition key is derived from the payload if the publisher was
+// created using WithDefaultKeyExtractor.
 func (p Publisher) Publish(event pubsub.Event[string, []byte], channels ...string) error {
 	if len(channels) != 1 {
 		return pubsub.ErrExactlyOneChannelAllowed
 	}
 
+	var key string
+
+	if p.keyExtractor != nil {
+		key = p.keyExtractor(event.Payload)
+	}
+
+	return p.publish(context.Background(), channels[0], key, event)
+}
+
+// PublishKeyed publishes an event to a kafka topic, like Publish, using
+// key as the partition key so the events with the same key are
+// delivered in order.
+func (p Publisher) PublishKeyed(
+	ctx context.Context,
+	channel, key string,
+	event pubsub.Event[string, []byte],
+) error {
+	return p.publish(ctx, channel, key, event)
+}
+
+func (p Publisher) publish(
+	ctx context.Context,
+	channel, key string,
+	event pubsub.Event[string, []byte],
+) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
 	mes := message.NewMessage(uuid.New().String(), event.Payload)
 
+	mes.SetContext(ctx)
+
+	for k, v := range event.Headers {
+		mes.Metadata.Set(k, v)
+	}
+
 	mes.Metadata.Set("type", event.Type)
 
+	if key != "" {
+		mes.Metadata.Set(PartitionKeyMetadataKey, key)
+	}
+
+	start := time.Now()
+
 	if err := p.kafkaPublisher.Publish(
-		channels[0],
+		channel,
 		mes,
 	); err != nil {
 		return fmt.Errorf("publish: %w", err)
 	}
 
+	if p.instruments != nil {
+		p.instruments.record(channel, start)
+	}
+
 	return nil
 }
 
@@ -65,3 +168,27 @@ func (p Publisher) Publish(event pubsub.Event[string, []byte], channels ...strin
 func (p Publisher) Close() error {
 	return p.kafkaPublisher.Close()
 }
+
+// PartitionKeyMetadataKey is the metadata, and header, carrying the
+// partition key of a message.
+const PartitionKeyMetadataKey = "partition_key"
+
+// partitionKeyMarshaler sets the partition key of the messages,
+// marshaled with marshaler, with a PartitionKeyMetadataKey metadata.
+type partitionKeyMarshaler struct {
+	marshaler kafka.Marshaler
+}
+
+func (m partitionKeyMarshaler) Marshal(topic string, msg *message.Message) (*sarama.ProducerMessage, error) {
+	kafkaMsg, err := m.marshaler.Marshal(topic, msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal message: %w", err)
+	}
+
+	// an empty key would send all the messages to the same partition.
+	if key := msg.Metadata.Get(PartitionKeyMetadataKey); key != "" {
+		kafkaMsg.Key = sarama.StringEncoder(key)
+	}
+
+	return kafkaMsg, nil
+}
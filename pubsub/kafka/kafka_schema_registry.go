@@ -0,0 +1,308 @@
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/linkedin/goavro/v2"
+)
+
+// ErrInvalidWireFormat is returned when a kafka message value is not
+// prefixed by the magic byte and the schema id of the Confluent wire format.
+var ErrInvalidWireFormat = errors.New("invalid confluent wire format")
+
+// ErrSchemaRegistry is returned when the schema registry responds
+// with an unexpected status code.
+var ErrSchemaRegistry = errors.New("schema registry error")
+
+const (
+	// confluentMagicByte starts the values in the confluent wire format,
+	// followed by the 4 bytes big endian schema id.
+	confluentMagicByte = 0
+
+	confluentHeaderSize = 5
+
+	schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+)
+
+// SchemaRegistryOption configures the schema registry marshaler.
+type SchemaRegistryOption func(*schemaRegistryOptions)
+
+type schemaRegistryOptions struct {
+	client             *http.Client
+	username, password string
+	subjectFunc        func(topic string) string
+}
+
+// WithSchemaRegistryBasicAuth authenticates the requests
+// to the schema registry with HTTP basic auth.
+func WithSchemaRegistryBasicAuth(username, password string) SchemaRegistryOption {
+	return func(o *schemaRegistryOptions) {
+		o.username = username
+		o.password = password
+	}
+}
+
+// WithSchemaRegistryHTTPClient sets the client used to query the
+// schema registry. Defaults to a client with a 10s timeout.
+func WithSchemaRegistryHTTPClient(client *http.Client) SchemaRegistryOption {
+	return func(o *schemaRegistryOptions) {
+		o.client = client
+	}
+}
+
+// WithSubjectNameStrategy sets the func returning the subject of the
+// schema the messages of a topic are encoded with.
+// Defaults to the topic name strategy, "<topic>-value".
+func WithSubjectNameStrategy(f func(topic string) string) SchemaRegistryOption {
+	return func(o *schemaRegistryOptions) {
+		o.subjectFunc = f
+	}
+}
+
+// WithSchemaRegistry decodes the Avro values of the messages, in the
+// Confluent wire format, using the schemas of the registry at registryURL.
+//
+// The payload of the events is the Avro record encoded as JSON.
+func WithSchemaRegistry(registryURL string, opts ...SchemaRegistryOption) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.unmarshaler = NewConfluentSchemaRegistryMarshaler(registryURL, opts...)
+	}
+}
+
+// WithPublisherSchemaRegistry encodes the JSON payload of the events as
+// Avro, in the Confluent wire format, using the latest schema of the
+// topic subject of the registry at registryURL.
+func WithPublisherSchemaRegistry(registryURL string, opts ...SchemaRegistryOption) PublisherOption {
+	return func(o *publisherOptions) {
+		o.marshaler = NewConfluentSchemaRegistryMarshaler(registryURL, opts...)
+	}
+}
+
+var (
+	_ kafka.Marshaler   = (*ConfluentSchemaRegistryMarshaler)(nil)
+	_ kafka.Unmarshaler = (*ConfluentSchemaRegistryMarshaler)(nil)
+)
+
+// ConfluentSchemaRegistryMarshaler converts the JSON payloads of the
+// watermill messages to Avro values in the Confluent wire format,
+// and back, interoperating with the Confluent serializers.
+//
+// The schemas are fetched from the registry when first used and cached.
+type ConfluentSchemaRegistryMarshaler struct {
+	url  string
+	opts schemaRegistryOptions
+
+	mu sync.Mutex
+	// the codecs by schema id.
+	codecs map[int]*goavro.Codec
+	// the latest schema ids by subject.
+	subjects map[string]int
+}
+
+// NewConfluentSchemaRegistryMarshaler returns a marshaler using the
+// schema registry at registryURL.
+func NewConfluentSchemaRegistryMarshaler(
+	registryURL string,
+	opts ...SchemaRegistryOption,
+) *ConfluentSchemaRegistryMarshaler {
+	o := schemaRegistryOptions{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		subjectFunc: func(topic string) string { return topic + "-value" },
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &ConfluentSchemaRegistryMarshaler{
+		url:      strings.TrimSuffix(registryURL, "/"),
+		opts:     o,
+		codecs:   make(map[int]*goavro.Codec),
+		subjects: make(map[string]int),
+	}
+}
+
+// Marshal encodes the JSON payload of msg with the latest schema
+// of the subject of topic.
+func (m *ConfluentSchemaRegistryMarshaler) Marshal(
+	topic string,
+	msg *message.Message,
+) (*sarama.ProducerMessage, error) {
+	id, codec, err := m.latestSchema(msg.Context(), m.opts.subjectFunc(topic))
+	if err != nil {
+		return nil, fmt.Errorf("get schema: %w", err)
+	}
+
+	native, _, err := codec.NativeFromTextual(msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode json payload: %w", err)
+	}
+
+	value := make([]byte, confluentHeaderSize, confluentHeaderSize+len(msg.Payload))
+
+	value[0] = confluentMagicByte
+	binary.BigEndian.PutUint32(value[1:confluentHeaderSize], uint32(id))
+
+	value, err = codec.BinaryFromNative(value, native)
+	if err != nil {
+		return nil, fmt.Errorf("encode avro: %w", err)
+	}
+
+	kafkaMsg, err := kafka.DefaultMarshaler{}.Marshal(topic, msg)
+	if err != nil {
+		return nil, fmt.Errorf("marshal message: %w", err)
+	}
+
+	kafkaMsg.Value = sarama.ByteEncoder(value)
+
+	return kafkaMsg, nil
+}
+
+// Unmarshal decodes the Avro value of kafkaMsg, with the schema of the
+// id it carries, into a JSON payload.
+func (m *ConfluentSchemaRegistryMarshaler) Unmarshal(kafkaMsg *sarama.ConsumerMessage) (*message.Message, error) {
+	if len(kafkaMsg.Value) < confluentHeaderSize || kafkaMsg.Value[0] != confluentMagicByte {
+		return nil, ErrInvalidWireFormat
+	}
+
+	id := int(binary.BigEndian.Uint32(kafkaMsg.Value[1:confluentHeaderSize]))
+
+	codec, err := m.schemaByID(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("get schema %d: %w", id, err)
+	}
+
+	native, _, err := codec.NativeFromBinary(kafkaMsg.Value[confluentHeaderSize:])
+	if err != nil {
+		return nil, fmt.Errorf("decode avro: %w", err)
+	}
+
+	payload, err := codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("encode json payload: %w", err)
+	}
+
+	decoded := *kafkaMsg
+	decoded.Value = payload
+
+	msg, err := kafka.DefaultMarshaler{}.Unmarshal(&decoded)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (m *ConfluentSchemaRegistryMarshaler) latestSchema(
+	ctx context.Context,
+	subject string,
+) (int, *goavro.Codec, error) {
+	m.mu.Lock()
+
+	if id, ok := m.subjects[subject]; ok {
+		codec := m.codecs[id]
+
+		m.mu.Unlock()
+
+		return id, codec, nil
+	}
+
+	m.mu.Unlock()
+
+	var resp struct {
+		ID     int    `json:"id"`
+		Schema string `json:"schema"`
+	}
+
+	if err := m.get(ctx, "/subjects/"+url.PathEscape(subject)+"/versions/latest", &resp); err != nil {
+		return 0, nil, err
+	}
+
+	codec, err := goavro.NewCodec(resp.Schema)
+	if err != nil {
+		return 0, nil, fmt.Errorf("new codec: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subjects[subject] = resp.ID
+	m.codecs[resp.ID] = codec
+
+	return resp.ID, codec, nil
+}
+
+func (m *ConfluentSchemaRegistryMarshaler) schemaByID(ctx context.Context, id int) (*goavro.Codec, error) {
+	m.mu.Lock()
+
+	if codec, ok := m.codecs[id]; ok {
+		m.mu.Unlock()
+
+		return codec, nil
+	}
+
+	m.mu.Unlock()
+
+	var resp struct {
+		Schema string `json:"schema"`
+	}
+
+	if err := m.get(ctx, "/schemas/ids/"+strconv.Itoa(id), &resp); err != nil {
+		return nil, err
+	}
+
+	codec, err := goavro.NewCodec(resp.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("new codec: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.codecs[id] = codec
+
+	return codec, nil
+}
+
+func (m *ConfluentSchemaRegistryMarshaler) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url+path, nil)
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+
+	req.Header.Set("Accept", schemaRegistryContentType)
+
+	if m.opts.username != "" {
+		req.SetBasicAuth(m.opts.username, m.opts.password)
+	}
+
+	res, err := m.opts.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: GET %s: %s", ErrSchemaRegistry, path, res.Status)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
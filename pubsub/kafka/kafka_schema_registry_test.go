@@ -0,0 +1,131 @@
+package kafka_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+)
+
+const userSchema = `{
+	"type": "record",
+	"name": "User",
+	"fields": [
+		{"name": "id", "type": "string"},
+		{"name": "age", "type": "int"}
+	]
+}`
+
+func TestConfluentSchemaRegistryMarshaler(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		requests.Add(1)
+
+		switch r.URL.Path {
+		case "/subjects/users-value/versions/latest":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 7, "schema": userSchema})
+
+		case "/schemas/ids/7":
+			_ = json.NewEncoder(w).Encode(map[string]any{"schema": userSchema})
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(registry.Close)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		producer := kafka.NewConfluentSchemaRegistryMarshaler(
+			registry.URL,
+			kafka.WithSchemaRegistryBasicAuth("user", "pass"),
+		)
+
+		msg := message.NewMessage("1", []byte(`{"id":"u1","age":30}`))
+		msg.Metadata.Set("type", "created")
+
+		kafkaMsg, err := producer.Marshal("users", msg)
+		i.NoErr(err)
+
+		value, err := kafkaMsg.Value.Encode()
+		i.NoErr(err)
+
+		// the value starts with the magic byte and the schema id.
+		i.Equal(byte(0), value[0])
+		i.Equal(uint32(7), binary.BigEndian.Uint32(value[1:5]))
+
+		// the schema is cached.
+		_, err = producer.Marshal("users", msg)
+		i.NoErr(err)
+		i.Equal(int32(1), requests.Load())
+
+		headers := make([]*sarama.RecordHeader, len(kafkaMsg.Headers))
+
+		for n := range kafkaMsg.Headers {
+			headers[n] = &kafkaMsg.Headers[n]
+		}
+
+		consumer := kafka.NewConfluentSchemaRegistryMarshaler(
+			registry.URL,
+			kafka.WithSchemaRegistryBasicAuth("user", "pass"),
+		)
+
+		decoded, err := consumer.Unmarshal(&sarama.ConsumerMessage{
+			Value:   value,
+			Headers: headers,
+		})
+		i.NoErr(err)
+		i.Equal("1", decoded.UUID)
+		i.Equal("created", decoded.Metadata.Get("type"))
+
+		var user struct {
+			ID  string `json:"id"`
+			Age int    `json:"age"`
+		}
+
+		i.NoErr(json.Unmarshal(decoded.Payload, &user))
+		i.Equal("u1", user.ID)
+		i.Equal(30, user.Age)
+	})
+
+	t.Run("InvalidWireFormat", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := kafka.NewConfluentSchemaRegistryMarshaler(registry.URL).
+			Unmarshal(&sarama.ConsumerMessage{Value: []byte(`{"id":"u1"}`)})
+		i.True(errors.Is(err, kafka.ErrInvalidWireFormat))
+	})
+
+	t.Run("RegistryError", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		_, err := kafka.NewConfluentSchemaRegistryMarshaler(registry.URL).
+			Marshal("users", message.NewMessage("1", []byte(`{"id":"u1","age":30}`)))
+		i.True(errors.Is(err, kafka.ErrSchemaRegistry))
+	})
+}
@@ -2,13 +2,17 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/purposeinplay/go-commons/pubsub"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -17,6 +21,36 @@ var _ pubsub.Subscriber[string, []byte] = (*Subscriber)(nil)
 // Subscriber represents a kafka subscriber.
 type Subscriber struct {
 	kafkaSubscriber *kafka.Subscriber
+	opts            subscriberOptions
+	logger          *zap.Logger
+
+	brokers       []string
+	saramaConfig  *sarama.Config
+	consumerGroup string
+	lag           *lagCollector
+	instruments   *subscriberInstruments
+}
+
+// SubscriberOption configures the kafka subscriber.
+type SubscriberOption func(*subscriberOptions)
+
+type subscriberOptions struct {
+	backoff       *pubsub.BackoffOptions
+	lagInterval   time.Duration
+	admin         ClusterAdmin
+	configOptions []configOption
+	meter         metric.Meter
+	unmarshaler   kafka.Unmarshaler
+}
+
+// WithProcessingBackoff delays the redelivery of the nacked events
+// following opts, up to opts.MaxAttempts times. Once the attempts are
+// exhausted the event is given up: it is acked, so it is not redelivered
+// anymore, and logged as an error.
+func WithProcessingBackoff(opts pubsub.BackoffOptions) SubscriberOption {
+	return func(o *subscriberOptions) {
+		o.backoff = &opts
+	}
 }
 
 // NewSubscriber creates a new kafka subscriber.
@@ -25,11 +59,33 @@ func NewSubscriber(
 	saramaConfig *sarama.Config,
 	brokers []string,
 	consumerGroup string,
+	opts ...SubscriberOption,
 ) (*Subscriber, error) {
+	o := subscriberOptions{
+		unmarshaler: kafka.DefaultMarshaler{},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	saramaConfig, err := applyConfigOptions(saramaConfig, kafka.DefaultSaramaSubscriberConfig, o.configOptions)
+	if err != nil {
+		return nil, fmt.Errorf("configure kafka subscriber: %w", err)
+	}
+
+	var instruments *subscriberInstruments
+
+	if o.meter != nil {
+		if instruments, err = newSubscriberInstruments(o.meter, consumerGroup); err != nil {
+			return nil, err
+		}
+	}
+
 	sub, err := kafka.NewSubscriber(
 		kafka.SubscriberConfig{
 			Brokers:               brokers,
-			Unmarshaler:           kafka.DefaultMarshaler{},
+			Unmarshaler:           o.unmarshaler,
 			OverwriteSaramaConfig: saramaConfig,
 			ConsumerGroup:         consumerGroup,
 		},
@@ -41,78 +97,245 @@ func NewSubscriber(
 
 	return &Subscriber{
 		kafkaSubscriber: sub,
+		opts:            o,
+		logger:          logger,
+		brokers:         brokers,
+		saramaConfig:    saramaConfig,
+		consumerGroup:   consumerGroup,
+		instruments:     instruments,
 	}, nil
 }
 
-// Subscribe subscribes to a kafka topic.
+// Subscribe subscribes to one or more kafka topics, merging
+// their messages into a single subscription.
 func (s Subscriber) Subscribe(channels ...string) (pubsub.Subscription[string, []byte], error) {
-	if len(channels) != 1 {
-		return nil, pubsub.ErrExactlyOneChannelAllowed
+	if len(channels) == 0 {
+		return nil, pubsub.ErrAtLeastOneChannelRequired
 	}
 
-	mes, err := s.kafkaSubscriber.Subscribe(context.Background(), channels[0])
-	if err != nil {
-		return nil, fmt.Errorf("subscribe: %w", err)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mesChs := make([]<-chan *message.Message, len(channels))
+
+	for i, channel := range channels {
+		mesCh, err := s.kafkaSubscriber.Subscribe(ctx, channel)
+		if err != nil {
+			cancel()
+
+			return nil, fmt.Errorf("subscribe to %q: %w", channel, err)
+		}
+
+		mesChs[i] = mesCh
 	}
 
-	return newSubscription(mes), nil
+	return newSubscription(channels, mesChs, cancel, s.opts.backoff, s.instruments, s.logger), nil
 }
 
-// Close closes the kafka subscriber.
+// Close closes the kafka subscriber, and stops its lag collector.
 func (s Subscriber) Close() error {
-	return s.kafkaSubscriber.Close()
+	var errs error
+
+	if s.lag != nil {
+		if err := s.lag.close(); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("close lag collector: %w", err))
+		}
+	}
+
+	if err := s.kafkaSubscriber.Close(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	return errs
 }
 
 var _ pubsub.Subscription[string, []byte] = (*Subscription)(nil)
 
-// Subscription represents a stream of events published to a kafka topic.
+// Subscription represents a stream of events published to kafka topics.
 type Subscription struct {
 	eventCh chan pubsub.Event[string, []byte]
 	closeCh chan struct{}
+
+	// cancels the underlying topic subscriptions.
+	cancel context.CancelFunc
+
+	// the processing attempts of the nacked messages, by message uuid.
+	attempts sync.Map
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
 }
 
-// newSubscription creates a new subscription.
-// nolint: gocognit
+// newSubscription creates a new subscription, copying the messages of
+// each topic subscription into the shared event channel.
+//
+// The instruments, if not nil, observe the events of each topic.
 func newSubscription(
-	mesCh <-chan *message.Message,
+	topics []string,
+	mesChs []<-chan *message.Message,
+	cancel context.CancelFunc,
+	backoff *pubsub.BackoffOptions,
+	instruments *subscriberInstruments,
+	logger *zap.Logger,
 ) *Subscription {
-	eventCh := make(chan pubsub.Event[string, []byte])
-	closeCh := make(chan struct{})
+	sub := &Subscription{
+		eventCh: make(chan pubsub.Event[string, []byte], len(mesChs)),
+		closeCh: make(chan struct{}),
+		cancel:  cancel,
+	}
 
-	go func() {
-		for {
-			select {
-			case <-closeCh:
+	sub.wg.Add(len(mesChs))
+
+	for i, mesCh := range mesChs {
+		go func(topic string, mesCh <-chan *message.Message) {
+			defer sub.wg.Done()
+
+			sub.forward(topic, mesCh, backoff, instruments, logger)
+		}(topics[i], mesCh)
+	}
+
+	return sub
+}
+
+// forward sends the messages of mesCh, subscribed to topic, as events
+// until the subscription is closed.
+func (s *Subscription) forward(
+	topic string,
+	mesCh <-chan *message.Message,
+	backoff *pubsub.BackoffOptions,
+	instruments *subscriberInstruments,
+	logger *zap.Logger,
+) {
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case mes, ok := <-mesCh:
+			if !ok {
+				slog.Info("sub closed")
 				return
-			case mes, ok := <-mesCh:
-				if !ok {
-					slog.Info("sub closed")
-					return
+			}
+
+			headers := make(map[string]string, len(mes.Metadata))
+
+			for k, v := range mes.Metadata {
+				if k != "type" {
+					headers[k] = v
 				}
+			}
+
+			var ack pubsub.Acknowledger = mes
 
-				eventCh <- pubsub.Event[string, []byte]{
-					Type:    mes.Metadata.Get("type"),
-					Payload: mes.Payload,
+			if backoff != nil {
+				ack = &backoffAcknowledger{
+					mes:      mes,
+					topic:    topic,
+					backoff:  *backoff,
+					attempts: &s.attempts,
+					closeCh:  s.closeCh,
+					logger:   logger,
 				}
 			}
-		}
-	}()
 
-	return &Subscription{
-		eventCh: eventCh,
-		closeCh: closeCh,
+			if instruments != nil {
+				ack = instruments.observe(topic, ack)
+			}
+
+			event := pubsub.Event[string, []byte]{
+				Type:         mes.Metadata.Get("type"),
+				Payload:      mes.Payload,
+				Headers:      headers,
+				ID:           mes.UUID,
+				ReceivedAt:   time.Now(),
+				Acknowledger: ack,
+			}
+
+			select {
+			case s.eventCh <- event:
+			case <-s.closeCh:
+				mes.Nack()
+				return
+			}
+		}
 	}
 }
 
 // C returns a receive-only go channel of events published.
-func (s Subscription) C() <-chan pubsub.Event[string, []byte] {
+func (s *Subscription) C() <-chan pubsub.Event[string, []byte] {
 	return s.eventCh
 }
 
-// Close closes the subscription.
-func (s Subscription) Close() error {
-	close(s.eventCh)
-	close(s.closeCh)
+// Close cancels the topic subscriptions and closes the event channel,
+// nacking the buffered events that were not received.
+// It can be called multiple times.
+func (s *Subscription) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		s.cancel()
+		s.wg.Wait()
+
+		close(s.eventCh)
+
+		for e := range s.eventCh {
+			e.Nack()
+		}
+	})
 
 	return nil
 }
+
+// backoffAcknowledger delays the nacks of a message, so its
+// redelivery follows the backoff.
+type backoffAcknowledger struct {
+	mes      *message.Message
+	topic    string
+	backoff  pubsub.BackoffOptions
+	attempts *sync.Map
+	closeCh  <-chan struct{}
+	logger   *zap.Logger
+}
+
+// Ack acknowledges the message and forgets its attempts.
+func (a *backoffAcknowledger) Ack() bool {
+	a.attempts.Delete(a.mes.UUID)
+
+	return a.mes.Ack()
+}
+
+// Nack nacks the message after the backoff delay of the attempt,
+// in the background. Once the attempts are exhausted the message is
+// acked instead, so it is not redelivered, and logged.
+func (a *backoffAcknowledger) Nack() bool {
+	attempt := 1
+
+	if v, ok := a.attempts.Load(a.mes.UUID); ok {
+		attempt = v.(int) + 1
+	}
+
+	if attempt >= a.backoff.MaxAttempts {
+		a.attempts.Delete(a.mes.UUID)
+
+		if a.logger != nil {
+			a.logger.Error(
+				"processing attempts exhausted, giving up message",
+				zap.String("topic", a.topic),
+				zap.String("message_id", a.mes.UUID),
+				zap.Int("attempts", attempt),
+			)
+		}
+
+		return a.mes.Ack()
+	}
+
+	a.attempts.Store(a.mes.UUID, attempt)
+
+	go func() {
+		select {
+		case <-time.After(a.backoff.Next(attempt)):
+			a.mes.Nack()
+
+		case <-a.closeCh:
+		}
+	}()
+
+	return true
+}
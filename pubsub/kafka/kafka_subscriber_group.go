@@ -0,0 +1,286 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrDuplicateTopic is returned when more than one SubscriberConfig
+// of a SubscriberGroup targets the same topic.
+var ErrDuplicateTopic = errors.New("duplicate topic")
+
+// ErrSubscriberGroupClosed is returned by SubscriberGroup.Lag
+// once the group is closed.
+var ErrSubscriberGroupClosed = errors.New("subscriber group closed")
+
+// SubscriberConfig configures a subscriber of a SubscriberGroup.
+type SubscriberConfig struct {
+	Logger        *zap.Logger
+	SaramaConfig  *sarama.Config
+	Brokers       []string
+	Topic         string
+	ConsumerGroup string
+
+	// ClusterAdmin is the admin the lag of the subscriber is computed
+	// with, see SubscriberGroup.Lag. Optional.
+	ClusterAdmin ClusterAdmin
+}
+
+// MultiError groups the errors returned by several operations.
+type MultiError []error
+
+func (m MultiError) Error() string {
+	msgs := make([]string, len(m))
+
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the grouped errors.
+func (m MultiError) Unwrap() []error {
+	return m
+}
+
+type groupMember struct {
+	config SubscriberConfig
+
+	// the subscriber of the topic, a *Subscriber outside of tests.
+	subscriber message.Subscriber
+
+	// the admin the lag is computed with, and its client if owned.
+	admin  ClusterAdmin
+	client sarama.Client
+}
+
+// memberSubscriber adapts a Subscriber to message.Subscriber.
+type memberSubscriber struct {
+	sub *Subscriber
+}
+
+func (m memberSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	return m.sub.kafkaSubscriber.Subscribe(ctx, topic)
+}
+
+func (m memberSubscriber) Close() error {
+	return m.sub.Close()
+}
+
+// SubscriberGroup coordinates a subscriber, each with its own
+// consumer group, for every topic.
+type SubscriberGroup struct {
+	// guards the lazy creation of the cluster admins, and closed.
+	mu      sync.Mutex
+	closed  bool
+	members []*groupMember
+}
+
+// NewSubscriberGroup creates a subscriber for each config.
+func NewSubscriberGroup(configs []SubscriberConfig) (*SubscriberGroup, error) {
+	group := new(SubscriberGroup)
+
+	topics := make(map[string]struct{}, len(configs))
+
+	for _, cfg := range configs {
+		if _, ok := topics[cfg.Topic]; ok {
+			_ = group.Close()
+
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateTopic, cfg.Topic)
+		}
+
+		topics[cfg.Topic] = struct{}{}
+
+		sub, err := NewSubscriber(cfg.Logger, cfg.SaramaConfig, cfg.Brokers, cfg.ConsumerGroup)
+		if err != nil {
+			_ = group.Close()
+
+			return nil, fmt.Errorf("new subscriber for topic %s: %w", cfg.Topic, err)
+		}
+
+		group.members = append(group.members, &groupMember{
+			config:     cfg,
+			subscriber: memberSubscriber{sub: sub},
+			admin:      cfg.ClusterAdmin,
+		})
+	}
+
+	return group, nil
+}
+
+// Run consumes all the topics concurrently and passes the messages
+// to the handler until the context is canceled.
+//
+// A message is acked when the handler succeeds. Otherwise it is nacked
+// and Run returns the handler error, stopping all the subscribers.
+//
+// Run doesn't return before all the consumers stopped: the handler
+// isn't called once Run returned, including when subscribing to
+// one of the topics fails.
+func (g *SubscriberGroup) Run(
+	ctx context.Context,
+	handler func(topic string, msg []byte) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	eg, egCtx := errgroup.WithContext(ctx)
+
+	for _, m := range g.members {
+		topic := m.config.Topic
+
+		mesCh, err := m.subscriber.Subscribe(egCtx, topic)
+		if err != nil {
+			cancel()
+
+			return errors.Join(
+				fmt.Errorf("subscribe to topic %s: %w", topic, err),
+				eg.Wait(),
+			)
+		}
+
+		eg.Go(func() error {
+			return consume(egCtx, topic, mesCh, handler)
+		})
+	}
+
+	return eg.Wait()
+}
+
+func consume(
+	ctx context.Context,
+	topic string,
+	mesCh <-chan *message.Message,
+	handler func(topic string, msg []byte) error,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case mes, ok := <-mesCh:
+			if !ok {
+				return nil
+			}
+
+			// both cases may be ready, the messages received
+			// once the context is done are left unhandled.
+			if ctx.Err() != nil {
+				mes.Nack()
+
+				return nil
+			}
+
+			if err := handler(topic, mes.Payload); err != nil {
+				mes.Nack()
+
+				return fmt.Errorf("handle message from topic %s: %w", topic, err)
+			}
+
+			mes.Ack()
+		}
+	}
+}
+
+// Lag returns the number of messages not consumed yet by each
+// subscriber, keyed by topic.
+//
+// Partitions without a committed offset count all of their messages.
+//
+// Unless SubscriberConfig.ClusterAdmin is set, a kafka client is
+// connected to the brokers of each subscriber on the first call,
+// then reused until the group is closed.
+//
+// It returns ErrSubscriberGroupClosed once the group is closed.
+func (g *SubscriberGroup) Lag(ctx context.Context) (map[string]int64, error) {
+	lags := make(map[string]int64, len(g.members))
+
+	for _, m := range g.members {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		admin, err := g.clusterAdmin(m)
+		if err != nil {
+			return nil, fmt.Errorf("lag for topic %s: %w", m.config.Topic, err)
+		}
+
+		partitionLags, err := consumerGroupLag(admin, m.config.ConsumerGroup, m.config.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("lag for topic %s: %w", m.config.Topic, err)
+		}
+
+		lags[m.config.Topic] = totalLag(partitionLags)
+	}
+
+	return lags, nil
+}
+
+func (g *SubscriberGroup) clusterAdmin(m *groupMember) (ClusterAdmin, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return nil, ErrSubscriberGroupClosed
+	}
+
+	if m.admin != nil {
+		return m.admin, nil
+	}
+
+	client, err := sarama.NewClient(m.config.Brokers, m.config.SaramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	admin, err := NewClusterAdmin(client)
+	if err != nil {
+		return nil, errors.Join(err, client.Close())
+	}
+
+	m.admin = admin
+	m.client = client
+
+	return admin, nil
+}
+
+// Close closes all the subscribers of the group, and the kafka
+// clients created by Lag. Lag returns ErrSubscriberGroupClosed
+// once Close is called.
+func (g *SubscriberGroup) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.closed = true
+
+	var errs MultiError
+
+	for _, m := range g.members {
+		if err := m.subscriber.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close subscriber for topic %s: %w", m.config.Topic, err))
+		}
+
+		if m.client != nil {
+			if err := m.client.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close client for topic %s: %w", m.config.Topic, err))
+			}
+
+			m.admin, m.client = nil, nil
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
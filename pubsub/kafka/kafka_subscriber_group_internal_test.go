@@ -0,0 +1,290 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/matryer/is"
+)
+
+func TestSubscriberGroupRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Route", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		a, b := newFakeSubscriber(), newFakeSubscriber()
+
+		group := newFakeGroup(map[string]*fakeSubscriber{"a": a, "b": b})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			mu       sync.Mutex
+			received = make(map[string]string)
+		)
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- group.Run(ctx, func(topic string, msg []byte) error {
+				mu.Lock()
+				defer mu.Unlock()
+
+				received[topic] = string(msg)
+
+				return nil
+			})
+		}()
+
+		mesA := message.NewMessage("1", []byte("to a"))
+		mesB := message.NewMessage("2", []byte("to b"))
+
+		a.messages <- mesA
+		b.messages <- mesB
+
+		<-mesA.Acked()
+		<-mesB.Acked()
+
+		cancel()
+
+		i.NoErr(<-errCh)
+		i.Equal(map[string]string{"a": "to a", "b": "to b"}, received)
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		a, b := newFakeSubscriber(), newFakeSubscriber()
+
+		group := newFakeGroup(map[string]*fakeSubscriber{"a": a, "b": b})
+
+		errHandler := errors.New("handler error")
+
+		errCh := make(chan error, 1)
+
+		go func() {
+			errCh <- group.Run(context.Background(), func(string, []byte) error {
+				return errHandler
+			})
+		}()
+
+		mes := message.NewMessage("1", []byte("to a"))
+
+		a.messages <- mes
+
+		<-mes.Nacked()
+
+		i.True(errors.Is(<-errCh, errHandler))
+
+		// the other subscribers are stopped.
+		i.True(b.ctx().Err() != nil)
+	})
+
+	t.Run("SubscribeError", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		a, b := newFakeSubscriber(), newFakeSubscriber()
+
+		errSubscribe := errors.New("subscribe error")
+		b.subscribeErr = errSubscribe
+
+		group := &SubscriberGroup{members: []*groupMember{
+			{config: SubscriberConfig{Topic: "a"}, subscriber: a},
+			{config: SubscriberConfig{Topic: "b"}, subscriber: b},
+		}}
+
+		err := group.Run(context.Background(), func(string, []byte) error {
+			t.Error("unexpected handler call")
+
+			return nil
+		})
+		i.True(errors.Is(err, errSubscribe))
+
+		// the subscribers already started are stopped before Run returns.
+		i.True(a.ctx().Err() != nil)
+	})
+}
+
+func TestSubscriberGroupClose(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	a, b, c := newFakeSubscriber(), newFakeSubscriber(), newFakeSubscriber()
+
+	errA, errC := errors.New("close a"), errors.New("close c")
+
+	a.closeErr = errA
+	c.closeErr = errC
+
+	err := newFakeGroup(map[string]*fakeSubscriber{"a": a, "b": b, "c": c}).Close()
+
+	var multiErr MultiError
+
+	i.True(errors.As(err, &multiErr))
+	i.Equal(2, len(multiErr))
+	i.True(errors.Is(err, errA))
+	i.True(errors.Is(err, errC))
+
+	i.True(a.closed && b.closed && c.closed)
+}
+
+func TestSubscriberGroupLag(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	admin := &lagClusterAdmin{
+		newest: map[string]map[int32]int64{
+			"a": {0: 10, 1: 5},
+			"b": {0: 3},
+		},
+		committed: map[string]map[int32]int64{
+			// partition 1 has no committed offset.
+			"a": {0: 7, 1: -1},
+			// committed after the newest offset was fetched.
+			"b": {0: 5},
+		},
+	}
+
+	group := &SubscriberGroup{members: []*groupMember{
+		{
+			config:     SubscriberConfig{Topic: "a", ConsumerGroup: "group-a"},
+			subscriber: newFakeSubscriber(),
+			admin:      admin,
+		},
+		{
+			config:     SubscriberConfig{Topic: "b", ConsumerGroup: "group-b"},
+			subscriber: newFakeSubscriber(),
+			admin:      admin,
+		},
+	}}
+
+	lags, err := group.Lag(context.Background())
+	i.NoErr(err)
+	i.Equal(map[string]int64{"a": 8, "b": 0}, lags)
+	i.Equal([]string{"group-a", "group-b"}, admin.groups)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = group.Lag(ctx)
+	i.True(errors.Is(err, context.Canceled))
+
+	// no client is connected once the group is closed.
+	i.NoErr(group.Close())
+
+	_, err = group.Lag(context.Background())
+	i.True(errors.Is(err, ErrSubscriberGroupClosed))
+}
+
+func newFakeGroup(subscribers map[string]*fakeSubscriber) *SubscriberGroup {
+	group := new(SubscriberGroup)
+
+	for topic, sub := range subscribers {
+		group.members = append(group.members, &groupMember{
+			config:     SubscriberConfig{Topic: topic},
+			subscriber: sub,
+		})
+	}
+
+	return group
+}
+
+var _ message.Subscriber = (*fakeSubscriber)(nil)
+
+type fakeSubscriber struct {
+	messages     chan *message.Message
+	subscribeErr error
+	closeErr     error
+
+	mu           sync.Mutex
+	subscribeCtx context.Context
+	closed       bool
+}
+
+func newFakeSubscriber() *fakeSubscriber {
+	return &fakeSubscriber{messages: make(chan *message.Message)}
+}
+
+func (s *fakeSubscriber) Subscribe(ctx context.Context, _ string) (<-chan *message.Message, error) {
+	if s.subscribeErr != nil {
+		return nil, s.subscribeErr
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribeCtx = ctx
+
+	return s.messages, nil
+}
+
+// ctx returns the context the subscriber was subscribed with.
+func (s *fakeSubscriber) ctx() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.subscribeCtx
+}
+
+func (s *fakeSubscriber) Close() error {
+	s.closed = true
+
+	return s.closeErr
+}
+
+var _ ClusterAdmin = (*lagClusterAdmin)(nil)
+
+type lagClusterAdmin struct {
+	newest, committed map[string]map[int32]int64
+
+	groups []string
+}
+
+func (a *lagClusterAdmin) DescribeTopics(topics []string) ([]*sarama.TopicMetadata, error) {
+	metadata := make([]*sarama.TopicMetadata, len(topics))
+
+	for n, topic := range topics {
+		metadata[n] = &sarama.TopicMetadata{Name: topic}
+
+		for p := range a.newest[topic] {
+			metadata[n].Partitions = append(metadata[n].Partitions, &sarama.PartitionMetadata{ID: p})
+		}
+	}
+
+	return metadata, nil
+}
+
+func (a *lagClusterAdmin) ListConsumerGroupOffsets(
+	group string,
+	topicPartitions map[string][]int32,
+) (*sarama.OffsetFetchResponse, error) {
+	a.groups = append(a.groups, group)
+
+	resp := new(sarama.OffsetFetchResponse)
+
+	for topic, partitions := range topicPartitions {
+		for _, p := range partitions {
+			resp.AddBlock(topic, p, &sarama.OffsetFetchResponseBlock{Offset: a.committed[topic][p]})
+		}
+	}
+
+	return resp, nil
+}
+
+func (a *lagClusterAdmin) GetOffset(topic string, partitionID int32, _ int64) (int64, error) {
+	return a.newest[topic][partitionID], nil
+}
@@ -0,0 +1,108 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSubscriptionProcessingBackoff(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	core, logs := observer.New(zap.ErrorLevel)
+
+	mesCh := make(chan *message.Message)
+
+	sub := newSubscription(
+		[]string{"topic"},
+		[]<-chan *message.Message{mesCh},
+		func() {},
+		&pubsub.BackoffOptions{
+			InitialInterval: 20 * time.Millisecond,
+			Multiplier:      2,
+			MaxAttempts:     3,
+		},
+		nil,
+		zap.New(core),
+	)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	// deliver sends a new incarnation of the message with id,
+	// as the kafka subscriber does on redelivery.
+	deliver := func(id string) (*message.Message, pubsub.Event[string, []byte]) {
+		mes := message.NewMessage(id, []byte("payload"))
+
+		mesCh <- mes
+
+		return mes, <-sub.C()
+	}
+
+	attempts := func(id string) (int, bool) {
+		v, ok := sub.attempts.Load(id)
+		if !ok {
+			return 0, false
+		}
+
+		return v.(int), true
+	}
+
+	// the redeliveries are delayed following the backoff.
+	for attempt, delay := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond} {
+		mes, event := deliver("1")
+
+		start := time.Now()
+
+		i.True(event.Nack())
+
+		<-mes.Nacked()
+
+		i.True(time.Since(start) >= delay)
+
+		n, ok := attempts("1")
+		i.True(ok)
+		i.Equal(attempt+1, n)
+	}
+
+	// once the attempts are exhausted, the message is given up.
+	mes, event := deliver("1")
+
+	i.True(event.Nack())
+
+	select {
+	case <-mes.Acked():
+	case <-mes.Nacked():
+		t.Fatal("exhausted message nacked")
+	}
+
+	_, ok := attempts("1")
+	i.True(!ok)
+
+	i.Equal(1, logs.FilterField(zap.String("message_id", "1")).Len())
+
+	// the attempts are forgotten once the message is acked.
+	mes, event = deliver("2")
+
+	i.True(event.Nack())
+
+	<-mes.Nacked()
+
+	_, ok = attempts("2")
+	i.True(ok)
+
+	mes, event = deliver("2")
+
+	i.True(event.Ack())
+
+	<-mes.Acked()
+
+	_, ok = attempts("2")
+	i.True(!ok)
+}
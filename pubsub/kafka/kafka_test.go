@@ -2,6 +2,7 @@ package kafka_test
 
 import (
 	"context"
+	"errors"
 	"os"
 	"sync"
 	"testing"
@@ -92,7 +93,9 @@ func TestPubSub(t *testing.T) {
 		defer wg.Done()
 
 		receivedMes := <-sub1.C()
-		is.Equal(receivedMes, mes)
+		is.Equal(receivedMes.Type, mes.Type)
+		is.Equal(receivedMes.Payload, mes.Payload)
+		is.True(receivedMes.Ack())
 
 		t.Logf("sub1 received the message in %s", time.Since(now))
 	}()
@@ -101,7 +104,9 @@ func TestPubSub(t *testing.T) {
 		defer wg.Done()
 
 		receivedMes := <-sub2.C()
-		is.Equal(receivedMes, mes)
+		is.Equal(receivedMes.Type, mes.Type)
+		is.Equal(receivedMes.Payload, mes.Payload)
+		is.True(receivedMes.Ack())
 
 		t.Logf("sub2 received the message in %s", time.Since(now))
 	}()
@@ -225,3 +230,91 @@ func TestConsumerGroups(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestPublishKeyed(t *testing.T) {
+	var (
+		username  = os.Getenv("KAFKA_USERNAME")
+		password  = os.Getenv("KAFKA_PASSWORD")
+		brokerURL = os.Getenv("KAFKA_BROKER_URL")
+		topic     = os.Getenv("KAFKA_TEST_TOPIC")
+	)
+
+	if brokerURL == "" {
+		t.Skip("KAFKA_BROKER_URL is not set")
+	}
+
+	i := is.New(t)
+
+	suber, err := kafka.NewSubscriber(
+		zap.NewNop(),
+		kafka.NewSASLSubscriberConfig(username, password),
+		[]string{brokerURL},
+		"",
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(suber.Close()) })
+
+	pub, err := kafka.NewPublisher(
+		zap.NewNop(),
+		kafka.NewSASLPublisherConfig(username, password),
+		[]string{brokerURL},
+		kafka.WithDefaultKeyExtractor(func(payload []byte) string {
+			return string(payload)
+		}),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(pub.Close()) })
+
+	sub, err := suber.Subscribe(topic)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	keyed := []string{"keyed-0", "keyed-1", "keyed-2"}
+
+	for _, payload := range keyed {
+		i.NoErr(pub.PublishKeyed(context.Background(), topic, "user-1", pubsub.Event[string, []byte]{
+			Type:    "test",
+			Payload: []byte(payload),
+		}))
+	}
+
+	i.NoErr(pub.Publish(pubsub.Event[string, []byte]{
+		Type:    "test",
+		Payload: []byte("extracted"),
+	}, topic))
+
+	// the order is only guaranteed between the messages with
+	// the same key, which go to the same partition.
+	var received []string
+
+	for range len(keyed) + 1 {
+		select {
+		case e := <-sub.C():
+			i.True(e.Ack())
+
+			if string(e.Payload) == "extracted" {
+				i.Equal("extracted", e.Headers[kafka.PartitionKeyMetadataKey])
+
+				continue
+			}
+
+			i.Equal("user-1", e.Headers[kafka.PartitionKeyMetadataKey])
+
+			received = append(received, string(e.Payload))
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	i.Equal(keyed, received)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = pub.PublishKeyed(ctx, topic, "user-1", pubsub.Event[string, []byte]{Type: "test"})
+	i.True(errors.Is(err, context.Canceled))
+}
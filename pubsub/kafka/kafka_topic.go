@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// topicCreator is the subset of the sarama.ClusterAdmin operations
+// used to create the topics.
+type topicCreator interface {
+	CreateTopic(topic string, detail *sarama.TopicDetail, validateOnly bool) error
+}
+
+// EnsureTopic creates the topic with the given number of partitions,
+// replication factor and config entries, unless it already exists.
+//
+// The topic is created with the admin set by WithClusterAdmin, if it
+// can create topics, or with an admin connected to the subscriber's
+// brokers otherwise.
+func (s *Subscriber) EnsureTopic(
+	ctx context.Context,
+	topic string,
+	partitions int32,
+	replication int16,
+	config map[string]string,
+) error {
+	if creator, ok := s.opts.admin.(topicCreator); ok {
+		return ensureTopic(ctx, creator, topic, partitions, replication, config)
+	}
+
+	return ensureTopicWithBrokers(ctx, s.brokers, s.saramaConfig, topic, partitions, replication, config)
+}
+
+// EnsureTopic creates the topic with the given number of partitions,
+// replication factor and config entries, unless it already exists.
+func (p *Publisher) EnsureTopic(
+	ctx context.Context,
+	topic string,
+	partitions int32,
+	replication int16,
+	config map[string]string,
+) error {
+	return ensureTopicWithBrokers(ctx, p.brokers, p.saramaConfig, topic, partitions, replication, config)
+}
+
+// ensureTopicWithBrokers creates the topic with a short-lived admin
+// connected to brokers.
+func ensureTopicWithBrokers(
+	ctx context.Context,
+	brokers []string,
+	saramaConfig *sarama.Config,
+	topic string,
+	partitions int32,
+	replication int16,
+	config map[string]string,
+) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	admin, err := sarama.NewClusterAdmin(brokers, saramaConfig)
+	if err != nil {
+		return fmt.Errorf("new cluster admin: %w", err)
+	}
+
+	defer func() {
+		if closeErr := admin.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("close cluster admin: %w", closeErr))
+		}
+	}()
+
+	return ensureTopic(ctx, admin, topic, partitions, replication, config)
+}
+
+// ensureTopic creates the topic, ignoring sarama.ErrTopicAlreadyExists.
+//
+// sarama doesn't support cancellation, the creation is left running
+// in the background if ctx is done first.
+func ensureTopic(
+	ctx context.Context,
+	creator topicCreator,
+	topic string,
+	partitions int32,
+	replication int16,
+	config map[string]string,
+) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	entries := make(map[string]*string, len(config))
+
+	for k, v := range config {
+		entries[k] = &v
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- creator.CreateTopic(topic, &sarama.TopicDetail{
+			NumPartitions:     partitions,
+			ReplicationFactor: replication,
+			ConfigEntries:     entries,
+		}, false)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case err := <-errCh:
+		if err == nil || errors.Is(err, sarama.ErrTopicAlreadyExists) {
+			return nil
+		}
+
+		return fmt.Errorf("create topic %q: %w", topic, err)
+	}
+}
@@ -0,0 +1,67 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub/kafka"
+	"go.uber.org/zap"
+)
+
+func TestSubscriberEnsureTopic(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	admin := &topicClusterAdmin{topics: make(map[string]*sarama.TopicDetail)}
+
+	suber, err := kafka.NewSubscriber(
+		zap.NewNop(),
+		nil,
+		[]string{"localhost:9092"},
+		"group",
+		kafka.WithClusterAdmin(admin),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(suber.Close()) })
+
+	ctx := context.Background()
+
+	i.NoErr(suber.EnsureTopic(ctx, "a", 3, 2, map[string]string{"retention.ms": "1000"}))
+
+	detail := admin.topics["a"]
+	i.Equal(int32(3), detail.NumPartitions)
+	i.Equal(int16(2), detail.ReplicationFactor)
+	i.Equal("1000", *detail.ConfigEntries["retention.ms"])
+
+	// the existing topic is left as is.
+	i.NoErr(suber.EnsureTopic(ctx, "a", 1, 1, nil))
+	i.Equal(int32(3), admin.topics["a"].NumPartitions)
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	i.True(errors.Is(suber.EnsureTopic(cancelledCtx, "b", 1, 1, nil), context.Canceled))
+	i.True(admin.topics["b"] == nil)
+}
+
+// topicClusterAdmin creates the topics in memory.
+type topicClusterAdmin struct {
+	groupClusterAdmin
+
+	topics map[string]*sarama.TopicDetail
+}
+
+func (a *topicClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, _ bool) error {
+	if _, ok := a.topics[topic]; ok {
+		return &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}
+	}
+
+	a.topics[topic] = detail
+
+	return nil
+}
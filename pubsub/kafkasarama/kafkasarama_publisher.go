@@ -63,6 +63,13 @@ func (p Publisher) Publish(event pubsub.Event[string, []byte], channels ...strin
 		Value: sarama.ByteEncoder(event.Payload),
 	}
 
+	for k, v := range event.Headers {
+		mes.Headers = append(mes.Headers, sarama.RecordHeader{
+			Key:   []byte(k),
+			Value: []byte(v),
+		})
+	}
+
 	if _, _, err := p.syncProducer.SendMessage(mes); err != nil {
 		return fmt.Errorf("publish: %w", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/purposeinplay/go-commons/pubsub"
@@ -128,17 +129,24 @@ func consumePartition(
 		case m := <-partitionConsumer.Messages():
 			var typ string
 
+			headers := make(map[string]string, len(m.Headers))
+
 			for _, h := range m.Headers {
 				if bytes.Equal(h.Key, []byte("type")) {
 					typ = string(h.Value)
 
-					break
+					continue
 				}
+
+				headers[string(h.Key)] = string(h.Value)
 			}
 
 			eventCh <- pubsub.Event[string, []byte]{
-				Type:    typ,
-				Payload: m.Value,
+				Type:       typ,
+				Payload:    m.Value,
+				Headers:    headers,
+				ID:         fmt.Sprintf("%s/%d/%d", m.Topic, m.Partition, m.Offset),
+				ReceivedAt: time.Now(),
 			}
 
 		case err := <-partitionConsumer.Errors():
@@ -0,0 +1,47 @@
+package nats
+
+import (
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/nats-io/nats.go"
+)
+
+// Option configures the nats publisher and subscriber.
+type Option func(*options)
+
+type options struct {
+	natsOptions []nats.Option
+	jetStream   wmnats.JetStreamConfig
+}
+
+// WithNatsOptions configures the connection to the nats server.
+func WithNatsOptions(opts ...nats.Option) Option {
+	return func(o *options) {
+		o.natsOptions = append(o.natsOptions, opts...)
+	}
+}
+
+// WithJetStream publishes and subscribes through JetStream instead of
+// core nats, for at-least-once delivery. The streams of the subjects
+// are created if they don't exist yet.
+//
+// By default, the events are delivered at most once.
+func WithJetStream(opts ...nats.JSOpt) Option {
+	return func(o *options) {
+		o.jetStream = wmnats.JetStreamConfig{
+			AutoProvision:  true,
+			ConnectOptions: opts,
+		}
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		jetStream: wmnats.JetStreamConfig{Disabled: true},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
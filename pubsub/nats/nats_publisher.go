@@ -0,0 +1,75 @@
+package nats
+
+import (
+	"fmt"
+
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"go.uber.org/zap"
+)
+
+var _ pubsub.Publisher[string, []byte] = (*Publisher)(nil)
+
+// Publisher represents a nats publisher.
+type Publisher struct {
+	natsPublisher *wmnats.Publisher
+}
+
+// NewPublisher creates a new nats publisher connected to the server at url.
+func NewPublisher(
+	url string,
+	logger *zap.Logger,
+	opts ...Option,
+) (*Publisher, error) {
+	o := newOptions(opts)
+
+	pub, err := wmnats.NewPublisher(
+		wmnats.PublisherConfig{
+			URL:         url,
+			NatsOptions: o.natsOptions,
+			JetStream:   o.jetStream,
+		},
+		newLoggerAdapter(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new nats publisher: %w", err)
+	}
+
+	return &Publisher{
+		natsPublisher: pub,
+	}, nil
+}
+
+// Publish publishes an event to a nats subject.
+//
+// The payload is wrapped in a watermill message, carrying the type and
+// the headers of the event as metadata.
+func (p Publisher) Publish(event pubsub.Event[string, []byte], channels ...string) error {
+	if len(channels) != 1 {
+		return pubsub.ErrExactlyOneChannelAllowed
+	}
+
+	mes := message.NewMessage(uuid.New().String(), event.Payload)
+
+	for k, v := range event.Headers {
+		mes.Metadata.Set(k, v)
+	}
+
+	mes.Metadata.Set("type", event.Type)
+
+	if err := p.natsPublisher.Publish(
+		channels[0],
+		mes,
+	); err != nil {
+		return fmt.Errorf("publish: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the nats publisher.
+func (p Publisher) Close() error {
+	return p.natsPublisher.Close()
+}
@@ -0,0 +1,177 @@
+package nats
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	wmnats "github.com/ThreeDotsLabs/watermill-nats/v2/pkg/nats"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"go.uber.org/zap"
+)
+
+var _ pubsub.Subscriber[string, []byte] = (*Subscriber)(nil)
+
+// Subscriber represents a nats subscriber.
+type Subscriber struct {
+	natsSubscriber *wmnats.Subscriber
+}
+
+// NewSubscriber creates a new nats subscriber connected to the server at url.
+func NewSubscriber(
+	url string,
+	logger *zap.Logger,
+	opts ...Option,
+) (*Subscriber, error) {
+	o := newOptions(opts)
+
+	sub, err := wmnats.NewSubscriber(
+		wmnats.SubscriberConfig{
+			URL:         url,
+			NatsOptions: o.natsOptions,
+			JetStream:   o.jetStream,
+		},
+		newLoggerAdapter(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new nats subscriber: %w", err)
+	}
+
+	return &Subscriber{
+		natsSubscriber: sub,
+	}, nil
+}
+
+// Subscribe subscribes to one or more nats subjects, merging
+// their messages into a single subscription.
+func (s Subscriber) Subscribe(channels ...string) (pubsub.Subscription[string, []byte], error) {
+	if len(channels) == 0 {
+		return nil, pubsub.ErrAtLeastOneChannelRequired
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mesChs := make([]<-chan *message.Message, len(channels))
+
+	for i, channel := range channels {
+		mesCh, err := s.natsSubscriber.Subscribe(ctx, channel)
+		if err != nil {
+			cancel()
+
+			return nil, fmt.Errorf("subscribe to %q: %w", channel, err)
+		}
+
+		mesChs[i] = mesCh
+	}
+
+	return newSubscription(mesChs, cancel), nil
+}
+
+// Close closes the nats subscriber.
+func (s Subscriber) Close() error {
+	return s.natsSubscriber.Close()
+}
+
+var _ pubsub.Subscription[string, []byte] = (*Subscription)(nil)
+
+// Subscription represents a stream of events published to nats subjects.
+type Subscription struct {
+	eventCh chan pubsub.Event[string, []byte]
+	closeCh chan struct{}
+
+	// cancels the underlying subject subscriptions.
+	cancel context.CancelFunc
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// newSubscription creates a new subscription, copying the messages of
+// each subject subscription into the shared event channel.
+func newSubscription(
+	mesChs []<-chan *message.Message,
+	cancel context.CancelFunc,
+) *Subscription {
+	sub := &Subscription{
+		eventCh: make(chan pubsub.Event[string, []byte], len(mesChs)),
+		closeCh: make(chan struct{}),
+		cancel:  cancel,
+	}
+
+	sub.wg.Add(len(mesChs))
+
+	for _, mesCh := range mesChs {
+		go func(mesCh <-chan *message.Message) {
+			defer sub.wg.Done()
+
+			sub.forward(mesCh)
+		}(mesCh)
+	}
+
+	return sub
+}
+
+// forward sends the messages of mesCh as events
+// until the subscription is closed.
+func (s *Subscription) forward(mesCh <-chan *message.Message) {
+	for {
+		select {
+		case <-s.closeCh:
+			return
+		case mes, ok := <-mesCh:
+			if !ok {
+				return
+			}
+
+			headers := make(map[string]string, len(mes.Metadata))
+
+			for k, v := range mes.Metadata {
+				if k != "type" {
+					headers[k] = v
+				}
+			}
+
+			event := pubsub.Event[string, []byte]{
+				Type:         mes.Metadata.Get("type"),
+				Payload:      mes.Payload,
+				Headers:      headers,
+				ID:           mes.UUID,
+				ReceivedAt:   time.Now(),
+				Acknowledger: mes,
+			}
+
+			select {
+			case s.eventCh <- event:
+			case <-s.closeCh:
+				mes.Nack()
+				return
+			}
+		}
+	}
+}
+
+// C returns a receive-only go channel of events published.
+func (s *Subscription) C() <-chan pubsub.Event[string, []byte] {
+	return s.eventCh
+}
+
+// Close cancels the subject subscriptions and closes the event channel,
+// nacking the buffered events that were not received.
+// It can be called multiple times.
+func (s *Subscription) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		s.cancel()
+		s.wg.Wait()
+
+		close(s.eventCh)
+
+		for e := range s.eventCh {
+			e.Nack()
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package nats_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/nats"
+	"go.uber.org/zap"
+)
+
+func TestPubSub(t *testing.T) {
+	url := os.Getenv("NATS_URL")
+	if url == "" {
+		t.Skip("NATS_URL is not set")
+	}
+
+	logger := zap.NewExample()
+
+	// nolint: gocritic, revive
+	is := is.New(t)
+
+	suber, err := nats.NewSubscriber(url, logger)
+	is.NoErr(err)
+
+	t.Cleanup(func() { is.NoErr(suber.Close()) })
+
+	pub, err := nats.NewPublisher(url, logger)
+	is.NoErr(err)
+
+	t.Cleanup(func() { is.NoErr(pub.Close()) })
+
+	sub, err := suber.Subscribe("test.users", "test.orders")
+	is.NoErr(err)
+
+	t.Cleanup(func() { is.NoErr(sub.Close()) })
+
+	for _, subject := range []string{"test.users", "test.orders"} {
+		err = pub.Publish(pubsub.Event[string, []byte]{
+			Type:    subject,
+			Payload: []byte("test"),
+		}, subject)
+		is.NoErr(err)
+	}
+
+	received := make(map[string]bool)
+
+	for len(received) < 2 {
+		select {
+		case e := <-sub.C():
+			is.Equal([]byte("test"), e.Payload)
+			is.True(e.Ack())
+
+			received[e.Type] = true
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
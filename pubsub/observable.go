@@ -0,0 +1,154 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const observableTracerName = "github.com/purposeinplay/go-commons/pubsub"
+
+// ObservableOption configures the observable subscription.
+type ObservableOption[T, P any] func(*observableOptions[T, P])
+
+type observableOptions[T, P any] struct {
+	topic    string
+	spanName func(Event[T, P]) string
+}
+
+// WithSpanNameFormatter sets the func naming the span of an event.
+// Defaults to "<topic> receive", or "receive" if no topic was set.
+func WithSpanNameFormatter[T, P any](f func(Event[T, P]) string) ObservableOption[T, P] {
+	return func(o *observableOptions[T, P]) {
+		o.spanName = f
+	}
+}
+
+// WithSpanTopic sets the topic recorded on the spans, as the events
+// don't carry the channel they were published in.
+func WithSpanTopic[T, P any](topic string) ObservableOption[T, P] {
+	return func(o *observableOptions[T, P]) {
+		o.topic = topic
+	}
+}
+
+// NewObservableSubscription returns a Subscription that starts a
+// consumer span, using a tracer of tp, for every event of sub.
+//
+// The parent of the span is extracted from the event headers, using
+// the W3C trace context propagator, if present.
+// The span ends once the event is acked or nacked. Events without an
+// Acknowledger and events carrying an error end their span as soon as
+// they are delivered.
+func NewObservableSubscription[T, P any](
+	sub Subscription[T, P],
+	tp trace.TracerProvider,
+	opts ...ObservableOption[T, P],
+) Subscription[T, P] {
+	var o observableOptions[T, P]
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.spanName == nil {
+		name := "receive"
+
+		if o.topic != "" {
+			name = o.topic + " " + name
+		}
+
+		o.spanName = func(Event[T, P]) string { return name }
+	}
+
+	tracer := tp.Tracer(observableTracerName)
+	propagator := propagation.TraceContext{}
+
+	return newForwardSubscription(
+		sub,
+		func(ctx context.Context, e Event[T, P], send func(Event[T, P]) bool) {
+			ctx = propagator.Extract(ctx, propagation.MapCarrier(e.Headers))
+
+			attrs := []attribute.KeyValue{
+				attribute.String("messaging.operation", "receive"),
+			}
+
+			if e.ID != "" {
+				attrs = append(attrs, attribute.String("messaging.message.id", e.ID))
+			}
+
+			if o.topic != "" {
+				attrs = append(attrs, attribute.String("messaging.destination.name", o.topic))
+			}
+
+			_, span := tracer.Start(
+				ctx,
+				o.spanName(e),
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(attrs...),
+			)
+
+			if e.Error != nil || e.Acknowledger == nil {
+				if e.Error != nil {
+					span.RecordError(e.Error)
+					span.SetStatus(codes.Error, e.Error.Error())
+				}
+
+				span.End()
+
+				send(e)
+
+				return
+			}
+
+			e.Acknowledger = &spanAcknowledger{
+				ack:  e.Acknowledger,
+				span: span,
+			}
+
+			if !send(e) {
+				// the event will never be settled by the consumer.
+				span.End()
+			}
+		},
+	)
+}
+
+var _ Acknowledger = (*spanAcknowledger)(nil)
+
+// spanAcknowledger ends the span of an event once it is settled.
+type spanAcknowledger struct {
+	ack  Acknowledger
+	span trace.Span
+	once sync.Once
+}
+
+func (a *spanAcknowledger) Ack() bool {
+	acked := a.ack.Ack()
+
+	a.end(false)
+
+	return acked
+}
+
+func (a *spanAcknowledger) Nack() bool {
+	nacked := a.ack.Nack()
+
+	a.end(true)
+
+	return nacked
+}
+
+func (a *spanAcknowledger) end(nack bool) {
+	a.once.Do(func() {
+		if nack {
+			a.span.SetStatus(codes.Error, "nacked")
+		}
+
+		a.span.End()
+	})
+}
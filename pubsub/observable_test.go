@@ -0,0 +1,89 @@
+package pubsub_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestObservableSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ps := inmem.NewPubSub[string, int](10)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	observableSub := pubsub.NewObservableSubscription(
+		sub,
+		tp,
+		pubsub.WithSpanTopic[string, int]("a"),
+		pubsub.WithSpanNameFormatter(func(e pubsub.Event[string, int]) string {
+			return "process " + e.Type
+		}),
+	)
+	t.Cleanup(func() { i.NoErr(observableSub.Close()) })
+
+	// inject a remote parent in the headers of the event.
+	parentCtx, parent := tp.Tracer("test").Start(context.Background(), "publish")
+	parent.End()
+
+	headers := make(map[string]string)
+
+	propagation.TraceContext{}.Inject(parentCtx, propagation.MapCarrier(headers))
+
+	ack := new(testAcknowledger)
+
+	i.NoErr(ps.Publish(pubsub.Event[string, int]{
+		Type:         "created",
+		Payload:      1,
+		Headers:      headers,
+		ID:           "1",
+		Acknowledger: ack,
+	}, "a"))
+
+	e := <-observableSub.C()
+	i.Equal(1, e.Payload)
+
+	// the span is ended only once the event is settled.
+	i.Equal(1, len(recorder.Ended()))
+
+	i.True(e.Nack())
+	i.Equal(int32(1), ack.nacks.Load())
+
+	ended := recorder.Ended()
+	i.Equal(2, len(ended))
+
+	span := ended[1]
+	i.Equal("process created", span.Name())
+	i.Equal(trace.SpanKindConsumer, span.SpanKind())
+	i.Equal(parent.SpanContext().TraceID(), span.Parent().TraceID())
+	i.Equal(parent.SpanContext().SpanID(), span.Parent().SpanID())
+	i.Equal(codes.Error, span.Status().Code)
+
+	attrs := make(map[string]string)
+
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.AsString()
+	}
+
+	i.Equal("1", attrs["messaging.message.id"])
+	i.Equal("a", attrs["messaging.destination.name"])
+
+	// settling the event again doesn't end the span twice.
+	i.True(e.Ack())
+	i.Equal(2, len(recorder.Ended()))
+}
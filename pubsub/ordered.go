@@ -0,0 +1,208 @@
+package pubsub
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+)
+
+// orderedQueueSize is the number of events waiting for each worker
+// of an ordered subscription before the dispatching blocks.
+const orderedQueueSize = 16
+
+var _ Subscription[string, any] = (*orderedSubscription[string, any])(nil)
+
+// orderedSubscription delivers the events of an inner subscription
+// holding back the events of a key until the previous one is settled.
+type orderedSubscription[T, P any] struct {
+	inner Subscription[T, P]
+
+	eventCh chan Event[T, P]
+	queues  []chan Event[T, P]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewOrderedSubscription returns a Subscription that delivers the
+// events of sub with the same key, as returned by keyFunc, in order:
+// an event is delivered only after the previous event with the same key
+// was acked or nacked. The events with different keys are delivered
+// independently.
+//
+// The keys are hashed to one of the workers, each worker waiting for a
+// single event at a time. Events carrying an error and events without
+// an Acknowledger are delivered without waiting for them.
+//
+// Closing the subscription nacks the events not settled yet.
+func NewOrderedSubscription[T, P any](
+	sub Subscription[T, P],
+	keyFunc func(Event[T, P]) string,
+	workers int,
+) Subscription[T, P] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &orderedSubscription[T, P]{
+		inner:   sub,
+		eventCh: make(chan Event[T, P]),
+		queues:  make([]chan Event[T, P], workers),
+		cancel:  cancel,
+	}
+
+	for i := range s.queues {
+		s.queues[i] = make(chan Event[T, P], orderedQueueSize)
+	}
+
+	var workersWG sync.WaitGroup
+
+	workersWG.Add(workers)
+
+	for _, queue := range s.queues {
+		go func() {
+			defer workersWG.Done()
+
+			s.work(ctx, queue)
+		}()
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+		defer close(s.eventCh)
+
+		s.dispatch(ctx, keyFunc)
+
+		for _, queue := range s.queues {
+			close(queue)
+		}
+
+		workersWG.Wait()
+	}()
+
+	return s
+}
+
+// dispatch routes the events of the inner subscription to the worker
+// of their key, until the inner subscription ends or ctx is done.
+func (s *orderedSubscription[T, P]) dispatch(ctx context.Context, keyFunc func(Event[T, P]) string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case e, ok := <-s.inner.C():
+			if !ok {
+				return
+			}
+
+			if e.Error != nil {
+				s.send(ctx, e)
+
+				continue
+			}
+
+			h := fnv.New32a()
+
+			_, _ = h.Write([]byte(keyFunc(e)))
+
+			select {
+			case s.queues[h.Sum32()%uint32(len(s.queues))] <- e:
+			case <-ctx.Done():
+				e.Nack()
+
+				return
+			}
+		}
+	}
+}
+
+// work delivers the events of queue one at a time, waiting for each
+// to be settled before delivering the next one.
+// Once ctx is done the remaining events are nacked.
+func (s *orderedSubscription[T, P]) work(ctx context.Context, queue <-chan Event[T, P]) {
+	for e := range queue {
+		if ctx.Err() != nil {
+			e.Nack()
+
+			continue
+		}
+
+		if e.Acknowledger == nil {
+			s.send(ctx, e)
+
+			continue
+		}
+
+		ack := newSettlement()
+
+		inner := e
+		e.Acknowledger = ack
+
+		if !s.send(ctx, e) {
+			inner.Nack()
+
+			continue
+		}
+
+		select {
+		case acked := <-ack.result:
+			settle(inner, acked)
+
+		case <-ctx.Done():
+			// keep the settlement made before the subscription was closed.
+			select {
+			case acked := <-ack.result:
+				settle(inner, acked)
+			default:
+				inner.Nack()
+			}
+		}
+	}
+}
+
+func settle[T, P any](e Event[T, P], acked bool) {
+	if acked {
+		e.Ack()
+	} else {
+		e.Nack()
+	}
+}
+
+// send reports false if the subscription was closed
+// before the event was received.
+func (s *orderedSubscription[T, P]) send(ctx context.Context, e Event[T, P]) bool {
+	select {
+	case s.eventCh <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// C returns a receive-only go channel of the ordered events.
+func (s *orderedSubscription[T, P]) C() <-chan Event[T, P] {
+	return s.eventCh
+}
+
+// Close stops delivering events, nacking the ones not settled yet,
+// and closes the inner subscription.
+// Safe to call multiple times.
+func (s *orderedSubscription[T, P]) Close() error {
+	s.closeOnce.Do(func() {
+		s.cancel()
+
+		s.closeErr = s.inner.Close()
+
+		s.wg.Wait()
+	})
+
+	return s.closeErr
+}
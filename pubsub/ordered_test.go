@@ -0,0 +1,79 @@
+package pubsub_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestOrderedSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	broker := inmem.NewBroker()
+
+	sub, err := broker.Subscriber().Subscribe("a")
+	i.NoErr(err)
+
+	// the keys a and b are routed to different workers.
+	orderedSub := pubsub.NewOrderedSubscription(sub, func(e pubsub.Event[string, []byte]) string {
+		return e.Type
+	}, 2)
+	t.Cleanup(func() { i.NoErr(orderedSub.Close()) })
+
+	acks := make(map[string]*testAcknowledger)
+
+	for _, id := range []string{"a1", "a2", "b1"} {
+		acks[id] = new(testAcknowledger)
+
+		i.NoErr(broker.Publisher().Publish(pubsub.Event[string, []byte]{
+			Type:         id[:1],
+			ID:           id,
+			Acknowledger: acks[id],
+		}, "a"))
+	}
+
+	received := make(map[string]pubsub.Event[string, []byte])
+
+	for range 2 {
+		e := <-orderedSub.C()
+		received[e.ID] = e
+	}
+
+	// a2 is held back until a1 is settled, without holding back b1.
+	_, ok := received["a1"]
+	i.True(ok)
+
+	_, ok = received["b1"]
+	i.True(ok)
+
+	select {
+	case e := <-orderedSub.C():
+		t.Fatalf("unexpected event: %s", e.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	i.True(received["a1"].Nack())
+
+	e := <-orderedSub.C()
+	i.Equal("a2", e.ID)
+	i.True(e.Ack())
+
+	// the settlements are propagated to the inner events.
+	i.Equal(int32(1), acks["a1"].nacks.Load())
+	i.Equal(int32(0), acks["b1"].acks.Load()+acks["b1"].nacks.Load())
+
+	i.True(received["b1"].Ack())
+
+	i.NoErr(orderedSub.Close())
+
+	_, open := <-orderedSub.C()
+	i.True(!open)
+
+	i.Equal(int32(1), acks["a2"].acks.Load())
+	i.Equal(int32(1), acks["b1"].acks.Load())
+}
@@ -6,8 +6,23 @@
 // to all the subscribers of those channels.
 //
 // Its primary job is to wrap implementations of such PubSub systems,
+//
+// Besides the Payload, the subscribers deliver the metadata of the
+// message the Event was received in: its ID, its Headers and the time
+// it was ReceivedAt. Code reading only the payload is unchanged, the
+// metadata is read from the same Event:
+//
+//	for e := range sub.C() {
+//		log.Printf("message %s received at %s", e.ID, e.ReceivedAt)
+//
+//		process(e.Payload)
+//
+//		e.Ack()
+//	}
 package pubsub
 
+import "time"
+
 // Publisher is the interface that wraps the basic Publish method.
 type Publisher[T, P any] interface {
 	// Publish publishes an event to specified channels.
@@ -51,6 +66,52 @@ type Event[T, P any] struct {
 	// The actual data from the event.
 	Payload P `json:"payload"`
 
+	// Carries metadata of the event alongside the payload,
+	// e.g. signatures or tracing information.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Identifies the message the event was received in, when the
+	// underlying broker provides an identifier.
+	ID string `json:"id,omitempty"`
+
+	// The time the event was received by the subscriber.
+	ReceivedAt time.Time `json:"-"`
+
 	// Carries an error produced by the underlying subscriber.
 	Error error
+
+	// Settles the delivery of the event with the broker it was
+	// received from. It is nil for events that don't require
+	// acknowledgement.
+	Acknowledger Acknowledger `json:"-"`
+}
+
+// Acknowledger is the interface that wraps the methods used to settle
+// the delivery of an event.
+type Acknowledger interface {
+	// Ack marks the event as processed.
+	Ack() bool
+
+	// Nack marks the event as failed, the broker may redeliver it.
+	Nack() bool
+}
+
+// Ack acknowledges the event.
+// It reports false if the event has no Acknowledger.
+func (e Event[T, P]) Ack() bool {
+	if e.Acknowledger == nil {
+		return false
+	}
+
+	return e.Acknowledger.Ack()
+}
+
+// Nack negatively acknowledges the event.
+// It reports false if the event has no Acknowledger.
+func (e Event[T, P]) Nack() bool {
+	if e.Acknowledger == nil {
+		return false
+	}
+
+	return e.Acknowledger.Nack()
 }
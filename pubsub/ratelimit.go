@@ -0,0 +1,37 @@
+package pubsub
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// NewRateLimitedSubscription returns a Subscription that delivers the
+// events of sub no faster than limiter allows.
+//
+// At most one event is held while waiting for the limiter, it is
+// nacked if the subscription is closed before it can be delivered.
+func NewRateLimitedSubscription[T, P any](
+	sub Subscription[T, P],
+	limiter *rate.Limiter,
+) Subscription[T, P] {
+	return newForwardSubscription(
+		sub,
+		func(ctx context.Context, e Event[T, P], send func(Event[T, P]) bool) {
+			// fails only when the subscription is closed.
+			if err := limiter.Wait(ctx); err != nil {
+				e.Nack()
+
+				return
+			}
+
+			send(e)
+		},
+	)
+}
+
+// NewTokenBucketLimiter returns a limiter allowing rps events per
+// second, with bursts of up to burst events.
+func NewTokenBucketLimiter(rps float64, burst int) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
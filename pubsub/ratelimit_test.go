@@ -0,0 +1,38 @@
+package pubsub_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestRateLimitedSubscription(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	ps := inmem.NewPubSub[string, []byte](3)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	limitedSub := pubsub.NewRateLimitedSubscription(sub, pubsub.NewTokenBucketLimiter(20, 1))
+	t.Cleanup(func() { i.NoErr(limitedSub.Close()) })
+
+	for range 3 {
+		i.NoErr(ps.Publish(pubsub.Event[string, []byte]{Type: "test"}, "a"))
+	}
+
+	start := time.Now()
+
+	for range 3 {
+		e := <-limitedSub.C()
+		i.Equal("test", e.Type)
+	}
+
+	// the first event uses the burst, the next two wait 50ms each.
+	i.True(time.Since(start) >= 90*time.Millisecond)
+}
@@ -0,0 +1,70 @@
+// Package redisstreams provides a pubsub publisher and subscriber
+// backed by redis streams.
+package redisstreams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/redis/go-redis/v9"
+)
+
+// The fields of the stream entries.
+const (
+	fieldType    = "type"
+	fieldPayload = "payload"
+	fieldHeaders = "headers"
+)
+
+var _ pubsub.Publisher[string, []byte] = (*Publisher)(nil)
+
+// Publisher represents a redis streams publisher.
+type Publisher struct {
+	client *redis.Client
+}
+
+// NewPublisher creates a new redis streams publisher.
+func NewPublisher(client *redis.Client) *Publisher {
+	return &Publisher{
+		client: client,
+	}
+}
+
+// Publish appends an event to a redis stream,
+// letting redis generate the entry ID.
+func (p *Publisher) Publish(event pubsub.Event[string, []byte], channels ...string) error {
+	if len(channels) != 1 {
+		return pubsub.ErrExactlyOneChannelAllowed
+	}
+
+	values := map[string]any{
+		fieldType:    event.Type,
+		fieldPayload: event.Payload,
+	}
+
+	if len(event.Headers) > 0 {
+		headers, err := json.Marshal(event.Headers)
+		if err != nil {
+			return fmt.Errorf("marshal headers: %w", err)
+		}
+
+		values[fieldHeaders] = headers
+	}
+
+	if err := p.client.XAdd(context.Background(), &redis.XAddArgs{
+		Stream: channels[0],
+		ID:     "*",
+		Values: values,
+	}).Err(); err != nil {
+		return fmt.Errorf("xadd: %w", err)
+	}
+
+	return nil
+}
+
+// Close is a no-op, the redis client is owned by the caller.
+func (*Publisher) Close() error {
+	return nil
+}
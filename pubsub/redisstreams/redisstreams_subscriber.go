@@ -0,0 +1,229 @@
+package redisstreams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// readCount is the maximum number of entries read at once.
+	readCount = 10
+
+	// readBlock is the maximum duration a read waits for new entries.
+	readBlock = time.Second
+
+	// readRetryInterval is the delay before retrying a failed read.
+	readRetryInterval = time.Second
+)
+
+var _ pubsub.Subscriber[string, []byte] = (*Subscriber)(nil)
+
+// Subscriber represents a redis streams subscriber, reading
+// the streams as a consumer of a consumer group.
+type Subscriber struct {
+	client        *redis.Client
+	consumerGroup string
+	consumer      string
+}
+
+// NewSubscriber creates a new redis streams subscriber reading as the
+// consumer named consumer of consumerGroup.
+func NewSubscriber(client *redis.Client, consumerGroup, consumer string) *Subscriber {
+	return &Subscriber{
+		client:        client,
+		consumerGroup: consumerGroup,
+		consumer:      consumer,
+	}
+}
+
+// Subscribe reads the new entries of one or more redis streams.
+// The streams and the consumer group are created if they don't exist.
+func (s *Subscriber) Subscribe(channels ...string) (pubsub.Subscription[string, []byte], error) {
+	if len(channels) == 0 {
+		return nil, pubsub.ErrAtLeastOneChannelRequired
+	}
+
+	for _, stream := range channels {
+		err := s.client.XGroupCreateMkStream(
+			context.Background(),
+			stream,
+			s.consumerGroup,
+			"$",
+		).Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return nil, fmt.Errorf("create consumer group of %q: %w", stream, err)
+		}
+	}
+
+	return newSubscription(s, channels), nil
+}
+
+// Close is a no-op, the redis client is owned by the caller.
+func (*Subscriber) Close() error {
+	return nil
+}
+
+var _ pubsub.Subscription[string, []byte] = (*Subscription)(nil)
+
+// Subscription represents a stream of events appended to redis streams.
+type Subscription struct {
+	eventCh chan pubsub.Event[string, []byte]
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	closeOnce sync.Once
+}
+
+func newSubscription(s *Subscriber, streams []string) *Subscription {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := &Subscription{
+		eventCh: make(chan pubsub.Event[string, []byte]),
+		cancel:  cancel,
+	}
+
+	// read the entries never delivered to the group, of every stream.
+	args := make([]string, 0, 2*len(streams))
+	args = append(args, streams...)
+
+	for range streams {
+		args = append(args, ">")
+	}
+
+	sub.wg.Add(1)
+
+	go func() {
+		defer sub.wg.Done()
+		defer close(sub.eventCh)
+
+		sub.read(ctx, s, args)
+	}()
+
+	return sub
+}
+
+// read sends the entries of the streams as events
+// until the subscription is closed.
+func (s *Subscription) read(ctx context.Context, sub *Subscriber, streams []string) {
+	send := func(e pubsub.Event[string, []byte]) bool {
+		select {
+		case s.eventCh <- e:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for ctx.Err() == nil {
+		res, err := sub.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    sub.consumerGroup,
+			Consumer: sub.consumer,
+			Streams:  streams,
+			Count:    readCount,
+			Block:    readBlock,
+		}).Result()
+
+		switch {
+		case ctx.Err() != nil:
+			return
+
+		case errors.Is(err, redis.Nil):
+			continue
+
+		case err != nil:
+			if !send(pubsub.Event[string, []byte]{
+				Type:  pubsub.EventTypeError,
+				Error: fmt.Errorf("xreadgroup: %w", err),
+			}) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(readRetryInterval):
+			}
+
+			continue
+		}
+
+		for _, stream := range res {
+			for _, mes := range stream.Messages {
+				if !send(newEvent(sub, stream.Stream, mes)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func newEvent(sub *Subscriber, stream string, mes redis.XMessage) pubsub.Event[string, []byte] {
+	e := pubsub.Event[string, []byte]{
+		ID:         mes.ID,
+		ReceivedAt: time.Now(),
+		Acknowledger: &acknowledger{
+			client:        sub.client,
+			stream:        stream,
+			consumerGroup: sub.consumerGroup,
+			id:            mes.ID,
+		},
+	}
+
+	e.Type, _ = mes.Values[fieldType].(string)
+
+	if payload, ok := mes.Values[fieldPayload].(string); ok {
+		e.Payload = []byte(payload)
+	}
+
+	if headers, ok := mes.Values[fieldHeaders].(string); ok {
+		if err := json.Unmarshal([]byte(headers), &e.Headers); err != nil {
+			e.Error = fmt.Errorf("unmarshal headers: %w", err)
+		}
+	}
+
+	return e
+}
+
+// C returns a receive-only go channel of events appended.
+func (s *Subscription) C() <-chan pubsub.Event[string, []byte] {
+	return s.eventCh
+}
+
+// Close stops reading the streams and closes the event channel.
+// It can be called multiple times.
+func (s *Subscription) Close() error {
+	s.closeOnce.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+	})
+
+	return nil
+}
+
+// acknowledger settles the delivery of a stream entry.
+type acknowledger struct {
+	client        *redis.Client
+	stream        string
+	consumerGroup string
+	id            string
+}
+
+// Ack removes the entry from the pending entries of the consumer group.
+func (a *acknowledger) Ack() bool {
+	return a.client.XAck(context.Background(), a.stream, a.consumerGroup, a.id).Err() == nil
+}
+
+// Nack leaves the entry pending, it can be claimed
+// by another consumer of the group using XCLAIM.
+func (*acknowledger) Nack() bool {
+	return true
+}
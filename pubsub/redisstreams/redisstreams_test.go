@@ -0,0 +1,69 @@
+package redisstreams_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/redisstreams"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestPubSub(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	client := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	t.Cleanup(func() { i.NoErr(client.Close()) })
+
+	suber := redisstreams.NewSubscriber(client, "group", "consumer")
+
+	sub, err := suber.Subscribe("users", "orders")
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	// subscribing again reuses the consumer group.
+	otherSub, err := suber.Subscribe("users")
+	i.NoErr(err)
+	i.NoErr(otherSub.Close())
+
+	pub := redisstreams.NewPublisher(client)
+
+	for _, stream := range []string{"users", "orders"} {
+		err = pub.Publish(pubsub.Event[string, []byte]{
+			Type:    stream,
+			Payload: []byte("test"),
+			Headers: map[string]string{"trace": "1"},
+		}, stream)
+		i.NoErr(err)
+	}
+
+	received := make(map[string]bool)
+
+	for len(received) < 2 {
+		select {
+		case e := <-sub.C():
+			i.NoErr(e.Error)
+			i.Equal([]byte("test"), e.Payload)
+			i.Equal("1", e.Headers["trace"])
+			i.True(e.ID != "")
+			i.True(e.Ack())
+
+			received[e.Type] = true
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+
+	for _, stream := range []string{"users", "orders"} {
+		pending, err := client.XPending(context.Background(), stream, "group").Result()
+		i.NoErr(err)
+		i.Equal(int64(0), pending.Count)
+	}
+}
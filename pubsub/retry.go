@@ -0,0 +1,155 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryExhaustedError is returned by the retry publisher once all the
+// attempts of publishing an event failed.
+type RetryExhaustedError struct {
+	// The number of attempts made.
+	Attempts int
+
+	// The error of the last attempt.
+	Err error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	return fmt.Sprintf("retry exhausted after %d attempts: %s", e.Attempts, e.Err)
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryOption configures the retry publisher.
+type RetryOption func(*retryOptions)
+
+type retryOptions struct {
+	backoff   BackoffOptions
+	jitter    float64
+	retryable func(error) bool
+}
+
+// WithMaxAttempts sets the number of attempts, including the first one,
+// made to publish an event. Defaults to 3.
+func WithMaxAttempts(n int) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff.MaxAttempts = n
+	}
+}
+
+// WithInitialDelay sets the delay before the second attempt, doubled
+// after each attempt. Defaults to 100ms.
+func WithInitialDelay(d time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff.InitialInterval = d
+	}
+}
+
+// WithMaxDelay sets the maximum delay between two attempts.
+// Defaults to 10s.
+func WithMaxDelay(d time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff.MaxInterval = d
+	}
+}
+
+// WithJitter randomizes each delay by up to factor of its value,
+// e.g. 0.2 waits between 80% and 120% of the delay. Defaults to 0.
+func WithJitter(factor float64) RetryOption {
+	return func(o *retryOptions) {
+		o.jitter = factor
+	}
+}
+
+// WithRetryableErrors sets the predicate reporting whether a publishing
+// error is worth retrying. Defaults to retrying all the errors.
+func WithRetryableErrors(predicate func(error) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.retryable = predicate
+	}
+}
+
+// RetryPublisher retries the failed publishing of the events with
+// an exponential backoff.
+type RetryPublisher[T any] struct {
+	pub  Publisher[T, []byte]
+	opts retryOptions
+}
+
+// NewRetryPublisher returns a Publisher that publishes the events with
+// pub, retrying the retryable failures until the attempts are exhausted.
+func NewRetryPublisher[T any](pub Publisher[T, []byte], opts ...RetryOption) *RetryPublisher[T] {
+	o := retryOptions{
+		backoff: BackoffOptions{
+			InitialInterval: 100 * time.Millisecond,
+			MaxInterval:     10 * time.Second,
+			Multiplier:      2,
+			MaxAttempts:     3,
+		},
+		retryable: func(error) bool { return true },
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	o.backoff.MaxAttempts = max(o.backoff.MaxAttempts, 1)
+
+	return &RetryPublisher[T]{
+		pub:  pub,
+		opts: o,
+	}
+}
+
+// Publish publishes the event to the specified channels.
+func (p *RetryPublisher[T]) Publish(event Event[T, []byte], channels ...string) error {
+	return p.PublishContext(context.Background(), event, channels...)
+}
+
+// PublishContext publishes the event to the specified channels,
+// giving up waiting for the next attempt once ctx is done.
+//
+// The errors that are not retryable are returned as is, and a
+// *RetryExhaustedError once all the attempts failed.
+func (p *RetryPublisher[T]) PublishContext(ctx context.Context, event Event[T, []byte], channels ...string) error {
+	for attempt := 1; ; attempt++ {
+		err := p.pub.Publish(event, channels...)
+		if err == nil {
+			return nil
+		}
+
+		if !p.opts.retryable(err) {
+			return err
+		}
+
+		if attempt >= p.opts.backoff.MaxAttempts {
+			return &RetryExhaustedError{
+				Attempts: attempt,
+				Err:      err,
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait retry: %w", ctx.Err())
+
+		case <-time.After(p.delay(attempt)):
+		}
+	}
+}
+
+func (p *RetryPublisher[T]) delay(attempt int) time.Duration {
+	delay := p.opts.backoff.Next(attempt)
+
+	if p.opts.jitter <= 0 {
+		return delay
+	}
+
+	// scales the delay by a random factor in [1-jitter, 1+jitter).
+	return time.Duration(float64(delay) * (1 + p.opts.jitter*(2*rand.Float64()-1)))
+}
@@ -0,0 +1,98 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+func TestRetryPublisher(t *testing.T) {
+	t.Parallel()
+
+	event := pubsub.Event[string, []byte]{Type: "test"}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mock := new(mockPublisher)
+		mock.failures.Store(2)
+
+		pub := pubsub.NewRetryPublisher[string](
+			mock,
+			pubsub.WithInitialDelay(time.Millisecond),
+			pubsub.WithJitter(0.5),
+		)
+
+		i.NoErr(pub.Publish(event, "a"))
+		i.Equal(int32(3), mock.calls.Load())
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mock := new(mockPublisher)
+		mock.failures.Store(10)
+
+		pub := pubsub.NewRetryPublisher[string](
+			mock,
+			pubsub.WithMaxAttempts(4),
+			pubsub.WithInitialDelay(time.Millisecond),
+			pubsub.WithMaxDelay(2*time.Millisecond),
+		)
+
+		err := pub.Publish(event, "a")
+
+		var exhaustedErr *pubsub.RetryExhaustedError
+
+		i.True(errors.As(err, &exhaustedErr))
+		i.Equal(4, exhaustedErr.Attempts)
+		i.True(errors.Is(err, errPublish))
+		i.Equal(int32(4), mock.calls.Load())
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mock := new(mockPublisher)
+		mock.failures.Store(10)
+
+		pub := pubsub.NewRetryPublisher[string](
+			mock,
+			pubsub.WithRetryableErrors(func(err error) bool {
+				return !errors.Is(err, errPublish)
+			}),
+		)
+
+		i.Equal(errPublish, pub.Publish(event, "a"))
+		i.Equal(int32(1), mock.calls.Load())
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		mock := new(mockPublisher)
+		mock.failures.Store(10)
+
+		pub := pubsub.NewRetryPublisher[string](mock, pubsub.WithInitialDelay(time.Hour))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := pub.PublishContext(ctx, event, "a")
+
+		i.True(errors.Is(err, context.DeadlineExceeded))
+		i.Equal(int32(1), mock.calls.Load())
+	})
+}
@@ -0,0 +1,195 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ ScheduleStore = (*inMemoryScheduleStore)(nil)
+
+type inMemoryScheduleStore struct {
+	mu   sync.Mutex
+	msgs map[string]ScheduledMessage
+}
+
+// NewInMemoryScheduleStore returns a ScheduleStore backed by an in memory
+// storage. The scheduled messages are lost when the process exits.
+func NewInMemoryScheduleStore() ScheduleStore {
+	return &inMemoryScheduleStore{
+		msgs: make(map[string]ScheduledMessage),
+	}
+}
+
+func (s *inMemoryScheduleStore) Save(_ context.Context, msg ScheduledMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.msgs[msg.ID] = msg
+
+	return nil
+}
+
+func (s *inMemoryScheduleStore) DueMsgs(_ context.Context, now time.Time) ([]ScheduledMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []ScheduledMessage
+
+	for _, msg := range s.msgs {
+		if !msg.SendAt.After(now) {
+			due = append(due, msg)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].SendAt.Before(due[j].SendAt)
+	})
+
+	return due, nil
+}
+
+// MarkSent removes the message, as sent messages are not kept in memory.
+func (s *inMemoryScheduleStore) MarkSent(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.msgs, id)
+
+	return nil
+}
+
+func (s *inMemoryScheduleStore) Cancel(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.msgs[id]; !ok {
+		return ErrScheduledMessageNotFound
+	}
+
+	delete(s.msgs, id)
+
+	return nil
+}
+
+var _ ScheduleStore = (*postgresScheduleStore)(nil)
+
+// postgresClaimTimeout is how long the messages returned by DueMsgs
+// are not returned again, unless marked sent in the meantime.
+const postgresClaimTimeout = time.Minute
+
+type postgresScheduleStore struct {
+	db *sql.DB
+}
+
+// NewPostgresScheduleStore returns a ScheduleStore backed by
+// the scheduled_messages table of a Postgres database:
+//
+//	CREATE TABLE scheduled_messages (
+//		id            TEXT PRIMARY KEY,
+//		channel       TEXT NOT NULL,
+//		payload       BYTEA NOT NULL,
+//		send_at       TIMESTAMPTZ NOT NULL,
+//		sent_at       TIMESTAMPTZ,
+//		claimed_until TIMESTAMPTZ
+//	);
+//
+// The store can be shared by several schedulers: the due messages are
+// claimed for a minute by the scheduler they are returned to, so a
+// message whose publishing failed is returned again once its claim expires.
+func NewPostgresScheduleStore(db *sql.DB) ScheduleStore {
+	return &postgresScheduleStore{db: db}
+}
+
+func (s *postgresScheduleStore) Save(ctx context.Context, msg ScheduledMessage) error {
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO scheduled_messages (id, channel, payload, send_at) VALUES ($1, $2, $3, $4)`,
+		msg.ID, msg.Channel, msg.Payload, msg.SendAt,
+	); err != nil {
+		return fmt.Errorf("insert: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresScheduleStore) DueMsgs(ctx context.Context, now time.Time) ([]ScheduledMessage, error) {
+	// the rows locked by a concurrent claim are skipped.
+	rows, err := s.db.QueryContext(
+		ctx,
+		`UPDATE scheduled_messages SET claimed_until = $2
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE sent_at IS NULL AND send_at <= $1
+			AND (claimed_until IS NULL OR claimed_until <= $1)
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, channel, payload, send_at`,
+		now, now.Add(postgresClaimTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	defer rows.Close()
+
+	var due []ScheduledMessage
+
+	for rows.Next() {
+		var msg ScheduledMessage
+
+		if err := rows.Scan(&msg.ID, &msg.Channel, &msg.Payload, &msg.SendAt); err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		due = append(due, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows: %w", err)
+	}
+
+	// the rows returned by an update are not ordered.
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].SendAt.Before(due[j].SendAt)
+	})
+
+	return due, nil
+}
+
+func (s *postgresScheduleStore) MarkSent(ctx context.Context, id string) error {
+	if _, err := s.db.ExecContext(
+		ctx,
+		`UPDATE scheduled_messages SET sent_at = now() WHERE id = $1`,
+		id,
+	); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+func (s *postgresScheduleStore) Cancel(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM scheduled_messages WHERE id = $1 AND sent_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrScheduledMessageNotFound
+	}
+
+	return nil
+}
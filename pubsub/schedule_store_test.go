@@ -0,0 +1,81 @@
+package pubsub_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+)
+
+func TestPostgresScheduleStore(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_DSN is not set")
+	}
+
+	i := is.New(t)
+
+	ctx := context.Background()
+
+	db, err := sql.Open("postgres", dsn)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(db.Close()) })
+
+	// the temporary table is visible to its connection only.
+	db.SetMaxOpenConns(1)
+
+	_, err = db.ExecContext(ctx, `CREATE TEMPORARY TABLE scheduled_messages (
+		id            TEXT PRIMARY KEY,
+		channel       TEXT NOT NULL,
+		payload       BYTEA NOT NULL,
+		send_at       TIMESTAMPTZ NOT NULL,
+		sent_at       TIMESTAMPTZ,
+		claimed_until TIMESTAMPTZ
+	)`)
+	i.NoErr(err)
+
+	store := pubsub.NewPostgresScheduleStore(db)
+
+	now := time.Now()
+
+	for _, msg := range []pubsub.ScheduledMessage{
+		{ID: "late", Channel: "a", Payload: []byte("late"), SendAt: now.Add(-time.Minute)},
+		{ID: "early", Channel: "a", Payload: []byte("early"), SendAt: now.Add(-time.Hour)},
+		{ID: "future", Channel: "a", Payload: []byte("future"), SendAt: now.Add(time.Hour)},
+		{ID: "canceled", Channel: "a", Payload: []byte("canceled"), SendAt: now.Add(-time.Hour)},
+	} {
+		i.NoErr(store.Save(ctx, msg))
+	}
+
+	i.NoErr(store.Cancel(ctx, "canceled"))
+	i.True(errors.Is(store.Cancel(ctx, "canceled"), pubsub.ErrScheduledMessageNotFound))
+
+	due, err := store.DueMsgs(ctx, now)
+	i.NoErr(err)
+	i.Equal(2, len(due))
+	i.Equal("early", due[0].ID)
+	i.Equal("late", due[1].ID)
+	i.Equal("early", string(due[0].Payload))
+
+	// the claimed messages are not returned to another scheduler.
+	due, err = store.DueMsgs(ctx, now)
+	i.NoErr(err)
+	i.Equal(0, len(due))
+
+	i.NoErr(store.MarkSent(ctx, "early"))
+
+	// once the claim expires, the messages not sent are returned again.
+	due, err = store.DueMsgs(ctx, now.Add(2*time.Minute))
+	i.NoErr(err)
+	i.Equal(1, len(due))
+	i.Equal("late", due[0].ID)
+
+	i.True(errors.Is(store.Cancel(ctx, "early"), pubsub.ErrScheduledMessageNotFound))
+}
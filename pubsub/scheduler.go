@@ -0,0 +1,190 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventTypeScheduled is used as type for the events published by a Scheduler.
+var EventTypeScheduled = "scheduled"
+
+// ScheduledMessage is a message waiting to be published at SendAt.
+type ScheduledMessage struct {
+	ID      string
+	Channel string
+	Payload []byte
+	SendAt  time.Time
+}
+
+// ScheduleStore persists the scheduled messages.
+type ScheduleStore interface {
+	// Save persists a new scheduled message.
+	Save(ctx context.Context, msg ScheduledMessage) error
+
+	// DueMsgs returns the messages not sent yet that are due at now,
+	// ordered by their send time.
+	//
+	// A store shared by several schedulers must not return the same
+	// message to two of them, e.g. by claiming the returned messages.
+	DueMsgs(ctx context.Context, now time.Time) ([]ScheduledMessage, error)
+
+	// MarkSent marks the message as sent so it's not returned by DueMsgs.
+	MarkSent(ctx context.Context, id string) error
+
+	// Cancel removes a message that was not sent yet.
+	// It returns ErrScheduledMessageNotFound otherwise.
+	Cancel(ctx context.Context, id string) error
+}
+
+// SchedulerOption configures the Scheduler.
+type SchedulerOption func(*schedulerOptions)
+
+type schedulerOptions struct {
+	onError func(error)
+}
+
+// WithOnSchedulerError sets a callback called with the errors of the
+// background poller, e.g. when the due messages can't be fetched from
+// the store or published. By default the errors are ignored.
+func WithOnSchedulerError(f func(error)) SchedulerOption {
+	return func(o *schedulerOptions) {
+		o.onError = f
+	}
+}
+
+// Scheduler publishes messages at a future time.
+//
+// The messages are persisted in a ScheduleStore which is polled
+// in the background. A message whose publishing fails is retried
+// once returned again by the store, on a next poll.
+type Scheduler struct {
+	pub   Publisher[string, []byte]
+	store ScheduleStore
+	opts  schedulerOptions
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// NewScheduler creates a Scheduler and starts polling the store
+// every poll interval, which must be positive.
+func NewScheduler(
+	pub Publisher[string, []byte],
+	store ScheduleStore,
+	poll time.Duration,
+	opts ...SchedulerOption,
+) (*Scheduler, error) {
+	if poll <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPollInterval, poll)
+	}
+
+	o := schedulerOptions{
+		onError: func(error) {},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &Scheduler{
+		pub:    pub,
+		store:  store,
+		opts:   o,
+		cancel: cancel,
+	}
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case now := <-ticker.C:
+				s.publishDue(ctx, now)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Schedule persists a message to be published on the channel
+// at sendAt and returns its ID.
+func (s *Scheduler) Schedule(
+	ctx context.Context,
+	channel string,
+	payload []byte,
+	sendAt time.Time,
+) (string, error) {
+	msg := ScheduledMessage{
+		ID:      uuid.NewString(),
+		Channel: channel,
+		Payload: payload,
+		SendAt:  sendAt,
+	}
+
+	if err := s.store.Save(ctx, msg); err != nil {
+		return "", fmt.Errorf("save scheduled message: %w", err)
+	}
+
+	return msg.ID, nil
+}
+
+// Cancel cancels a message that was not published yet.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	if err := s.store.Cancel(ctx, id); err != nil {
+		return fmt.Errorf("cancel scheduled message: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops the background poller.
+// Safe to use concurrently and can be called multiple times.
+func (s *Scheduler) Close() error {
+	s.once.Do(func() {
+		s.cancel()
+		s.wg.Wait()
+	})
+
+	return nil
+}
+
+func (s *Scheduler) publishDue(ctx context.Context, now time.Time) {
+	msgs, err := s.store.DueMsgs(ctx, now)
+	if err != nil {
+		s.opts.onError(fmt.Errorf("get due messages: %w", err))
+
+		return
+	}
+
+	for _, msg := range msgs {
+		if err := s.pub.Publish(Event[string, []byte]{
+			Type:    EventTypeScheduled,
+			Payload: msg.Payload,
+		}, msg.Channel); err != nil {
+			s.opts.onError(fmt.Errorf("publish scheduled message %s: %w", msg.ID, err))
+
+			continue
+		}
+
+		// a message that is not marked gets published again.
+		if err := s.store.MarkSent(ctx, msg.ID); err != nil {
+			s.opts.onError(fmt.Errorf("mark scheduled message %s sent: %w", msg.ID, err))
+		}
+	}
+}
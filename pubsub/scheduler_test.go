@@ -0,0 +1,111 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	ctx := context.Background()
+
+	ps := inmem.NewPubSub[string, []byte](2)
+
+	sub, err := ps.Subscribe("a")
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(sub.Close()) })
+
+	scheduler, err := pubsub.NewScheduler(ps, pubsub.NewInMemoryScheduleStore(), 10*time.Millisecond)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(scheduler.Close()) })
+
+	canceledID, err := scheduler.Schedule(ctx, "a", []byte("canceled"), time.Now().Add(50*time.Millisecond))
+	i.NoErr(err)
+	i.NoErr(scheduler.Cancel(ctx, canceledID))
+
+	err = scheduler.Cancel(ctx, canceledID)
+	i.True(errors.Is(err, pubsub.ErrScheduledMessageNotFound))
+
+	_, err = scheduler.Schedule(ctx, "a", []byte("due"), time.Now().Add(20*time.Millisecond))
+	i.NoErr(err)
+
+	select {
+	case e := <-sub.C():
+		i.Equal(pubsub.EventTypeScheduled, e.Type)
+		i.Equal("due", string(e.Payload))
+
+	case <-time.After(time.Second):
+		t.Fatal("scheduled message not published")
+	}
+
+	select {
+	case e := <-sub.C():
+		t.Fatalf("unexpected event: %s", e.Payload)
+
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestSchedulerInvalidPollInterval(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	_, err := pubsub.NewScheduler(inmem.NewPubSub[string, []byte](0), pubsub.NewInMemoryScheduleStore(), 0)
+	i.True(errors.Is(err, pubsub.ErrInvalidPollInterval))
+}
+
+func TestSchedulerOnError(t *testing.T) {
+	t.Parallel()
+
+	i := is.New(t)
+
+	errPublish := errors.New("publish error")
+
+	errCh := make(chan error, 1)
+
+	scheduler, err := pubsub.NewScheduler(
+		failingPublisher{err: errPublish},
+		pubsub.NewInMemoryScheduleStore(),
+		10*time.Millisecond,
+		pubsub.WithOnSchedulerError(func(err error) {
+			select {
+			case errCh <- err:
+			default:
+			}
+		}),
+	)
+	i.NoErr(err)
+
+	t.Cleanup(func() { i.NoErr(scheduler.Close()) })
+
+	_, err = scheduler.Schedule(context.Background(), "a", []byte("due"), time.Now())
+	i.NoErr(err)
+
+	select {
+	case err := <-errCh:
+		i.True(errors.Is(err, errPublish))
+
+	case <-time.After(time.Second):
+		t.Fatal("publish error not reported")
+	}
+}
+
+type failingPublisher struct {
+	err error
+}
+
+func (p failingPublisher) Publish(pubsub.Event[string, []byte], ...string) error {
+	return p.err
+}
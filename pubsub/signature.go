@@ -0,0 +1,93 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+)
+
+// HeaderSignature is the event header holding the hex encoded
+// HMAC-SHA256 signature of the payload.
+const HeaderSignature = "x-signature"
+
+// ErrSignatureInvalid is returned when the signature of an event
+// is missing or does not match its payload.
+var ErrSignatureInvalid = errors.New("signature invalid")
+
+// SignedPublisher signs the payload of every event before
+// publishing it.
+type SignedPublisher[T any] struct {
+	pub Publisher[T, []byte]
+	key []byte
+}
+
+// NewSignedPublisher returns a Publisher that sets the HMAC-SHA256
+// signature of each payload, computed with key, in the
+// HeaderSignature header before publishing the event with pub.
+func NewSignedPublisher[T any](pub Publisher[T, []byte], key []byte) *SignedPublisher[T] {
+	return &SignedPublisher[T]{
+		pub: pub,
+		key: key,
+	}
+}
+
+// Publish signs the event and publishes it to the specified channels.
+func (p *SignedPublisher[T]) Publish(event Event[T, []byte], channels ...string) error {
+	// copy the headers so the caller's map is not mutated.
+	headers := make(map[string]string, len(event.Headers)+1)
+
+	maps.Copy(headers, event.Headers)
+
+	headers[HeaderSignature] = hex.EncodeToString(sign(p.key, event.Payload))
+
+	event.Headers = headers
+
+	return p.pub.Publish(event, channels...)
+}
+
+// NewSignatureVerifyingSubscription returns a Subscription that verifies
+// the signature set by a SignedPublisher on every event received from sub.
+//
+// Events failing the verification are nacked and an event carrying
+// ErrSignatureInvalid is delivered in their place.
+func NewSignatureVerifyingSubscription[T any](
+	sub Subscription[T, []byte],
+	key []byte,
+) Subscription[T, []byte] {
+	return newForwardSubscription(
+		sub,
+		func(_ context.Context, e Event[T, []byte], send func(Event[T, []byte]) bool) {
+			if e.Error != nil {
+				send(e)
+
+				return
+			}
+
+			signature, err := hex.DecodeString(e.Headers[HeaderSignature])
+			if err != nil || !hmac.Equal(signature, sign(key, e.Payload)) {
+				e.Nack()
+
+				send(Event[T, []byte]{
+					Type:  e.Type,
+					Error: ErrSignatureInvalid,
+				})
+
+				return
+			}
+
+			send(e)
+		},
+	)
+}
+
+func sign(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+
+	// nolint: errcheck // hash.Hash never returns an error.
+	_, _ = mac.Write(payload)
+
+	return mac.Sum(nil)
+}
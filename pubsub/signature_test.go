@@ -0,0 +1,81 @@
+package pubsub_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestSignature(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](1)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		verifyingSub := pubsub.NewSignatureVerifyingSubscription(sub, []byte("key"))
+		t.Cleanup(func() { i.NoErr(verifyingSub.Close()) })
+
+		headers := map[string]string{"trace": "1"}
+
+		err = pubsub.NewSignedPublisher[string](ps, []byte("key")).Publish(pubsub.Event[string, []byte]{
+			Type:    "test",
+			Payload: []byte("test"),
+			Headers: headers,
+		}, "a")
+		i.NoErr(err)
+
+		e := <-verifyingSub.C()
+		i.NoErr(e.Error)
+		i.Equal("test", string(e.Payload))
+		i.Equal("1", e.Headers["trace"])
+		i.True(e.Headers[pubsub.HeaderSignature] != "")
+		i.Equal(1, len(headers))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](2)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		verifyingSub := pubsub.NewSignatureVerifyingSubscription(sub, []byte("key"))
+		t.Cleanup(func() { i.NoErr(verifyingSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		err = pubsub.NewSignedPublisher[string](ps, []byte("other key")).Publish(pubsub.Event[string, []byte]{
+			Type:         "test",
+			Payload:      []byte("test"),
+			Acknowledger: ack,
+		}, "a")
+		i.NoErr(err)
+
+		err = ps.Publish(pubsub.Event[string, []byte]{
+			Type:         "test",
+			Payload:      []byte("unsigned"),
+			Acknowledger: ack,
+		}, "a")
+		i.NoErr(err)
+
+		for range 2 {
+			e := <-verifyingSub.C()
+			i.True(errors.Is(e.Error, pubsub.ErrSignatureInvalid))
+		}
+
+		i.Equal(int32(2), ack.nacks.Load())
+	})
+}
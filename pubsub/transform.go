@@ -0,0 +1,172 @@
+package pubsub
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrCiphertextTooShort is returned when an encrypted payload
+// is shorter than the nonce.
+var ErrCiphertextTooShort = errors.New("ciphertext too short")
+
+// ErrDecompressedPayloadTooLarge is returned when a decompressed
+// payload exceeds the maximum size.
+var ErrDecompressedPayloadTooLarge = errors.New("decompressed payload too large")
+
+// TransformOption configures the transform subscription.
+type TransformOption[T, A any] func(*transformOptions[T, A])
+
+type transformOptions[T, A any] struct {
+	onError func(Event[T, A], error)
+}
+
+// WithOnTransformError sets a callback called with the original event
+// and the error whenever transforming its payload fails.
+func WithOnTransformError[T, A any](f func(Event[T, A], error)) TransformOption[T, A] {
+	return func(o *transformOptions[T, A]) {
+		o.onError = f
+	}
+}
+
+// NewTransformSubscription returns a Subscription that applies transform
+// to the payload of every event received from sub.
+//
+// If transform fails, the original event is nacked and an event
+// carrying the error is delivered in its place.
+// Events that already carry an error are forwarded without being
+// transformed.
+func NewTransformSubscription[T, A, B any](
+	sub Subscription[T, A],
+	transform func(context.Context, A) (B, error),
+	opts ...TransformOption[T, A],
+) Subscription[T, B] {
+	o := transformOptions[T, A]{
+		onError: func(Event[T, A], error) {},
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return newForwardSubscription(
+		sub,
+		func(ctx context.Context, e Event[T, A], send func(Event[T, B]) bool) {
+			if e.Error != nil {
+				send(Event[T, B]{
+					Type:         e.Type,
+					Headers:      e.Headers,
+					ID:           e.ID,
+					ReceivedAt:   e.ReceivedAt,
+					Error:        e.Error,
+					Acknowledger: e.Acknowledger,
+				})
+
+				return
+			}
+
+			payload, err := transform(ctx, e.Payload)
+			if err != nil {
+				e.Nack()
+
+				o.onError(e, err)
+
+				send(Event[T, B]{
+					Type:       e.Type,
+					Headers:    e.Headers,
+					ID:         e.ID,
+					ReceivedAt: e.ReceivedAt,
+					Error:      fmt.Errorf("transform payload: %w", err),
+				})
+
+				return
+			}
+
+			send(Event[T, B]{
+				Type:         e.Type,
+				Payload:      payload,
+				Headers:      e.Headers,
+				ID:           e.ID,
+				ReceivedAt:   e.ReceivedAt,
+				Acknowledger: e.Acknowledger,
+			})
+		},
+	)
+}
+
+// GzipDecompressTransform returns a transform that decompresses
+// gzip encoded payloads.
+//
+// The decompression fails with ErrDecompressedPayloadTooLarge once
+// more than maxSize bytes are decompressed, so a small payload can't
+// exhaust the memory.
+func GzipDecompressTransform(maxSize int64) func(context.Context, []byte) ([]byte, error) {
+	return func(_ context.Context, payload []byte) ([]byte, error) {
+		r, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("new gzip reader: %w", err)
+		}
+
+		defer func() { _ = r.Close() }()
+
+		// the extra byte tells the payloads of maxSize bytes
+		// from the larger ones.
+		decompressed, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+
+		if int64(len(decompressed)) > maxSize {
+			return nil, fmt.Errorf("%w: more than %d bytes", ErrDecompressedPayloadTooLarge, maxSize)
+		}
+
+		return decompressed, nil
+	}
+}
+
+// AES256DecryptTransform returns a transform that decrypts payloads
+// encrypted with AES-256-GCM. The payload is expected to be the nonce
+// followed by the ciphertext.
+//
+// The key must be 32 bytes long, otherwise every call of the
+// transform fails.
+func AES256DecryptTransform(key []byte) func(context.Context, []byte) ([]byte, error) {
+	const keySize = 32
+
+	gcm, gcmErr := func() (cipher.AEAD, error) {
+		if len(key) != keySize {
+			return nil, aes.KeySizeError(len(key))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("new cipher: %w", err)
+		}
+
+		return cipher.NewGCM(block)
+	}()
+
+	return func(_ context.Context, payload []byte) ([]byte, error) {
+		if gcmErr != nil {
+			return nil, gcmErr
+		}
+
+		nonceSize := gcm.NonceSize()
+
+		if len(payload) < nonceSize {
+			return nil, ErrCiphertextTooShort
+		}
+
+		plaintext, err := gcm.Open(nil, payload[:nonceSize], payload[nonceSize:], nil)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt: %w", err)
+		}
+
+		return plaintext, nil
+	}
+}
@@ -0,0 +1,188 @@
+package pubsub_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/matryer/is"
+	"github.com/purposeinplay/go-commons/pubsub"
+	"github.com/purposeinplay/go-commons/pubsub/inmem"
+)
+
+func TestTransformSubscription(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Gzip", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](1)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		transformSub := pubsub.NewTransformSubscription(sub, pubsub.GzipDecompressTransform(1024))
+		t.Cleanup(func() { i.NoErr(transformSub.Close()) })
+
+		var buf bytes.Buffer
+
+		w := gzip.NewWriter(&buf)
+		_, err = w.Write([]byte("test"))
+		i.NoErr(err)
+		i.NoErr(w.Close())
+
+		err = ps.Publish(pubsub.Event[string, []byte]{Type: "test", Payload: buf.Bytes()}, "a")
+		i.NoErr(err)
+
+		e := <-transformSub.C()
+		i.NoErr(e.Error)
+		i.Equal("test", e.Type)
+		i.Equal("test", string(e.Payload))
+	})
+
+	t.Run("GzipMaxSize", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		var buf bytes.Buffer
+
+		w := gzip.NewWriter(&buf)
+		_, err := w.Write(make([]byte, 1<<20))
+		i.NoErr(err)
+		i.NoErr(w.Close())
+
+		decompress := pubsub.GzipDecompressTransform(1 << 10)
+
+		_, err = decompress(context.Background(), buf.Bytes())
+		i.True(errors.Is(err, pubsub.ErrDecompressedPayloadTooLarge))
+
+		decompressed, err := pubsub.GzipDecompressTransform(1<<20)(context.Background(), buf.Bytes())
+		i.NoErr(err)
+		i.Equal(1<<20, len(decompressed))
+	})
+
+	t.Run("AES256", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		i.NoErr(err)
+
+		block, err := aes.NewCipher(key)
+		i.NoErr(err)
+
+		gcm, err := cipher.NewGCM(block)
+		i.NoErr(err)
+
+		nonce := make([]byte, gcm.NonceSize())
+		_, err = rand.Read(nonce)
+		i.NoErr(err)
+
+		decrypt := pubsub.AES256DecryptTransform(key)
+
+		plaintext, err := decrypt(context.Background(), gcm.Seal(nonce, nonce, []byte("test"), nil))
+		i.NoErr(err)
+		i.Equal("test", string(plaintext))
+
+		_, err = pubsub.AES256DecryptTransform([]byte("short"))(context.Background(), nil)
+		i.True(err != nil)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		ps := inmem.NewPubSub[string, []byte](1)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		var transformErrs []error
+
+		transformSub := pubsub.NewTransformSubscription(
+			sub,
+			pubsub.GzipDecompressTransform(1024),
+			pubsub.WithOnTransformError(func(e pubsub.Event[string, []byte], err error) {
+				i.Equal("not gzip", string(e.Payload))
+
+				transformErrs = append(transformErrs, err)
+			}),
+		)
+		t.Cleanup(func() { i.NoErr(transformSub.Close()) })
+
+		ack := new(testAcknowledger)
+
+		err = ps.Publish(pubsub.Event[string, []byte]{
+			Type:         "test",
+			Payload:      []byte("not gzip"),
+			Headers:      map[string]string{"key": "value"},
+			ID:           "id",
+			Acknowledger: ack,
+		}, "a")
+		i.NoErr(err)
+
+		e := <-transformSub.C()
+		i.True(e.Error != nil)
+		i.Equal("id", e.ID)
+		i.Equal("value", e.Headers["key"])
+		i.Equal(int32(1), ack.nacks.Load())
+		i.Equal(1, len(transformErrs))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		t.Parallel()
+
+		i := is.New(t)
+
+		type orderV2 struct {
+			ID     string `json:"id"`
+			Amount int    `json:"amount"`
+		}
+
+		ps := inmem.NewPubSub[string, []byte](1)
+
+		sub, err := ps.Subscribe("a")
+		i.NoErr(err)
+
+		transformSub := pubsub.NewTransformSubscription(sub, pubsub.JSONTransform[orderV2]())
+		t.Cleanup(func() { i.NoErr(transformSub.Close()) })
+
+		err = ps.Publish(pubsub.Event[string, []byte]{
+			Type:    "order",
+			Payload: []byte(`{"id":"1","amount":10,"currency":"EUR"}`),
+		}, "a")
+		i.NoErr(err)
+
+		e := <-transformSub.C()
+		i.NoErr(e.Error)
+		i.Equal(orderV2{ID: "1", Amount: 10}, e.Payload)
+	})
+}
+
+var _ pubsub.Acknowledger = (*testAcknowledger)(nil)
+
+type testAcknowledger struct {
+	acks, nacks atomic.Int32
+}
+
+func (a *testAcknowledger) Ack() bool {
+	a.acks.Add(1)
+	return true
+}
+
+func (a *testAcknowledger) Nack() bool {
+	a.nacks.Add(1)
+	return true
+}